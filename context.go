@@ -2,15 +2,20 @@ package gdutils
 
 import (
 	"crypto/tls"
+	"fmt"
 	"net/http"
+	"os"
 
 	"github.com/pawelWritesCode/gdutils/pkg/cache"
+	"github.com/pawelWritesCode/gdutils/pkg/coverage"
 	"github.com/pawelWritesCode/gdutils/pkg/debugger"
+	"github.com/pawelWritesCode/gdutils/pkg/diff"
 	"github.com/pawelWritesCode/gdutils/pkg/formatter"
 	"github.com/pawelWritesCode/gdutils/pkg/httpctx"
 	"github.com/pawelWritesCode/gdutils/pkg/osutils"
 	"github.com/pawelWritesCode/gdutils/pkg/pathfinder"
 	"github.com/pawelWritesCode/gdutils/pkg/schema"
+	"github.com/pawelWritesCode/gdutils/pkg/snapshot"
 	"github.com/pawelWritesCode/gdutils/pkg/template"
 	"github.com/pawelWritesCode/gdutils/pkg/validator"
 )
@@ -38,8 +43,46 @@ type APIContext struct {
 	// Formatters are entities that has ability to format data in particular format.
 	Formatters Formatters
 
+	// CoverageRecorder is entity that has ability to record sent requests against OpenAPI specification.
+	// It is optional and preserved between scenarios.
+	CoverageRecorder coverage.Recorder
+
+	// Snapshots is store of snapshots used by snapshot assertions. It is preserved between scenarios.
+	Snapshots snapshot.Store
+
+	// Differ is entity that describes differences between expected and actual values in assertions errors.
+	Differ diff.Differ
+
+	// featureURI, scenarioName and exampleKey identify currently run scenario, snapshots are named after them.
+	featureURI   string
+	scenarioName string
+	exampleKey   string
+
+	// snapshotIndex is number of snapshot assertions made in current scenario.
+	snapshotIndex int
+
+	// softAssertions tells whether failed assertions are recorded instead of returned.
+	softAssertions bool
+
+	// softAssertionFailures are failures recorded in soft assertions mode.
+	softAssertionFailures []error
+
+	// stepDepth is number of currently executed, nested steps.
+	stepDepth int
+
+	// statusCodeBodyExcerptLength is maximum length of last response body excerpt attached to status code assertions errors.
+	statusCodeBodyExcerptLength int
+
 	// fileRecognizer is entity that has ability to recognize file reference.
 	fileRecognizer osutils.FileRecognizer
+
+	// jsonSchemaDir is directory to which inferred JSON schemas are written.
+	jsonSchemaDir string
+
+	// insecureDoer is copy of insecureDoerOf client, which does not verify TLS certificates. It is built once,
+	// so requests sent without TLS verification reuse its connections.
+	insecureDoer   httpctx.RequestDoer
+	insecureDoerOf *http.Client
 }
 
 // Formatters is container for entities that know how to serialize and deserialize data.
@@ -54,7 +97,7 @@ type Formatters struct {
 	XML formatter.Formatter
 }
 
-// SchemaValidators is container for JSON schema validators.
+// SchemaValidators is container for JSON schema and XML Schema validators.
 type SchemaValidators struct {
 	// StringValidator represents entity that has ability to validate document against string of containing schema.
 	StringValidator validator.SchemaValidator
@@ -62,6 +105,13 @@ type SchemaValidators struct {
 	// ReferenceValidator represents entity that has ability to validate document against string with reference
 	// to schema, which may be URL or relative/full OS path for example.
 	ReferenceValidator validator.SchemaValidator
+
+	// XSDStringValidator represents entity that has ability to validate XML document against string containing XML Schema.
+	XSDStringValidator validator.SchemaValidator
+
+	// XSDReferenceValidator represents entity that has ability to validate XML document against XML Schema
+	// passed as relative/full OS path.
+	XSDReferenceValidator validator.SchemaValidator
 }
 
 // PathFinders is container for different data types pathfinders.
@@ -77,7 +127,7 @@ type PathFinders struct {
 }
 
 // NewDefaultAPIContext returns *APIContext with default services.
-// jsonSchemaDir may be empty string or valid full path to directory with JSON schemas.
+// jsonSchemaDir may be empty string or valid full path to directory with JSON schemas and XML Schemas.
 func NewDefaultAPIContext(isDebug bool, jsonSchemaDir string) *APIContext {
 	defaultCache := cache.NewConcurrentCache()
 	defaultHttpClient := &http.Client{Transport: &http.Transport{
@@ -85,8 +135,10 @@ func NewDefaultAPIContext(isDebug bool, jsonSchemaDir string) *APIContext {
 	}}
 
 	jsonSchemaValidators := SchemaValidators{
-		StringValidator:    schema.NewJSONSchemaRawValidator(),
-		ReferenceValidator: schema.NewDefaultJSONSchemaReferenceValidator(jsonSchemaDir),
+		StringValidator:       schema.NewJSONSchemaRawValidator(),
+		ReferenceValidator:    schema.NewDefaultJSONSchemaReferenceValidator(jsonSchemaDir),
+		XSDStringValidator:    schema.NewXSDRawValidator(jsonSchemaDir),
+		XSDReferenceValidator: schema.NewDefaultXSDReferenceValidator(jsonSchemaDir),
 	}
 
 	pathFinders := PathFinders{
@@ -103,7 +155,10 @@ func NewDefaultAPIContext(isDebug bool, jsonSchemaDir string) *APIContext {
 
 	defaultDebugger := debugger.New(isDebug)
 
-	return NewAPIContext(defaultHttpClient, defaultCache, jsonSchemaValidators, pathFinders, formatters, defaultDebugger)
+	apiCtx := NewAPIContext(defaultHttpClient, defaultCache, jsonSchemaValidators, pathFinders, formatters, defaultDebugger)
+	apiCtx.jsonSchemaDir = jsonSchemaDir
+
+	return apiCtx
 }
 
 // NewAPIContext returns *APIContext
@@ -118,6 +173,8 @@ func NewAPIContext(cli *http.Client, c cache.Cache, jv SchemaValidators, p PathF
 		SchemaValidators: jv,
 		PathFinders:      p,
 		Formatters:       f,
+		Snapshots:        snapshot.Store{Differ: diff.New(true)},
+		Differ:           diff.New(true),
 		fileRecognizer:   fileRecognizer,
 	}
 }
@@ -126,6 +183,35 @@ func NewAPIContext(cli *http.Client, c cache.Cache, jv SchemaValidators, p PathF
 func (apiCtx *APIContext) ResetState(isDebug bool) {
 	apiCtx.Cache.Reset()
 	apiCtx.Debugger.Reset(isDebug)
+	apiCtx.snapshotIndex = 0
+	apiCtx.softAssertions = false
+	apiCtx.softAssertionFailures = nil
+	apiCtx.stepDepth = 0
+}
+
+// SetScenario sets currently run scenario: URI of its feature file, its name and key of its example.
+// It should be called in scenario hook, before each scenario, when snapshot assertions are used,
+// because snapshot files are named after scenario. exampleKey distinguishes examples of Scenario Outline
+// and scenarios of the same name within feature file, for example godog pickle ID. It should not change between
+// runs, otherwise new snapshot is created, so values of example row are better choice when scenarios change often.
+func (apiCtx *APIContext) SetScenario(featureURI, name, exampleKey string) {
+	apiCtx.featureURI = featureURI
+	apiCtx.scenarioName = name
+	apiCtx.exampleKey = exampleKey
+	apiCtx.snapshotIndex = 0
+}
+
+// EnableSnapshots sets directory of snapshots used by snapshot assertions.
+// Snapshots are rewritten instead of compared when environment variable GDUTILS_UPDATE_SNAPSHOTS is set to true.
+func (apiCtx *APIContext) EnableSnapshots(dir string) {
+	apiCtx.Snapshots = snapshot.NewStore(dir)
+	apiCtx.Snapshots.Differ = apiCtx.Differ
+}
+
+// SetStatusCodeBodyExcerptLength sets maximum length of last HTTP(s) response body excerpt, which is attached
+// to errors of status code assertions. Zero value, which is default, disables excerpts.
+func (apiCtx *APIContext) SetStatusCodeBodyExcerptLength(length int) {
+	apiCtx.statusCodeBodyExcerptLength = length
 }
 
 // SetDebugger sets new debugger for APIContext.
@@ -158,6 +244,59 @@ func (apiCtx *APIContext) SetSchemaReferenceValidator(j validator.SchemaValidato
 	apiCtx.SchemaValidators.ReferenceValidator = j
 }
 
+// SetXSDStringValidator sets new XML Schema StringValidator for APIContext.
+func (apiCtx *APIContext) SetXSDStringValidator(x validator.SchemaValidator) {
+	apiCtx.SchemaValidators.XSDStringValidator = x
+}
+
+// SetXSDReferenceValidator sets new XML Schema ReferenceValidator for APIContext.
+func (apiCtx *APIContext) SetXSDReferenceValidator(x validator.SchemaValidator) {
+	apiCtx.SchemaValidators.XSDReferenceValidator = x
+}
+
+// SetJSONSchemaDir sets directory to which JSON schemas inferred from responses are written.
+// NewDefaultAPIContext sets it to jsonSchemaDir.
+func (apiCtx *APIContext) SetJSONSchemaDir(dir string) {
+	apiCtx.jsonSchemaDir = dir
+}
+
+// RegisterJSONSchemaFormat registers checker of custom format of JSON schema "format" keyword, for example: iban.
+// Format is available to both schema StringValidator and ReferenceValidator, as long as they implement schema.FormatRegisterer.
+func (apiCtx *APIContext) RegisterJSONSchemaFormat(name string, checker schema.FormatChecker) error {
+	registered := false
+	for _, v := range []validator.SchemaValidator{apiCtx.SchemaValidators.StringValidator, apiCtx.SchemaValidators.ReferenceValidator} {
+		registerer, ok := v.(schema.FormatRegisterer)
+		if !ok {
+			continue
+		}
+
+		if err := registerer.RegisterFormat(name, checker); err != nil {
+			return err
+		}
+
+		registered = true
+	}
+
+	if !registered {
+		return fmt.Errorf("none of JSON schema validators accepts custom formats")
+	}
+
+	return nil
+}
+
+// MapJSONSchemaURL makes JSON schemas under remote URL prefix, referenced directly or by $ref, load from local file
+// or directory, for example: MapJSONSchemaURL("https://example.com/schemas/", "remote") loads
+// https://example.com/schemas/user.json from remote/user.json in JSON schemas directory.
+// Schema ReferenceValidator should implement schema.URLMapper.
+func (apiCtx *APIContext) MapJSONSchemaURL(prefix, localPath string) error {
+	mapper, ok := apiCtx.SchemaValidators.ReferenceValidator.(schema.URLMapper)
+	if !ok {
+		return fmt.Errorf("JSON schema ReferenceValidator does not accept URL mappings")
+	}
+
+	return mapper.MapURL(prefix, localPath)
+}
+
 // SetJSONPathFinder sets new JSON pathfinder for APIContext.
 func (apiCtx *APIContext) SetJSONPathFinder(r pathfinder.PathFinder) {
 	apiCtx.PathFinders.JSON = r
@@ -187,3 +326,70 @@ func (apiCtx *APIContext) SetYAMLFormatter(yd formatter.Formatter) {
 func (apiCtx *APIContext) SetXMLFormatter(xf formatter.Formatter) {
 	apiCtx.Formatters.XML = xf
 }
+
+// SetDiffer sets new differ for APIContext. It is used by snapshots as well.
+// Colored output may be disabled, for example on CI, with SetDiffer(diff.New(false)) or NO_COLOR environment variable.
+func (apiCtx *APIContext) SetDiffer(d diff.Differ) {
+	apiCtx.Differ = d
+	apiCtx.Snapshots.Differ = d
+}
+
+// SetCoverageRecorder sets new OpenAPI coverage Recorder for APIContext.
+func (apiCtx *APIContext) SetCoverageRecorder(r coverage.Recorder) {
+	apiCtx.CoverageRecorder = r
+}
+
+// EnableOpenAPICoverage loads OpenAPI specification from specPath and starts recording
+// every HTTP(s) request sent by APIContext against it.
+// specPath should be full or relative OS path to specification in JSON or YAML format.
+func (apiCtx *APIContext) EnableOpenAPICoverage(specPath string) error {
+	spec, err := coverage.LoadSpecification(specPath)
+	if err != nil {
+		return err
+	}
+
+	apiCtx.SetCoverageRecorder(coverage.NewOpenAPIRecorder(spec))
+
+	return nil
+}
+
+// WriteOpenAPICoverageReport writes OpenAPI coverage report in JSON format to jsonPath and in HTML format to htmlPath.
+// Any of paths may be empty string, then report in that format is not written.
+// Method is meant to be called once, at the end of test suite.
+func (apiCtx *APIContext) WriteOpenAPICoverageReport(jsonPath, htmlPath string) error {
+	if apiCtx.CoverageRecorder == nil {
+		return fmt.Errorf("OpenAPI coverage is not enabled, use EnableOpenAPICoverage or SetCoverageRecorder first")
+	}
+
+	report := apiCtx.CoverageRecorder.Report()
+	writers := []struct {
+		path  string
+		write func(f *os.File) error
+	}{
+		{path: jsonPath, write: func(f *os.File) error { return report.WriteJSON(f) }},
+		{path: htmlPath, write: func(f *os.File) error { return report.WriteHTML(f) }},
+	}
+
+	for _, w := range writers {
+		if w.path == "" {
+			continue
+		}
+
+		f, err := os.Create(w.path)
+		if err != nil {
+			return fmt.Errorf("could not create OpenAPI coverage report file %s, err: %w", w.path, err)
+		}
+
+		err = w.write(f)
+		closeErr := f.Close()
+		if err != nil {
+			return fmt.Errorf("could not write OpenAPI coverage report to %s, err: %w", w.path, err)
+		}
+
+		if closeErr != nil {
+			return fmt.Errorf("could not close OpenAPI coverage report file %s, err: %w", w.path, closeErr)
+		}
+	}
+
+	return nil
+}
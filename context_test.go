@@ -1,11 +1,16 @@
 package gdutils
 
 import (
+	"encoding/json"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"reflect"
 	"testing"
 
 	"github.com/pawelWritesCode/gdutils/pkg/cache"
+	"github.com/pawelWritesCode/gdutils/pkg/coverage"
 	"github.com/pawelWritesCode/gdutils/pkg/debugger"
 	"github.com/pawelWritesCode/gdutils/pkg/formatter"
 	"github.com/pawelWritesCode/gdutils/pkg/pathfinder"
@@ -197,6 +202,70 @@ func TestState_SetSchemaReferenceValidator(t *testing.T) {
 	}
 }
 
+func TestState_RegisterJSONSchemaFormat(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "country.json"), []byte(`{"format": "country-code"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewDefaultAPIContext(false, dir)
+	isCountryCode := func(v interface{}) bool {
+		code, ok := v.(string)
+		return !ok || code == "PL" || code == "DE"
+	}
+
+	if err := s.RegisterJSONSchemaFormat("country-code", isCountryCode); err != nil {
+		t.Fatalf("RegisterJSONSchemaFormat() error = %v", err)
+	}
+
+	if err := s.SchemaValidators.StringValidator.Validate(`"PL"`, `{"format": "country-code"}`); err != nil {
+		t.Errorf("StringValidator.Validate() error = %v", err)
+	}
+
+	if err := s.SchemaValidators.StringValidator.Validate(`"XX"`, `{"format": "country-code"}`); err == nil {
+		t.Errorf("StringValidator.Validate() should return error for invalid country code")
+	}
+
+	if err := s.SchemaValidators.ReferenceValidator.Validate(`"XX"`, "country.json"); err == nil {
+		t.Errorf("ReferenceValidator.Validate() should return error for invalid country code")
+	}
+
+	s.SetSchemaStringValidator(newStringValidator{})
+	s.SetSchemaReferenceValidator(newStringValidator{})
+	if err := s.RegisterJSONSchemaFormat("country-code", isCountryCode); err == nil {
+		t.Errorf("RegisterJSONSchemaFormat() should return error when none of validators accepts custom formats")
+	}
+}
+
+func TestState_MapJSONSchemaURL(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "user.json"), []byte(`{"$ref": "https://example.com/schemas/name.json"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "name.json"), []byte(`{"type": "string"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewDefaultAPIContext(false, dir)
+	if err := s.MapJSONSchemaURL("https://example.com/schemas/", "."); err != nil {
+		t.Fatalf("MapJSONSchemaURL() error = %v", err)
+	}
+
+	if err := s.SchemaValidators.ReferenceValidator.Validate(`"ivo"`, "user.json"); err != nil {
+		t.Errorf("ReferenceValidator.Validate() error = %v", err)
+	}
+
+	if err := s.SchemaValidators.ReferenceValidator.Validate(`1`, "https://example.com/schemas/name.json"); err == nil {
+		t.Errorf("ReferenceValidator.Validate() should return error for invalid document")
+	}
+
+	s.SetSchemaReferenceValidator(newStringValidator{})
+	if err := s.MapJSONSchemaURL("https://example.com/schemas/", "."); err == nil {
+		t.Errorf("MapJSONSchemaURL() should return error when ReferenceValidator does not accept URL mappings")
+	}
+}
+
 func TestState_SetJSONPathFinder(t *testing.T) {
 	s := NewDefaultAPIContext(false, "")
 
@@ -292,3 +361,102 @@ func TestState_SetXMLFormatter(t *testing.T) {
 		t.Errorf("SetXMLFormatter does not work properly")
 	}
 }
+
+func TestState_SetCoverageRecorder(t *testing.T) {
+	s := NewDefaultAPIContext(false, "")
+
+	if s.CoverageRecorder != nil {
+		t.Errorf("default coverage Recorder should be nil")
+	}
+
+	s.SetCoverageRecorder(coverage.NewOpenAPIRecorder(coverage.Specification{}))
+
+	_, isNewRecorder := s.CoverageRecorder.(*coverage.OpenAPIRecorder)
+	if !isNewRecorder {
+		t.Errorf("SetCoverageRecorder does not work properly")
+	}
+}
+
+func TestState_OpenAPICoverage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/1" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "openapi.yaml")
+	spec := `
+openapi: 3.0.0
+paths:
+  /users/{id}:
+    get:
+      responses:
+        "200":
+          description: ok
+        "404":
+          description: not found
+  /users:
+    post:
+      responses:
+        "201":
+          description: created
+`
+	if err := ioutil.WriteFile(specPath, []byte(spec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewDefaultAPIContext(false, "")
+	if err := s.WriteOpenAPICoverageReport(filepath.Join(dir, "report.json"), ""); err == nil {
+		t.Errorf("WriteOpenAPICoverageReport() should fail when coverage is not enabled")
+	}
+
+	if err := s.EnableOpenAPICoverage(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Errorf("EnableOpenAPICoverage() should fail for missing specification")
+	}
+
+	if err := s.EnableOpenAPICoverage(specPath); err != nil {
+		t.Fatalf("EnableOpenAPICoverage() error = %v", err)
+	}
+
+	if err := s.IPrepareNewRequestToAndSaveItAs(http.MethodGet, srv.URL+"/users/1", "REQ"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.ISendRequest("REQ"); err != nil {
+		t.Fatal(err)
+	}
+
+	s.ResetState(false)
+
+	if err := s.ISendRequestToWithBodyAndHeaders(http.MethodGet, srv.URL+"/users/2", `{"body": {}, "headers": {}}`); err != nil {
+		t.Fatal(err)
+	}
+
+	jsonPath, htmlPath := filepath.Join(dir, "report.json"), filepath.Join(dir, "report.html")
+	if err := s.WriteOpenAPICoverageReport(jsonPath, htmlPath); err != nil {
+		t.Fatalf("WriteOpenAPICoverageReport() error = %v", err)
+	}
+
+	data, err := ioutil.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var report coverage.Report
+	if err = json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+
+	if report.OperationsHit != 1 || report.OperationsTotal != 2 || report.StatusCodesHit != 2 || report.StatusCodesTotal != 3 {
+		t.Errorf("unexpected coverage report: %s", data)
+	}
+
+	if _, err = ioutil.ReadFile(htmlPath); err != nil {
+		t.Errorf("HTML report was not written, err: %v", err)
+	}
+}
@@ -15,12 +15,18 @@
 //	func (apiCtx *APIContext) SetTemplateEngine(t template.Engine)
 //	func (apiCtx *APIContext) SetSchemaStringValidator(j validator.SchemaValidator)
 //	func (apiCtx *APIContext) SetSchemaReferenceValidator(j validator.SchemaValidator)
+//	func (apiCtx *APIContext) SetXSDStringValidator(x validator.SchemaValidator)
+//	func (apiCtx *APIContext) SetXSDReferenceValidator(x validator.SchemaValidator)
+//	func (apiCtx *APIContext) RegisterJSONSchemaFormat(name string, checker schema.FormatChecker) error
+//	func (apiCtx *APIContext) MapJSONSchemaURL(prefix, localPath string) error
 //	func (apiCtx *APIContext) SetJSONPathFinder(r pathfinder.PathFinder)
 //	func (apiCtx *APIContext) SetJSONFormatter(jf formatter.Formatter)
 //	func (apiCtx *APIContext) SetXMLPathFinder(r pathfinder.PathFinder)
 //	func (apiCtx *APIContext) SetYAMLPathFinder(r pathfinder.PathFinder)
 //	func (apiCtx *APIContext) SetYAMLFormatter(yd formatter.Formatter)
 //	func (apiCtx *APIContext) SetXMLFormatter(xf formatter.Formatter)
+//	func (apiCtx *APIContext) SetCoverageRecorder(r coverage.Recorder)
+//	func (apiCtx *APIContext) SetDiffer(d diff.Differ)
 //
 // Those services will be used in utility methods.
 // For example, if you want to use your own debugger, create your own struct, implement debugger.Debugger interface on it,
@@ -36,40 +42,101 @@
 //	func (apiCtx *APIContext) IGenerateARandomSentenceInTheRangeFromToWordsAndSaveItAs(charset string, wordMinLength, wordMaxLength int) func(from, to int, cacheKey string) error
 //	func (apiCtx *APIContext) IGetTimeAndTravelByAndSaveItAs(t time.Time, timeDirection timeutils.TimeDirection, timeDuration time.Duration, cacheKey string) error
 //	func (apiCtx *APIContext) IGenerateCurrentTimeAndTravelByAndSaveItAs(timeDirection timeutils.TimeDirection, timeDuration time.Duration, cacheKey string) error
+//	func (apiCtx *APIContext) IParseNodeAsTimeInLayoutAndSaveItAs(dataFormat format.DataFormat, exprTemplate, layout, cacheKey string) error
 //
 // * Sending HTTP(s) requests:
 //
 //	func (apiCtx *APIContext) ISendRequestToWithBodyAndHeaders(method, urlTemplate string, bodyTemplate string) error
+//	func (apiCtx *APIContext) ILoadHTTPClientEnvironmentFromFile(environment, pathTemplate string) error
+//	func (apiCtx *APIContext) ISendRequestFromHTTPFile(requestName, pathTemplate string) error
 //
 // or
 //
 //	func (apiCtx *APIContext) IPrepareNewRequestToAndSaveItAs(method, urlTemplate, cacheKey string) error
+//	func (apiCtx *APIContext) IPrepareNewRequestFromCurlCommandAndSaveItAs(curlTemplate, cacheKey string) error
+//	func (apiCtx *APIContext) IPrepareNewRequestFromHTTPFileAndSaveItAs(requestName, pathTemplate, cacheKey string) error
 //	func (apiCtx *APIContext) ISetFollowingHeadersForPreparedRequest(cacheKey string, headersTemplate string) error
 //	func (apiCtx *APIContext) ISetFollowingFormForPreparedRequest(cacheKey, formTemplate string) error
 //	func (apiCtx *APIContext) ISetFollowingCookiesForPreparedRequest(cacheKey, cookiesTemplate string) error
 //	func (apiCtx *APIContext) ISetFollowingBodyForPreparedRequest(cacheKey string, bodyTemplate string) error
 //	func (apiCtx *APIContext) ISendRequest(cacheKey string) error
+//	func (apiCtx *APIContext) IFollowPaginationOfPreparedRequestAndSaveNodesAs(cacheKey, paginationTemplate string, dataFormat format.DataFormat, exprTemplate string, pageLimit int, resultCacheKey string) error
 //
 // * Assertions:
 //
 //	func (apiCtx *APIContext) TheResponseStatusCodeShouldBe(code int) error
+//	func (apiCtx *APIContext) TheResponseStatusCodeShouldBeOfClass(class string) error
+//	func (apiCtx *APIContext) TheResponseStatusCodeShouldBeOneOf(codes string) error
+//	func (apiCtx *APIContext) TheResponseStatusCodeShouldBeBetween(from, to int) error
+//	func (apiCtx *APIContext) SetStatusCodeBodyExcerptLength(length int)
 //	func (apiCtx *APIContext) TheResponseBodyShouldHaveFormat(dataFormat format.DataFormat) error
 //	func (apiCtx *APIContext) TheResponseShouldHaveCookie(name string) error
 //	func (apiCtx *APIContext) TheResponseShouldHaveCookieOfValue(name, valueTemplate string) error
+//	func (apiCtx *APIContext) TheResponseShouldNotHaveCookie(name string) error
+//	func (apiCtx *APIContext) TheResponseShouldNotHaveCookieOfValue(name, valueTemplate string) error
+//	func (apiCtx *APIContext) TheResponseCookieShouldHaveAttributeOfValue(name string, attribute httpctx.CookieAttribute, valueTemplate string) error
+//	func (apiCtx *APIContext) TheResponseCookieShouldExpireAfter(name string, timeInterval time.Duration) error
+//	func (apiCtx *APIContext) TheResponseCookieShouldExpireBefore(name string, timeInterval time.Duration) error
+//	func (apiCtx *APIContext) TheResponseSessionCookieShouldBeHardened(name string) error
 //	func (apiCtx *APIContext) TheResponseShouldHaveNode(dataFormat format.DataFormat, exprTemplate string) error
+//	func (apiCtx *APIContext) TheResponseShouldNotHaveNode(dataFormat format.DataFormat, exprTemplate string) error
 //	func (apiCtx *APIContext) TheNodeShouldNotBe(df format.DataFormat, exprTemplate string, goType string) error
 //	func (apiCtx *APIContext) TheNodeShouldBe(df format.DataFormat, exprTemplate string, goType string) error
 //	func (apiCtx *APIContext) TheNodeShouldMatchRegExp(dataFormat format.DataFormat, exprTemplate, regExpTemplate string) error
+//	func (apiCtx *APIContext) TheNodeShouldNotMatchRegExp(dataFormat format.DataFormat, exprTemplate, regExpTemplate string) error
+//	func (apiCtx *APIContext) TheResponseShouldSatisfyExpression(expressionTemplate string) error
+//	func (apiCtx *APIContext) TheNodeShouldBeGreaterThan(dataFormat format.DataFormat, exprTemplate, valueTemplate string) error
+//	func (apiCtx *APIContext) TheNodeShouldBeGreaterThanOrEqualTo(dataFormat format.DataFormat, exprTemplate, valueTemplate string) error
+//	func (apiCtx *APIContext) TheNodeShouldBeLessThan(dataFormat format.DataFormat, exprTemplate, valueTemplate string) error
+//	func (apiCtx *APIContext) TheNodeShouldBeLessThanOrEqualTo(dataFormat format.DataFormat, exprTemplate, valueTemplate string) error
+//	func (apiCtx *APIContext) TheNodeShouldBeBetween(dataFormat format.DataFormat, exprTemplate, fromTemplate, toTemplate string) error
+//	func (apiCtx *APIContext) TheNodeShouldBeApproximately(dataFormat format.DataFormat, exprTemplate, valueTemplate, toleranceTemplate string) error
+//	func (apiCtx *APIContext) TheNodeShouldBeDivisibleBy(dataFormat format.DataFormat, exprTemplate, divisorTemplate string) error
+//	func (apiCtx *APIContext) TheNodeShouldBeTimeInLayout(dataFormat format.DataFormat, exprTemplate, layout string) error
+//	func (apiCtx *APIContext) TheNodeShouldBeTimeInLayoutBefore(dataFormat format.DataFormat, exprTemplate, layout, cacheKey string) error
+//	func (apiCtx *APIContext) TheNodeShouldBeTimeInLayoutAfter(dataFormat format.DataFormat, exprTemplate, layout, cacheKey string) error
+//	func (apiCtx *APIContext) TheNodeShouldBeTimeInLayoutWithinOf(dataFormat format.DataFormat, exprTemplate, layout string, timeDuration time.Duration, cacheKey string) error
+//	func (apiCtx *APIContext) TheNodeShouldMatchDocument(dataFormat format.DataFormat, exprTemplate string, mode compare.Mode, documentTemplate, ignoredPathsTemplate string) error
+//	func (apiCtx *APIContext) TheResponseBodyShouldMatchSnapshot(dataFormat format.DataFormat, redactedPathsTemplate string) error
 //	func (apiCtx *APIContext) TheResponseShouldHaveNodes(dataFormat format.DataFormat, expressionsTemplates string) error
+//	func (apiCtx *APIContext) TheResponseShouldNotHaveNodes(dataFormat format.DataFormat, expressionsTemplate string) error
 //	func (apiCtx *APIContext) TheNodeShouldBeSliceOfLength(dataFormat format.DataFormat, exprTemplate string, length int) error
+//	func (apiCtx *APIContext) TheNodeShouldBeSliceOfLengthAtLeast(dataFormat format.DataFormat, exprTemplate string, length int) error
+//	func (apiCtx *APIContext) TheNodeShouldBeSliceOfLengthAtMost(dataFormat format.DataFormat, exprTemplate string, length int) error
+//	func (apiCtx *APIContext) TheNodeShouldContainElement(dataFormat format.DataFormat, exprTemplate, elementTemplate string) error
+//	func (apiCtx *APIContext) EveryElementOfTheNodeShouldHaveNodeOfValue(dataFormat format.DataFormat, exprTemplate, elementExprTemplate, valueTemplate string) error
+//	func (apiCtx *APIContext) AnyElementOfTheNodeShouldHaveNodeOfValue(dataFormat format.DataFormat, exprTemplate, elementExprTemplate, valueTemplate string) error
+//	func (apiCtx *APIContext) TheNodeShouldBeSortedBy(dataFormat format.DataFormat, exprTemplate, elementExprTemplate string, order collection.Order) error
+//	func (apiCtx *APIContext) TheNodeShouldBeUniqueBy(dataFormat format.DataFormat, exprTemplate, elementExprTemplate string) error
 //	func (apiCtx *APIContext) TheNodeShouldBeOfValue(dataFormat format.DataFormat, exprTemplate, dataType, dataValue string) error
+//	func (apiCtx *APIContext) TheNodeShouldNotBeOfValue(dataFormat format.DataFormat, exprTemplate, dataType, dataValue string) error
 //	func (apiCtx *APIContext) TheResponseShouldHaveHeader(name string) error
 //	func (apiCtx *APIContext) TheResponseShouldHaveHeaderOfValue(name, value string) error
+//	func (apiCtx *APIContext) TheResponseShouldNotHaveHeader(name string) error
+//	func (apiCtx *APIContext) TheResponseShouldNotHaveHeaderOfValue(name, valueTemplate string) error
+//	func (apiCtx *APIContext) TheResponseHeaderShouldMatchRegExp(name, regExpTemplate string) error
+//	func (apiCtx *APIContext) TheResponseHeaderShouldContainValue(name, valueTemplate string) error
+//	func (apiCtx *APIContext) TheResponseContentTypeShouldBe(mediaTypeTemplate string) error
+//	func (apiCtx *APIContext) TheResponseHeaderShouldHaveDirective(name, directive string) error
+//	func (apiCtx *APIContext) TheResponseHeaderDirectiveShouldBeOfValue(name, directive, valueTemplate string) error
 //  func (apiCtx *APIContext) IValidateLastResponseBodyWithSchemaReference(referenceTemplate string) error
 //	func (apiCtx *APIContext) IValidateLastResponseBodyWithSchemaString(schemaTemplate string) error
 //	func (apiCtx *APIContext) IValidateNodeWithSchemaString(dataFormat format.DataFormat, exprTemplate, schemaTemplate string) error
 //	func (apiCtx *APIContext) IValidateNodeWithSchemaReference(dataFormat format.DataFormat, exprTemplate, referenceTemplate string) error
+//	func (apiCtx *APIContext) IValidateLastResponseBodyWithXSDReference(referenceTemplate string) error
+//	func (apiCtx *APIContext) IValidateLastResponseBodyWithXSDString(schema string) error
+//	func (apiCtx *APIContext) IValidateXMLNodeWithXSDReference(exprTemplate, referenceTemplate string) error
+//	func (apiCtx *APIContext) IValidateXMLNodeWithXSDString(exprTemplate, schemaTemplate string) error
 //	func (apiCtx *APIContext) TimeBetweenLastHTTPRequestResponseShouldBeLessThanOrEqualTo(timeInterval time.Duration) error
+//	func (apiCtx *APIContext) TimeOfLastHTTPRequestPhaseShouldBeLessThanOrEqualTo(phase httpctx.TimingPhase, timeInterval time.Duration) error
+//	func (apiCtx *APIContext) TheResponseTLSVersionShouldBe(version string) error
+//	func (apiCtx *APIContext) TheResponseTLSVersionShouldBeAtLeast(version string) error
+//	func (apiCtx *APIContext) TheResponseTLSCipherSuiteShouldBe(cipherSuite string) error
+//	func (apiCtx *APIContext) TheResponseCertificateShouldHaveSubject(subjectTemplate string) error
+//	func (apiCtx *APIContext) TheResponseCertificateShouldBeValidForHost(hostTemplate string) error
+//	func (apiCtx *APIContext) TheResponseCertificateShouldBeIssuedBy(issuerTemplate string) error
+//	func (apiCtx *APIContext) TheResponseCertificateShouldBeValidForAtLeastDays(days int) error
+//	func (apiCtx *APIContext) TheResponseShouldHaveStapledOCSPResponse() error
 //
 // * Preserving JSON nodes:
 //
@@ -80,9 +147,39 @@
 //
 //	func (apiCtx *APIContext) IWait(timeInterval time.Duration) error
 //
+// * OpenAPI coverage:
+//
+//	func (apiCtx *APIContext) EnableOpenAPICoverage(specPath string) error
+//	func (apiCtx *APIContext) WriteOpenAPICoverageReport(jsonPath, htmlPath string) error
+//
+// * Snapshots:
+//
+//	func (apiCtx *APIContext) EnableSnapshots(dir string)
+//	func (apiCtx *APIContext) SetScenario(featureURI, name, exampleKey string)
+//
+// * JSON schema inference:
+//
+//	func (apiCtx *APIContext) SetJSONSchemaDir(dir string)
+//	func (apiCtx *APIContext) IGenerateJSONSchemaFromLastResponseBodyAndWriteItTo(fileNameTemplate string) error
+//	func (apiCtx *APIContext) IGenerateJSONSchemaFromSavedResponsesAndWriteItTo(cacheKeysTemplate, fileNameTemplate string) error
+//
+// * Soft assertions:
+//
+//	func (apiCtx *APIContext) IStartSoftAssertions() error
+//	func (apiCtx *APIContext) AllSoftAssertionsShouldPass() error
+//
 // * Debugging:
 //
 //	func (apiCtx *APIContext) IPrintLastResponseBody() error
 //	func (apiCtx *APIContext) IStartDebugMode() error
 //	func (apiCtx *APIContext) IStopDebugMode() error
+//
+// * Errors:
+//
+// Failed assertions return *AssertionError with name of step, path, expected and actual value.
+// Missing last response, template engine and PathFinder problems are returned as *MissingResponseError,
+// *TemplateError and *PathFinderError, remaining errors of steps as *StepError. Errors may be checked with errors.As
+// or errors.Is with ErrAssertion, ErrMissingResponse, ErrTemplate and ErrPathFinder.
+// JSON schema validation steps wrap *schema.ValidationError, which lists every violation with JSON pointer to node,
+// keyword path, expected and actual value. In debug mode excerpts of invalid nodes are printed as well.
 package gdutils
@@ -0,0 +1,293 @@
+package gdutils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrAssertion occurs when assertion is not satisfied. It may be checked with errors.Is.
+	ErrAssertion = errors.New("assertion failed")
+
+	// ErrMissingResponse occurs when last HTTP(s) response or its body could not be obtained.
+	ErrMissingResponse = errors.New("missing last HTTP(s) response")
+
+	// ErrTemplate occurs when template engine could not replace template values.
+	ErrTemplate = errors.New("template engine error")
+
+	// ErrPathFinder occurs when node could not be found with PathFinder.
+	ErrPathFinder = errors.New("path finder error")
+)
+
+// AssertionError describes assertion failure. It may be obtained from error returned by APIContext assertion
+// with errors.As, for example to render rich reports in custom formatter.
+type AssertionError struct {
+	// Step is name of APIContext method, which returned error, for example: TheNodeShouldBeOfValue.
+	Step string
+
+	// Path points at asserted element, for example: node expression, header or cookie name.
+	Path string
+
+	// Expected is expected value, may be nil when assertion does not compare values.
+	Expected interface{}
+
+	// Actual is actual value, may be nil when assertion does not compare values.
+	Actual interface{}
+
+	// Message is human-readable description of failure, Err is described when it is empty.
+	Message string
+
+	// Err is underlying error.
+	Err error
+}
+
+// Error returns description of assertion failure.
+func (e *AssertionError) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+
+	return ErrAssertion.Error()
+}
+
+// Unwrap returns underlying error.
+func (e *AssertionError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrAssertion.
+func (e *AssertionError) Is(target error) bool {
+	return target == ErrAssertion
+}
+
+func (e *AssertionError) setStep(step string) {
+	if e.Step == "" {
+		e.Step = step
+	}
+}
+
+func (e *AssertionError) step() string {
+	return e.Step
+}
+
+// MissingResponseError occurs when last HTTP(s) response or its body could not be obtained,
+// for example because no request was sent yet.
+type MissingResponseError struct {
+	// Step is name of APIContext method, which returned error.
+	Step string
+
+	// Body tells whether response body could not be obtained.
+	Body bool
+
+	// Err is underlying error.
+	Err error
+}
+
+// Error returns description of error.
+func (e *MissingResponseError) Error() string {
+	if e.Body {
+		return fmt.Sprintf("could not obtain last HTTP(s) response body, err: %v", e.Err)
+	}
+
+	return fmt.Sprintf("could not obtain last HTTP(s) response, err: %v", e.Err)
+}
+
+// Unwrap returns underlying error.
+func (e *MissingResponseError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrMissingResponse.
+func (e *MissingResponseError) Is(target error) bool {
+	return target == ErrMissingResponse
+}
+
+func (e *MissingResponseError) setStep(step string) {
+	if e.Step == "" {
+		e.Step = step
+	}
+}
+
+func (e *MissingResponseError) step() string {
+	return e.Step
+}
+
+// TemplateError occurs when template engine could not replace template values in one of step arguments.
+type TemplateError struct {
+	// Step is name of APIContext method, which returned error.
+	Step string
+
+	// Name is name of step argument, for example: value or expression.
+	Name string
+
+	// Err is underlying error.
+	Err error
+}
+
+// Error returns description of error.
+func (e *TemplateError) Error() string {
+	return fmt.Sprintf("template engine has problem with '%s' template, err: %v", e.Name, e.Err)
+}
+
+// Unwrap returns underlying error.
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrTemplate.
+func (e *TemplateError) Is(target error) bool {
+	return target == ErrTemplate
+}
+
+func (e *TemplateError) setStep(step string) {
+	if e.Step == "" {
+		e.Step = step
+	}
+}
+
+func (e *TemplateError) step() string {
+	return e.Step
+}
+
+// PathFinderError occurs when node could not be obtained with PathFinder,
+// because it does not exist, expression is invalid or data is malformed.
+type PathFinderError struct {
+	// Step is name of APIContext method, which returned error.
+	Step string
+
+	// Expr is expression pointing at node.
+	Expr string
+
+	// Err is underlying error.
+	Err error
+}
+
+// Error returns description of error.
+func (e *PathFinderError) Error() string {
+	return fmt.Sprintf("node '%s', err: %v", e.Expr, e.Err)
+}
+
+// Unwrap returns underlying error.
+func (e *PathFinderError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrPathFinder.
+func (e *PathFinderError) Is(target error) bool {
+	return target == ErrPathFinder
+}
+
+func (e *PathFinderError) setStep(step string) {
+	if e.Step == "" {
+		e.Step = step
+	}
+}
+
+func (e *PathFinderError) step() string {
+	return e.Step
+}
+
+// StepError occurs when step could not be performed for reason other than described by remaining errors of this file,
+// for example because of invalid step argument or request that could not be sent.
+type StepError struct {
+	// Step is name of APIContext method, which returned error.
+	Step string
+
+	// Err is underlying error.
+	Err error
+}
+
+// Error returns description of underlying error.
+func (e *StepError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns underlying error.
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+func (e *StepError) setStep(step string) {
+	if e.Step == "" {
+		e.Step = step
+	}
+}
+
+func (e *StepError) step() string {
+	return e.Step
+}
+
+// SoftAssertionsError aggregates failures recorded in soft assertions mode.
+type SoftAssertionsError struct {
+	// Failures are errors of failed assertions, in order of occurrence.
+	Failures []error
+}
+
+// Error returns numbered list of failures with names of failed assertions.
+func (e *SoftAssertionsError) Error() string {
+	report := make([]string, 0, len(e.Failures))
+	for i, failure := range e.Failures {
+		report = append(report, fmt.Sprintf("%d) %s: %s", i+1, stepName(failure), failure.Error()))
+	}
+
+	return fmt.Sprintf("%d soft assertion(s) failed:\n%s", len(e.Failures), strings.Join(report, "\n"))
+}
+
+// Is reports whether target is ErrAssertion or matches any of failures.
+func (e *SoftAssertionsError) Is(target error) bool {
+	if target == ErrAssertion {
+		return true
+	}
+
+	for _, failure := range e.Failures {
+		if errors.Is(failure, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds first of failures that matches target, so each of them may be obtained with errors.As.
+func (e *SoftAssertionsError) As(target interface{}) bool {
+	for _, failure := range e.Failures {
+		if errors.As(failure, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// stepError is error, which may be annotated with name of step that returned it.
+type stepError interface {
+	error
+	setStep(step string)
+	step() string
+}
+
+// withStep annotates err with step name. Errors other than described in this file are wrapped in *StepError.
+func withStep(step string, err error) error {
+	var se stepError
+	if errors.As(err, &se) {
+		se.setStep(step)
+		return err
+	}
+
+	return &StepError{Step: step, Err: err}
+}
+
+// stepName returns name of step that returned err.
+func stepName(err error) string {
+	var se stepError
+	if errors.As(err, &se) && se.step() != "" {
+		return se.step()
+	}
+
+	return "assertion"
+}
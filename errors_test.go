@@ -0,0 +1,189 @@
+package gdutils
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/pawelWritesCode/gdutils/pkg/format"
+	"github.com/pawelWritesCode/gdutils/pkg/httpcache"
+)
+
+func newErrorsTestAPIContext(body string) *APIContext {
+	s := NewDefaultAPIContext(false, "")
+	s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{
+		StatusCode: http.StatusUnprocessableEntity,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+	})
+
+	return s
+}
+
+func TestAssertionError(t *testing.T) {
+	tests := []struct {
+		name     string
+		assert   func(s *APIContext) error
+		step     string
+		path     string
+		expected interface{}
+		actual   interface{}
+	}{
+		{name: "status code", assert: func(s *APIContext) error {
+			return s.TheResponseStatusCodeShouldBe(http.StatusOK)
+		}, step: "TheResponseStatusCodeShouldBe", path: "status code", expected: "200", actual: http.StatusUnprocessableEntity},
+		{name: "node value", assert: func(s *APIContext) error {
+			return s.TheNodeShouldBeOfValue(format.JSON, "$.name", "string", "abc")
+		}, step: "TheNodeShouldBeOfValue", path: "$.name", expected: "abc", actual: "xyz"},
+		{name: "header value", assert: func(s *APIContext) error {
+			return s.TheResponseShouldHaveHeaderOfValue("Content-Type", "text/plain")
+		}, step: "TheResponseShouldHaveHeaderOfValue", path: "Content-Type", expected: "text/plain", actual: "application/json"},
+		{name: "node type", assert: func(s *APIContext) error {
+			return s.TheNodeShouldBe(format.JSON, "$.name", "int")
+		}, step: "TheNodeShouldBe", path: "$.name", expected: "int", actual: "xyz"},
+		{name: "slice length", assert: func(s *APIContext) error {
+			s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`{"items": [1, 2]}`))})
+			return s.TheNodeShouldBeSliceOfLength(format.JSON, "$.items", 3)
+		}, step: "TheNodeShouldBeSliceOfLength", path: "$.items", expected: 3, actual: 2},
+		{name: "missing header", assert: func(s *APIContext) error {
+			return s.TheResponseShouldHaveHeader("X-Request-Id")
+		}, step: "TheResponseShouldHaveHeader", path: "X-Request-Id"},
+		{name: "body format", assert: func(s *APIContext) error {
+			return s.TheResponseBodyShouldHaveFormat(format.YAML)
+		}, step: "TheResponseBodyShouldHaveFormat", path: "body", expected: format.YAML, actual: format.JSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.assert(newErrorsTestAPIContext(`{"name": "xyz"}`))
+
+			var assertionErr *AssertionError
+			if !errors.As(err, &assertionErr) {
+				t.Fatalf("error %v (%T) should be *AssertionError", err, err)
+			}
+
+			if !errors.Is(err, ErrAssertion) {
+				t.Errorf("errors.Is(%v, ErrAssertion) should be true", err)
+			}
+
+			if assertionErr.Step != tt.step {
+				t.Errorf("Step = %s, want %s", assertionErr.Step, tt.step)
+			}
+
+			if assertionErr.Path != tt.path {
+				t.Errorf("Path = %s, want %s", assertionErr.Path, tt.path)
+			}
+
+			if tt.expected != nil && assertionErr.Expected != tt.expected {
+				t.Errorf("Expected = %v, want %v", assertionErr.Expected, tt.expected)
+			}
+
+			if tt.actual != nil && assertionErr.Actual != tt.actual {
+				t.Errorf("Actual = %v, want %v", assertionErr.Actual, tt.actual)
+			}
+		})
+	}
+}
+
+func TestStepErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		assert   func(s *APIContext) error
+		sentinel error
+		target   interface{}
+		step     string
+	}{
+		{name: "missing response", assert: func(s *APIContext) error {
+			return s.TheResponseShouldHaveHeader("Content-Type")
+		}, sentinel: ErrMissingResponse, target: new(*MissingResponseError), step: "TheResponseShouldHaveHeader"},
+		{name: "template", assert: func(s *APIContext) error {
+			mTemplateEngine := new(mockedTemplateEngine)
+			mTemplateEngine.On("Replace", "abc", mock.Anything).Return("", errors.New("unclosed action"))
+			s.SetTemplateEngine(mTemplateEngine)
+			return s.TheNodeShouldBeOfValue(format.JSON, "$.name", "string", "abc")
+		}, sentinel: ErrTemplate, target: new(*TemplateError), step: "TheNodeShouldBeOfValue"},
+		{name: "path finder", assert: func(s *APIContext) error {
+			s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`{}`))})
+			return s.TheNodeShouldBeSliceOfLength(format.JSON, "$.name", 1)
+		}, sentinel: ErrPathFinder, target: new(*PathFinderError), step: "TheNodeShouldBeSliceOfLength"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.assert(NewDefaultAPIContext(false, ""))
+			if !errors.Is(err, tt.sentinel) {
+				t.Fatalf("errors.Is(%v, %v) should be true", err, tt.sentinel)
+			}
+
+			if !errors.As(err, tt.target) {
+				t.Fatalf("error %v (%T) should be %T", err, err, tt.target)
+			}
+
+			if errors.Is(err, ErrAssertion) {
+				t.Errorf("errors.Is(%v, ErrAssertion) should be false", err)
+			}
+
+			if got := stepName(err); got != tt.step {
+				t.Errorf("stepName() = %s, want %s", got, tt.step)
+			}
+		})
+	}
+}
+
+func TestStepError(t *testing.T) {
+	s := NewDefaultAPIContext(false, "")
+
+	err := s.ISaveAs("", "key")
+
+	var stepErr *StepError
+	if !errors.As(err, &stepErr) {
+		t.Fatalf("error %v (%T) should be *StepError", err, err)
+	}
+
+	if stepErr.Step != "ISaveAs" {
+		t.Errorf("Step = %s, want ISaveAs", stepErr.Step)
+	}
+
+	if errors.Is(err, ErrAssertion) {
+		t.Errorf("errors.Is(%v, ErrAssertion) should be false", err)
+	}
+
+	err = s.IGenerateARandomRunesInTheRangeToAndSaveItAs("abc")(5, 1, "key")
+	if got := stepName(err); got != "IGenerateARandomRunesInTheRangeToAndSaveItAs" {
+		t.Errorf("stepName() = %s, want IGenerateARandomRunesInTheRangeToAndSaveItAs", got)
+	}
+}
+
+func TestSoftAssertionsError(t *testing.T) {
+	s := newErrorsTestAPIContext(`{"name": "xyz"}`)
+	if err := s.IStartSoftAssertions(); err != nil {
+		t.Fatal(err)
+	}
+
+	_ = s.TheResponseStatusCodeShouldBe(http.StatusOK)
+	_ = s.TheNodeShouldBeOfValue(format.JSON, "$.name", "string", "abc")
+
+	err := s.AllSoftAssertionsShouldPass()
+
+	var softErr *SoftAssertionsError
+	if !errors.As(err, &softErr) {
+		t.Fatalf("error %v (%T) should be *SoftAssertionsError", err, err)
+	}
+
+	if len(softErr.Failures) != 2 {
+		t.Fatalf("Failures = %v, want 2 failures", softErr.Failures)
+	}
+
+	if !errors.Is(err, ErrAssertion) {
+		t.Errorf("errors.Is(%v, ErrAssertion) should be true", err)
+	}
+
+	var assertionErr *AssertionError
+	if !errors.As(err, &assertionErr) || assertionErr.Step != "TheResponseStatusCodeShouldBe" {
+		t.Errorf("errors.As() should obtain first recorded failure, got %+v", assertionErr)
+	}
+}
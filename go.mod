@@ -3,20 +3,20 @@ module github.com/pawelWritesCode/gdutils
 go 1.16
 
 require (
-	github.com/antchfx/xmlquery v1.3.9 // indirect
+	github.com/antchfx/xmlquery v1.3.9
+	github.com/antonmedv/expr v1.9.0
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/fatih/color v1.13.0 // indirect
-	github.com/goccy/go-yaml v1.9.5 // indirect
+	github.com/fatih/color v1.13.0
+	github.com/goccy/go-yaml v1.9.5
 	github.com/kr/text v0.2.0 // indirect
 	github.com/mattn/go-colorable v0.1.12 // indirect
 	github.com/moul/http2curl v1.0.0
 	github.com/oliveagle/jsonpath v0.0.0-20180606110733-2e52cf6e6852
 	github.com/pawelWritesCode/qjson v1.0.1
+	github.com/santhosh-tekuri/jsonschema/v5 v5.2.0
 	github.com/smartystreets/goconvey v1.6.4 // indirect
 	github.com/stretchr/objx v0.1.1 // indirect
 	github.com/stretchr/testify v1.7.0
-	github.com/xeipuuv/gojsonpointer v0.0.0-20190905194746-02993c407bfb // indirect
-	github.com/xeipuuv/gojsonschema v1.2.0
 	golang.org/x/sys v0.0.0-20220224120231-95c6836cb0e7 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 	gopkg.in/yaml.v2 v2.4.0
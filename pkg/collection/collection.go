@@ -0,0 +1,191 @@
+// Package collection holds utilities for working with slices obtained from deserialized documents.
+package collection
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pawelWritesCode/gdutils/pkg/compare"
+	"github.com/pawelWritesCode/gdutils/pkg/mathutils"
+)
+
+// Order describes sort order.
+type Order string
+
+const (
+	// Ascending order, every element is greater than or equal to previous one.
+	Ascending Order = "ascending"
+
+	// Descending order, every element is less than or equal to previous one.
+	Descending Order = "descending"
+)
+
+// ToSlice converts any slice into []interface{}.
+func ToSlice(value interface{}) ([]interface{}, bool) {
+	if s, ok := value.([]interface{}); ok {
+		return s, true
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	s := make([]interface{}, v.Len())
+	for i := range s {
+		s[i] = v.Index(i).Interface()
+	}
+
+	return s, true
+}
+
+// ParseValue parses raw value in JSON notation, for example: 10, true, "abc" or {"id": 1}.
+// Raw value, which is not valid JSON, is returned as string.
+func ParseValue(raw string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		return raw
+	}
+
+	return v
+}
+
+// Equal checks whether actual value equals expected one. Actual string is compared with expected
+// number or bool after parsing, because XML nodes values are always strings.
+func Equal(expected, actual interface{}) bool {
+	expected, actual = compare.Normalize(expected), compare.Normalize(actual)
+	if len(compare.Compare(expected, actual, compare.ModeExact, nil)) == 0 {
+		return true
+	}
+
+	actualString, ok := actual.(string)
+	if !ok {
+		return false
+	}
+
+	switch exp := expected.(type) {
+	case float64:
+		number, err := mathutils.ToFloat64(actualString)
+		return err == nil && number == exp
+	case bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(actualString))
+		return err == nil && b == exp
+	}
+
+	return false
+}
+
+// Contains checks whether slice contains element equal to expected one.
+func Contains(slice []interface{}, expected interface{}) bool {
+	for _, element := range slice {
+		if Equal(expected, element) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseOrder parses order name, accepted names are: asc, ascending, desc, descending.
+func ParseOrder(name string) (Order, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "asc", string(Ascending):
+		return Ascending, nil
+	case "desc", string(Descending):
+		return Descending, nil
+	default:
+		return "", fmt.Errorf("unknown order: %s, available orders: %s, %s", name, Ascending, Descending)
+	}
+}
+
+// FirstUnsorted returns index of first element, which breaks provided order, or -1 when values are sorted.
+// Values should be all numbers or all strings. Strings holding numbers are compared as numbers
+// when numericStrings is true, which is useful for XML nodes.
+func FirstUnsorted(values []interface{}, order Order, numericStrings bool) (int, error) {
+	if order != Ascending && order != Descending {
+		return 0, fmt.Errorf("unknown order: %s, available orders: %s, %s", order, Ascending, Descending)
+	}
+
+	keys, err := sortKeys(values, numericStrings)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := 1; i < len(keys); i++ {
+		cmp := keys[i-1].compare(keys[i])
+		if (order == Ascending && cmp > 0) || (order == Descending && cmp < 0) {
+			return i, nil
+		}
+	}
+
+	return -1, nil
+}
+
+// FirstDuplicate returns indexes of first pair of equal values or -1, -1 when all values are unique.
+func FirstDuplicate(values []interface{}) (int, int, error) {
+	seen := make(map[string]int, len(values))
+	for i, value := range values {
+		key, err := json.Marshal(compare.Normalize(value))
+		if err != nil {
+			return 0, 0, fmt.Errorf("could not serialize element %d, err: %w", i, err)
+		}
+
+		if first, ok := seen[string(key)]; ok {
+			return first, i, nil
+		}
+
+		seen[string(key)] = i
+	}
+
+	return -1, -1, nil
+}
+
+// sortKey is value comparable with other values of the same kind.
+type sortKey struct {
+	number float64
+	text   string
+}
+
+func (k sortKey) compare(other sortKey) int {
+	switch {
+	case k.text < other.text, k.text == other.text && k.number < other.number:
+		return -1
+	case k.text == other.text && k.number == other.number:
+		return 0
+	default:
+		return 1
+	}
+}
+
+func sortKeys(values []interface{}, numericStrings bool) ([]sortKey, error) {
+	keys := make([]sortKey, len(values))
+
+	allNumbers := true
+	for i, value := range values {
+		number, err := mathutils.ToFloat64(compare.Normalize(value))
+		if _, isString := value.(string); err != nil || (isString && !numericStrings) {
+			allNumbers = false
+			break
+		}
+
+		keys[i] = sortKey{number: number}
+	}
+
+	if allNumbers {
+		return keys, nil
+	}
+
+	for i, value := range values {
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("element %d: %v of type %T can't be compared, values should be all numbers or all strings", i, value, value)
+		}
+
+		keys[i] = sortKey{text: text}
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,113 @@
+package collection
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToSlice(t *testing.T) {
+	if got, ok := ToSlice([]string{"a", "b"}); !ok || !reflect.DeepEqual(got, []interface{}{"a", "b"}) {
+		t.Errorf("ToSlice() = %v, %t", got, ok)
+	}
+
+	if _, ok := ToSlice(map[string]interface{}{}); ok {
+		t.Errorf("ToSlice() should not accept map")
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want interface{}
+	}{
+		{raw: "10", want: float64(10)},
+		{raw: "true", want: true},
+		{raw: `"abc"`, want: "abc"},
+		{raw: "abc", want: "abc"},
+		{raw: `{"id": 1}`, want: map[string]interface{}{"id": float64(1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			if got := ParseValue(tt.raw); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseValue() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContains(t *testing.T) {
+	slice := []interface{}{uint64(1), "2", map[string]interface{}{"id": 3}, "true"}
+	tests := []struct {
+		name     string
+		expected interface{}
+		want     bool
+	}{
+		{name: "number", expected: float64(1), want: true},
+		{name: "number from string", expected: float64(2), want: true},
+		{name: "object", expected: map[string]interface{}{"id": float64(3)}, want: true},
+		{name: "bool from string", expected: true, want: true},
+		{name: "missing", expected: "x", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Contains(slice, tt.expected); got != tt.want {
+				t.Errorf("Contains() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFirstUnsorted(t *testing.T) {
+	tests := []struct {
+		name           string
+		values         []interface{}
+		order          Order
+		numericStrings bool
+		want           int
+		wantErr        bool
+	}{
+		{name: "ascending numbers", values: []interface{}{1.0, uint64(2), 2.0, 10.0}, order: Ascending, want: -1},
+		{name: "not ascending numbers", values: []interface{}{1.0, 3.0, 2.0}, order: Ascending, want: 2},
+		{name: "descending strings", values: []interface{}{"c", "b", "b", "a"}, order: Descending, want: -1},
+		{name: "strings compared lexicographically", values: []interface{}{"9", "10"}, order: Ascending, want: 1},
+		{name: "numeric strings", values: []interface{}{"9", "10"}, order: Ascending, numericStrings: true, want: -1},
+		{name: "mixed values", values: []interface{}{1.0, "a"}, order: Ascending, wantErr: true},
+		{name: "unknown order", values: []interface{}{}, order: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FirstUnsorted(tt.values, tt.order, tt.numericStrings)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FirstUnsorted() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("FirstUnsorted() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFirstDuplicate(t *testing.T) {
+	first, second, err := FirstDuplicate([]interface{}{map[string]interface{}{"a": 1, "b": 2}, "x", map[string]interface{}{"b": uint64(2), "a": 1.0}})
+	if err != nil || first != 0 || second != 2 {
+		t.Errorf("FirstDuplicate() = %d, %d, err: %v", first, second, err)
+	}
+
+	first, second, err = FirstDuplicate([]interface{}{1.0, 2.0})
+	if err != nil || first != -1 || second != -1 {
+		t.Errorf("FirstDuplicate() = %d, %d, err: %v", first, second, err)
+	}
+}
+
+func TestParseOrder(t *testing.T) {
+	for name, want := range map[string]Order{"asc": Ascending, "Descending": Descending} {
+		if got, err := ParseOrder(name); err != nil || got != want {
+			t.Errorf("ParseOrder(%s) = %s, err: %v", name, got, err)
+		}
+	}
+
+	if _, err := ParseOrder("random"); err == nil {
+		t.Errorf("ParseOrder() expected error")
+	}
+}
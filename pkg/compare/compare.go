@@ -0,0 +1,406 @@
+// Package compare holds utilities for deep comparison of deserialized JSON and YAML documents.
+package compare
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Mode describes how documents are compared.
+type Mode string
+
+const (
+	// ModeExact requires documents to be equal, including order of arrays elements.
+	ModeExact Mode = "exact"
+
+	// ModeSubset requires every expected object key and array element to be present in actual document.
+	// Actual document may have additional keys and elements. Arrays elements order is not relevant.
+	ModeSubset Mode = "subset"
+
+	// ModeUnordered requires documents to be equal, but arrays elements may be in any order.
+	ModeUnordered Mode = "unordered"
+)
+
+// Root is path of document root.
+const Root = "$"
+
+// Difference describes single difference between expected and actual document.
+type Difference struct {
+	// Path points at differing node, for example: $.users[1].name
+	Path string
+
+	// Expected is expected value, nil if node was not expected.
+	Expected interface{}
+
+	// Actual is actual value, nil if node is missing.
+	Actual interface{}
+
+	// Reason is human-readable description of difference.
+	Reason string
+}
+
+// Differences is collection of differences.
+type Differences []Difference
+
+// String returns every difference in separate line.
+func (d Differences) String() string {
+	lines := make([]string, 0, len(d))
+	for _, difference := range d {
+		lines = append(lines, difference.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// String returns human-readable representation of difference.
+func (d Difference) String() string {
+	return fmt.Sprintf("%s: %s", d.Path, d.Reason)
+}
+
+// ParseMode parses mode name, empty name means ModeExact.
+func ParseMode(name string) (Mode, error) {
+	switch Mode(strings.ToLower(strings.TrimSpace(name))) {
+	case ModeExact, "":
+		return ModeExact, nil
+	case ModeSubset:
+		return ModeSubset, nil
+	case ModeUnordered:
+		return ModeUnordered, nil
+	default:
+		return "", fmt.Errorf("unknown comparison mode: %s, available modes: %s, %s, %s", name, ModeExact, ModeSubset, ModeUnordered)
+	}
+}
+
+// Compare compares expected and actual documents according to mode and returns all found differences.
+// ignoredPaths are paths of nodes excluded from comparison, they may contain wildcards, for example: $.users[*].id or $.*.createdAt
+func Compare(expected, actual interface{}, mode Mode, ignoredPaths []string) Differences {
+	c := comparator{mode: mode}
+	for _, ignored := range ignoredPaths {
+		if ignored = strings.TrimSpace(ignored); ignored != "" {
+			c.ignored = append(c.ignored, splitPath(ignored))
+		}
+	}
+
+	return c.compare(Root, Normalize(expected), Normalize(actual))
+}
+
+// Normalize converts deserialized document into form with map[string]interface{} objects,
+// []interface{} arrays and float64 numbers, so documents deserialized by different libraries may be compared.
+func Normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, string, bool, float64:
+		return v
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			normalized[key] = Normalize(val)
+		}
+
+		return normalized
+	case map[interface{}]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			normalized[fmt.Sprint(key)] = Normalize(val)
+		}
+
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, val := range v {
+			normalized[i] = Normalize(val)
+		}
+
+		return normalized
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32:
+		return rv.Float()
+	case reflect.Slice, reflect.Array:
+		normalized := make([]interface{}, rv.Len())
+		for i := range normalized {
+			normalized[i] = Normalize(rv.Index(i).Interface())
+		}
+
+		return normalized
+	case reflect.Map:
+		normalized := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			normalized[fmt.Sprint(iter.Key().Interface())] = Normalize(iter.Value().Interface())
+		}
+
+		return normalized
+	}
+
+	return value
+}
+
+// MatchPath checks whether path matches pattern. Pattern may contain wildcards:
+// "*" matches any object key and "[*]" matches any array index, for example: $.users[*].id
+func MatchPath(pattern, path string) bool {
+	return matchSegments(splitPath(pattern), splitPath(path))
+}
+
+// comparator holds comparison settings.
+type comparator struct {
+	mode    Mode
+	ignored [][]string
+}
+
+func (c comparator) compare(path string, expected, actual interface{}) Differences {
+	if c.isIgnored(path) {
+		return nil
+	}
+
+	switch exp := expected.(type) {
+	case map[string]interface{}:
+		act, ok := actual.(map[string]interface{})
+		if !ok {
+			return Differences{c.typeDifference(path, expected, actual)}
+		}
+
+		return c.compareObjects(path, exp, act)
+	case []interface{}:
+		act, ok := actual.([]interface{})
+		if !ok {
+			return Differences{c.typeDifference(path, expected, actual)}
+		}
+
+		if c.mode == ModeExact {
+			return c.compareOrderedArrays(path, exp, act)
+		}
+
+		return c.compareUnorderedArrays(path, exp, act)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		return Differences{{Path: path, Expected: expected, Actual: actual, Reason: fmt.Sprintf("expected %s, got %s", describe(expected), describe(actual))}}
+	}
+
+	return nil
+}
+
+func (c comparator) compareObjects(path string, expected, actual map[string]interface{}) Differences {
+	var differences Differences
+	for _, key := range sortedKeys(expected) {
+		keyPath := path + "." + key
+		actualValue, ok := actual[key]
+		if !ok {
+			if !c.isIgnored(keyPath) {
+				differences = append(differences, Difference{Path: keyPath, Expected: expected[key], Reason: fmt.Sprintf("missing node, expected %s", describe(expected[key]))})
+			}
+
+			continue
+		}
+
+		differences = append(differences, c.compare(keyPath, expected[key], actualValue)...)
+	}
+
+	if c.mode == ModeSubset {
+		return differences
+	}
+
+	for _, key := range sortedKeys(actual) {
+		keyPath := path + "." + key
+		if _, ok := expected[key]; !ok && !c.isIgnored(keyPath) {
+			differences = append(differences, Difference{Path: keyPath, Actual: actual[key], Reason: fmt.Sprintf("unexpected node %s", describe(actual[key]))})
+		}
+	}
+
+	return differences
+}
+
+func (c comparator) compareOrderedArrays(path string, expected, actual []interface{}) Differences {
+	var differences Differences
+	for i := 0; i < len(expected) || i < len(actual); i++ {
+		elementPath := fmt.Sprintf("%s[%d]", path, i)
+		switch {
+		case i >= len(actual):
+			if !c.isIgnored(elementPath) {
+				differences = append(differences, Difference{Path: elementPath, Expected: expected[i], Reason: fmt.Sprintf("missing element, expected %s", describe(expected[i]))})
+			}
+		case i >= len(expected):
+			if !c.isIgnored(elementPath) {
+				differences = append(differences, Difference{Path: elementPath, Actual: actual[i], Reason: fmt.Sprintf("unexpected element %s", describe(actual[i]))})
+			}
+		default:
+			differences = append(differences, c.compare(elementPath, expected[i], actual[i])...)
+		}
+	}
+
+	return differences
+}
+
+// compareUnorderedArrays pairs expected elements with equal actual elements, so that as many expected elements
+// as possible are paired. Pairing is maximum bipartite matching, so element matching many actual elements
+// does not take the only match of other element.
+func (c comparator) compareUnorderedArrays(path string, expected, actual []interface{}) Differences {
+	candidates := make([][]int, len(expected))
+	for i, expectedElement := range expected {
+		elementPath := fmt.Sprintf("%s[%d]", path, i)
+		if c.isIgnored(elementPath) {
+			continue
+		}
+
+		for j, actualElement := range actual {
+			if len(c.compare(elementPath, expectedElement, actualElement)) == 0 {
+				candidates[i] = append(candidates[i], j)
+			}
+		}
+	}
+
+	// pairedWith holds index of expected element paired with actual element or -1.
+	pairedWith := make([]int, len(actual))
+	for j := range pairedWith {
+		pairedWith[j] = -1
+	}
+
+	var pair func(i int, visited []bool) bool
+	pair = func(i int, visited []bool) bool {
+		for _, j := range candidates[i] {
+			if visited[j] {
+				continue
+			}
+
+			visited[j] = true
+			if pairedWith[j] == -1 || pair(pairedWith[j], visited) {
+				pairedWith[j] = i
+				return true
+			}
+		}
+
+		return false
+	}
+
+	var differences Differences
+	for i, expectedElement := range expected {
+		elementPath := fmt.Sprintf("%s[%d]", path, i)
+		if c.isIgnored(elementPath) {
+			continue
+		}
+
+		if !pair(i, make([]bool, len(actual))) {
+			differences = append(differences, Difference{Path: elementPath, Expected: expectedElement, Reason: fmt.Sprintf("no matching element found for %s", describe(expectedElement))})
+		}
+	}
+
+	if c.mode == ModeSubset {
+		return differences
+	}
+
+	for j, actualElement := range actual {
+		elementPath := fmt.Sprintf("%s[%d]", path, j)
+		if pairedWith[j] == -1 && !c.isIgnored(elementPath) {
+			differences = append(differences, Difference{Path: elementPath, Actual: actualElement, Reason: fmt.Sprintf("unexpected element %s", describe(actualElement))})
+		}
+	}
+
+	return differences
+}
+
+func (c comparator) typeDifference(path string, expected, actual interface{}) Difference {
+	return Difference{Path: path, Expected: expected, Actual: actual, Reason: fmt.Sprintf("expected %s, got %s", describe(expected), describe(actual))}
+}
+
+// isIgnored checks whether path matches any of ignored paths.
+func (c comparator) isIgnored(path string) bool {
+	if len(c.ignored) == 0 {
+		return false
+	}
+
+	segments := splitPath(path)
+	for _, ignored := range c.ignored {
+		if matchSegments(ignored, segments) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitPath splits path like $.a.b[0].c into segments: a, b, [0], c.
+func splitPath(path string) []string {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, Root), ".")
+
+	var segments []string
+	for _, part := range strings.Split(path, ".") {
+		for part != "" {
+			idx := strings.Index(part, "[")
+			switch {
+			case idx == -1:
+				segments = append(segments, part)
+				part = ""
+			case idx > 0:
+				segments = append(segments, part[:idx])
+				part = part[idx:]
+			default:
+				end := strings.Index(part, "]")
+				if end == -1 {
+					segments = append(segments, part)
+					part = ""
+					continue
+				}
+
+				segments = append(segments, part[:end+1])
+				part = part[end+1:]
+			}
+		}
+	}
+
+	return segments
+}
+
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) != len(segments) {
+		return false
+	}
+
+	for i, p := range pattern {
+		if p == segments[i] || (p == "*" && !strings.HasPrefix(segments[i], "[")) || (p == "[*]" && strings.HasPrefix(segments[i], "[")) {
+			continue
+		}
+
+		return false
+	}
+
+	return true
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// describe returns short description of value with its type.
+func describe(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return fmt.Sprintf("object with %d key(s)", len(v))
+	case []interface{}:
+		return fmt.Sprintf("array of length %d", len(v))
+	case string:
+		return fmt.Sprintf("string %q", v)
+	case float64:
+		return fmt.Sprintf("number %v", v)
+	case bool:
+		return fmt.Sprintf("bool %t", v)
+	default:
+		return fmt.Sprintf("%T %v", v, v)
+	}
+}
@@ -0,0 +1,137 @@
+package compare
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func mustUnmarshal(t *testing.T, data string) interface{} {
+	t.Helper()
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	return v
+}
+
+func TestCompare(t *testing.T) {
+	actual := `{"id": 10, "name": "ivo", "tags": ["a", "b"], "users": [{"id": 1, "name": "a"}, {"id": 2, "name": "b"}], "meta": null}`
+	tests := []struct {
+		name     string
+		expected string
+		mode     Mode
+		ignored  []string
+		want     []string
+	}{
+		{name: "exact equal", expected: actual, mode: ModeExact},
+		{name: "exact with differences", mode: ModeExact,
+			expected: `{"id": "10", "name": "ivo", "tags": ["b", "a"], "users": [{"id": 1, "name": "a"}], "other": true}`,
+			want: []string{
+				`$.id: expected string "10", got number 10`,
+				`$.other: missing node, expected bool true`,
+				`$.tags[0]: expected string "b", got string "a"`,
+				`$.tags[1]: expected string "a", got string "b"`,
+				`$.users[1]: unexpected element object with 2 key(s)`,
+				`$.meta: unexpected node null`,
+			}},
+		{name: "exact with ignored paths", mode: ModeExact, ignored: []string{"$.id", "$.users[*].id", " $.meta "},
+			expected: `{"id": 99, "name": "ivo", "tags": ["a", "b"], "users": [{"id": 5, "name": "a"}, {"name": "b"}]}`},
+		{name: "subset", mode: ModeSubset, expected: `{"name": "ivo", "tags": ["b"], "users": [{"name": "b"}]}`},
+		{name: "subset with first candidate needed by later element", mode: ModeSubset, expected: `{"users": [{}, {"name": "a"}]}`},
+		{name: "subset with differences", mode: ModeSubset, expected: `{"name": "ivo", "tags": ["c"], "users": [{"name": "c"}]}`,
+			want: []string{
+				`$.tags[0]: no matching element found for string "c"`,
+				`$.users[0]: no matching element found for object with 1 key(s)`,
+			}},
+		{name: "unordered", mode: ModeUnordered,
+			expected: `{"id": 10, "name": "ivo", "tags": ["b", "a"], "users": [{"id": 2, "name": "b"}, {"id": 1, "name": "a"}], "meta": null}`},
+		{name: "unordered with differences", mode: ModeUnordered,
+			expected: `{"id": 10, "name": "ivo", "tags": ["b", "b"], "users": [{"id": 2, "name": "b"}, {"id": 1, "name": "a"}], "meta": {}}`,
+			want: []string{
+				`$.meta: expected object with 0 key(s), got null`,
+				`$.tags[1]: no matching element found for string "b"`,
+				`$.tags[0]: unexpected element string "a"`,
+			}},
+		{name: "unordered with wildcard ignored path", mode: ModeUnordered, ignored: []string{"$.*[*].id"},
+			expected: `{"id": 10, "name": "ivo", "tags": ["b", "a"], "users": [{"id": 7, "name": "b"}, {"id": 8, "name": "a"}], "meta": null}`},
+		{name: "unordered with ignored unpaired actual element", mode: ModeUnordered, ignored: []string{"$.tags[1]"},
+			expected: `{"id": 10, "name": "ivo", "tags": ["a"], "users": [{"id": 2, "name": "b"}, {"id": 1, "name": "a"}], "meta": null}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			differences := Compare(mustUnmarshal(t, tt.expected), mustUnmarshal(t, actual), tt.mode, tt.ignored)
+
+			got := make([]string, 0, len(differences))
+			for _, difference := range differences {
+				got = append(got, difference.String())
+			}
+
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compare() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	in := map[interface{}]interface{}{"a": uint64(1), "b": []interface{}{int64(-2), map[string]interface{}{"c": float32(0.5)}}, 1: []string{"x"}}
+	want := map[string]interface{}{"a": float64(1), "b": []interface{}{float64(-2), map[string]interface{}{"c": 0.5}}, "1": []interface{}{"x"}}
+
+	if got := Normalize(in); !reflect.DeepEqual(got, want) {
+		t.Errorf("Normalize() = %#v, want %#v", got, want)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Mode
+		wantErr bool
+	}{
+		{name: "", want: ModeExact},
+		{name: "Subset", want: ModeSubset},
+		{name: " unordered ", want: ModeUnordered},
+		{name: "partial", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMode(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseMode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{pattern: "$.a.b", path: "$.a.b", want: true},
+		{pattern: "$.a[*].b", path: "$.a[3].b", want: true},
+		{pattern: "$.*.b", path: "$.x.b", want: true},
+		{pattern: "$.*.b", path: "$[0].b", want: false},
+		{pattern: "$.a[*]", path: "$.a.b", want: false},
+		{pattern: "$.a", path: "$.a.b", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			if got := MatchPath(tt.pattern, tt.path); got != tt.want {
+				t.Errorf("MatchPath() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,453 @@
+// Package coverage holds utilities for measuring OpenAPI specification coverage.
+package coverage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ErrEmptySpecification occurs when OpenAPI specification does not describe any operation.
+var ErrEmptySpecification = errors.New("specification does not describe any operation")
+
+// Recorder describes entity that has ability to record exchanged HTTP(s) requests and responses
+// and summarize them in coverage report.
+type Recorder interface {
+	// Record preserves information about sent request and obtained response status code.
+	Record(method, path string, statusCode int)
+
+	// Report returns coverage report of all recorded requests.
+	Report() Report
+}
+
+// Operation describes single OpenAPI operation.
+type Operation struct {
+	// Method is HTTP method of operation, for example: GET.
+	Method string
+
+	// Path is path template of operation, for example: /users/{id}.
+	Path string
+
+	// OperationID is optional operation identifier.
+	OperationID string
+
+	// StatusCodes are response status codes documented for operation, for example: 200, 4XX, default.
+	StatusCodes []string
+}
+
+// Specification holds OpenAPI operations with base paths of servers they are exposed on.
+type Specification struct {
+	// Operations are all operations found in specification.
+	Operations []Operation
+
+	// BasePaths are URL path prefixes under which operations are exposed.
+	BasePaths []string
+}
+
+// OpenAPIRecorder is entity that has ability to match recorded requests against OpenAPI specification.
+// Safe for concurrent use.
+type OpenAPIRecorder struct {
+	spec Specification
+
+	mu        sync.Mutex
+	hits      map[int]map[string]int
+	unmatched map[UnmatchedRequest]int
+}
+
+// Report holds information about covered and not covered operations and status codes.
+type Report struct {
+	// Operations holds coverage of every operation from specification.
+	Operations []OperationReport `json:"operations"`
+
+	// Unmatched holds requests that did not match any operation from specification.
+	Unmatched []UnmatchedRequest `json:"unmatched"`
+
+	// OperationsHit is number of operations that were called at least once.
+	OperationsHit int `json:"operationsHit"`
+
+	// OperationsTotal is number of all operations in specification.
+	OperationsTotal int `json:"operationsTotal"`
+
+	// StatusCodesHit is number of documented status codes that were obtained at least once.
+	StatusCodesHit int `json:"statusCodesHit"`
+
+	// StatusCodesTotal is number of all documented status codes in specification.
+	StatusCodesTotal int `json:"statusCodesTotal"`
+}
+
+// OperationReport describes coverage of single operation.
+type OperationReport struct {
+	Method      string             `json:"method"`
+	Path        string             `json:"path"`
+	OperationID string             `json:"operationId,omitempty"`
+	Hits        int                `json:"hits"`
+	StatusCodes []StatusCodeReport `json:"statusCodes"`
+
+	// Undocumented holds obtained status codes that are not documented for operation.
+	Undocumented []string `json:"undocumented,omitempty"`
+}
+
+// StatusCodeReport describes coverage of single documented status code.
+type StatusCodeReport struct {
+	Code string `json:"code"`
+	Hits int    `json:"hits"`
+}
+
+// UnmatchedRequest describes request that did not match any operation.
+type UnmatchedRequest struct {
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	StatusCode int    `json:"statusCode"`
+	Hits       int    `json:"hits"`
+}
+
+type document struct {
+	BasePath string              `yaml:"basePath"`
+	Servers  []server            `yaml:"servers"`
+	Paths    map[string]pathItem `yaml:"paths"`
+}
+
+type server struct {
+	URL string `yaml:"url"`
+}
+
+type pathItem struct {
+	Get     *operation `yaml:"get"`
+	Put     *operation `yaml:"put"`
+	Post    *operation `yaml:"post"`
+	Delete  *operation `yaml:"delete"`
+	Options *operation `yaml:"options"`
+	Head    *operation `yaml:"head"`
+	Patch   *operation `yaml:"patch"`
+	Trace   *operation `yaml:"trace"`
+}
+
+type operation struct {
+	OperationID string                      `yaml:"operationId"`
+	Responses   map[interface{}]interface{} `yaml:"responses"`
+}
+
+// ParseSpecification parses OpenAPI 3.x or Swagger 2.0 document in JSON or YAML format.
+func ParseSpecification(data []byte) (Specification, error) {
+	var doc document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return Specification{}, fmt.Errorf("could not parse OpenAPI specification, err: %w", err)
+	}
+
+	spec := Specification{}
+	if doc.BasePath != "" && doc.BasePath != "/" {
+		spec.BasePaths = append(spec.BasePaths, strings.TrimSuffix(doc.BasePath, "/"))
+	}
+
+	for _, s := range doc.Servers {
+		u, err := url.Parse(s.URL)
+		if err != nil {
+			continue
+		}
+
+		if p := strings.TrimSuffix(u.Path, "/"); p != "" {
+			spec.BasePaths = append(spec.BasePaths, p)
+		}
+	}
+
+	for pth, item := range doc.Paths {
+		methods := map[string]*operation{
+			"GET": item.Get, "PUT": item.Put, "POST": item.Post, "DELETE": item.Delete,
+			"OPTIONS": item.Options, "HEAD": item.Head, "PATCH": item.Patch, "TRACE": item.Trace,
+		}
+
+		for method, op := range methods {
+			if op == nil {
+				continue
+			}
+
+			codes := make([]string, 0, len(op.Responses))
+			for code := range op.Responses {
+				codes = append(codes, strings.ToUpper(fmt.Sprint(code)))
+			}
+			sort.Strings(codes)
+
+			spec.Operations = append(spec.Operations, Operation{
+				Method:      method,
+				Path:        pth,
+				OperationID: op.OperationID,
+				StatusCodes: codes,
+			})
+		}
+	}
+
+	if len(spec.Operations) == 0 {
+		return Specification{}, ErrEmptySpecification
+	}
+
+	sort.Slice(spec.Operations, func(i, j int) bool {
+		if spec.Operations[i].Path == spec.Operations[j].Path {
+			return spec.Operations[i].Method < spec.Operations[j].Method
+		}
+
+		return spec.Operations[i].Path < spec.Operations[j].Path
+	})
+
+	return spec, nil
+}
+
+// LoadSpecification reads and parses OpenAPI document located under provided OS path.
+func LoadSpecification(pth string) (Specification, error) {
+	data, err := ioutil.ReadFile(pth)
+	if err != nil {
+		return Specification{}, fmt.Errorf("could not read OpenAPI specification %s, err: %w", pth, err)
+	}
+
+	return ParseSpecification(data)
+}
+
+// NewOpenAPIRecorder returns *OpenAPIRecorder that matches recorded requests against provided specification.
+func NewOpenAPIRecorder(spec Specification) *OpenAPIRecorder {
+	return &OpenAPIRecorder{
+		spec:      spec,
+		hits:      map[int]map[string]int{},
+		unmatched: map[UnmatchedRequest]int{},
+	}
+}
+
+// Record preserves information about sent request and obtained response status code.
+func (r *OpenAPIRecorder) Record(method, path string, statusCode int) {
+	method = strings.ToUpper(method)
+	code := strconv.Itoa(statusCode)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	idx, found := r.match(method, path)
+	if !found {
+		r.unmatched[UnmatchedRequest{Method: method, Path: path, StatusCode: statusCode}]++
+		return
+	}
+
+	if r.hits[idx] == nil {
+		r.hits[idx] = map[string]int{}
+	}
+
+	r.hits[idx][code]++
+}
+
+// Report returns coverage report of all recorded requests.
+func (r *OpenAPIRecorder) Report() Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := Report{
+		Operations:      make([]OperationReport, 0, len(r.spec.Operations)),
+		Unmatched:       []UnmatchedRequest{},
+		OperationsTotal: len(r.spec.Operations),
+	}
+
+	for idx, op := range r.spec.Operations {
+		opReport := OperationReport{Method: op.Method, Path: op.Path, OperationID: op.OperationID}
+		claimed := map[string]bool{}
+
+		for _, documented := range op.StatusCodes {
+			codeReport := StatusCodeReport{Code: documented}
+			for obtained, count := range r.hits[idx] {
+				if statusCodeMatches(documented, obtained, op.StatusCodes) {
+					codeReport.Hits += count
+					claimed[obtained] = true
+				}
+			}
+
+			if codeReport.Hits > 0 {
+				report.StatusCodesHit++
+			}
+
+			opReport.StatusCodes = append(opReport.StatusCodes, codeReport)
+		}
+
+		for obtained, count := range r.hits[idx] {
+			opReport.Hits += count
+			if !claimed[obtained] {
+				opReport.Undocumented = append(opReport.Undocumented, obtained)
+			}
+		}
+		sort.Strings(opReport.Undocumented)
+
+		if opReport.Hits > 0 {
+			report.OperationsHit++
+		}
+
+		report.StatusCodesTotal += len(op.StatusCodes)
+		report.Operations = append(report.Operations, opReport)
+	}
+
+	for u, count := range r.unmatched {
+		u.Hits = count
+		report.Unmatched = append(report.Unmatched, u)
+	}
+
+	sort.Slice(report.Unmatched, func(i, j int) bool {
+		a, b := report.Unmatched[i], report.Unmatched[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+
+		return a.StatusCode < b.StatusCode
+	})
+
+	return report
+}
+
+// match looks for index of operation matching method and path. Operations with more literal
+// path segments take precedence over templated ones, for example /users/me wins over /users/{id}.
+// Base path is stripped only at segment boundary, so /api does not match /apiv2.
+func (r *OpenAPIRecorder) match(method, path string) (int, bool) {
+	candidates := []string{path}
+	for _, basePath := range r.spec.BasePaths {
+		if path == basePath || strings.HasPrefix(path, basePath+"/") {
+			candidates = append(candidates, strings.TrimPrefix(path, basePath))
+		}
+	}
+
+	bestIdx, bestScore := -1, -1
+	for idx, op := range r.spec.Operations {
+		if op.Method != method {
+			continue
+		}
+
+		for _, candidate := range candidates {
+			if score, ok := pathMatches(op.Path, candidate); ok && score > bestScore {
+				bestIdx, bestScore = idx, score
+			}
+		}
+	}
+
+	return bestIdx, bestIdx != -1
+}
+
+// pathMatches checks whether path matches OpenAPI path template.
+// Returned score is number of literal segments that matched.
+func pathMatches(template, path string) (int, bool) {
+	templateSegments := strings.Split(strings.Trim(template, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+
+	if len(templateSegments) != len(pathSegments) {
+		return 0, false
+	}
+
+	score := 0
+	for i, segment := range templateSegments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if pathSegments[i] == "" {
+				return 0, false
+			}
+
+			continue
+		}
+
+		if segment != pathSegments[i] {
+			return 0, false
+		}
+
+		score++
+	}
+
+	return score, true
+}
+
+// statusCodeMatches checks whether obtained status code is described by documented one.
+// documented may be exact code, range like 2XX or "default", which covers codes not documented otherwise.
+func statusCodeMatches(documented, obtained string, allDocumented []string) bool {
+	switch {
+	case documented == obtained:
+		return true
+	case len(documented) == 3 && strings.HasSuffix(documented, "XX"):
+		if documented[0] != obtained[0] {
+			return false
+		}
+
+		for _, other := range allDocumented {
+			if other == obtained {
+				return false
+			}
+		}
+
+		return true
+	case documented == "DEFAULT":
+		for _, other := range allDocumented {
+			if other != "DEFAULT" && statusCodeMatches(other, obtained, allDocumented) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return false
+	}
+}
+
+// WriteJSON writes report in JSON format to w.
+func (r Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+
+	return encoder.Encode(r)
+}
+
+// WriteHTML writes report as HTML document to w.
+func (r Report) WriteHTML(w io.Writer) error {
+	return htmlReport.Execute(w, r)
+}
+
+var htmlReport = template.Must(template.New("coverage").Parse(`<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="utf-8">
+	<title>OpenAPI coverage report</title>
+	<style>
+		body { font-family: sans-serif; }
+		table { border-collapse: collapse; margin-bottom: 2em; }
+		th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+		.hit { background: #d4f7d4; }
+		.missed { background: #f7d4d4; }
+	</style>
+</head>
+<body>
+	<h1>OpenAPI coverage report</h1>
+	<p>Operations: {{.OperationsHit}}/{{.OperationsTotal}}, status codes: {{.StatusCodesHit}}/{{.StatusCodesTotal}}</p>
+	<table>
+		<tr><th>Method</th><th>Path</th><th>Operation ID</th><th>Hits</th><th>Status codes</th><th>Undocumented status codes</th></tr>
+		{{- range .Operations}}
+		<tr class="{{if .Hits}}hit{{else}}missed{{end}}">
+			<td>{{.Method}}</td>
+			<td>{{.Path}}</td>
+			<td>{{.OperationID}}</td>
+			<td>{{.Hits}}</td>
+			<td>{{range .StatusCodes}}<span class="{{if .Hits}}hit{{else}}missed{{end}}">{{.Code}} ({{.Hits}})</span> {{end}}</td>
+			<td>{{range .Undocumented}}{{.}} {{end}}</td>
+		</tr>
+		{{- end}}
+	</table>
+	{{- if .Unmatched}}
+	<h2>Requests not matching any operation</h2>
+	<table>
+		<tr><th>Method</th><th>Path</th><th>Status code</th><th>Hits</th></tr>
+		{{- range .Unmatched}}
+		<tr><td>{{.Method}}</td><td>{{.Path}}</td><td>{{.StatusCode}}</td><td>{{.Hits}}</td></tr>
+		{{- end}}
+	</table>
+	{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,221 @@
+package coverage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const openAPIYAML = `
+openapi: 3.0.0
+servers:
+  - url: https://api.example.com/v1
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      responses:
+        200:
+          description: ok
+    post:
+      responses:
+        "201":
+          description: created
+        4XX:
+          description: client error
+  /users/{id}:
+    parameters:
+      - name: id
+        in: path
+    get:
+      responses:
+        "200":
+          description: ok
+        default:
+          description: error
+  /users/me:
+    get:
+      responses:
+        "200":
+          description: ok
+`
+
+const swaggerJSON = `{
+	"swagger": "2.0",
+	"basePath": "/api",
+	"paths": {
+		"/pets/{petId}": {
+			"delete": {"responses": {"204": {"description": "deleted"}}}
+		}
+	}
+}`
+
+func TestParseSpecification(t *testing.T) {
+	tests := []struct {
+		name           string
+		data           string
+		wantOperations []Operation
+		wantBasePaths  []string
+		wantErr        bool
+	}{
+		{name: "invalid document", data: "paths: [", wantErr: true},
+		{name: "document without operations", data: "openapi: 3.0.0\npaths: {}", wantErr: true},
+		{name: "OpenAPI 3 in YAML format", data: openAPIYAML, wantOperations: []Operation{
+			{Method: "GET", Path: "/users", OperationID: "listUsers", StatusCodes: []string{"200"}},
+			{Method: "POST", Path: "/users", StatusCodes: []string{"201", "4XX"}},
+			{Method: "GET", Path: "/users/me", StatusCodes: []string{"200"}},
+			{Method: "GET", Path: "/users/{id}", StatusCodes: []string{"200", "DEFAULT"}},
+		}, wantBasePaths: []string{"/v1"}},
+		{name: "Swagger 2.0 in JSON format", data: swaggerJSON, wantOperations: []Operation{
+			{Method: "DELETE", Path: "/pets/{petId}", StatusCodes: []string{"204"}},
+		}, wantBasePaths: []string{"/api"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSpecification([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSpecification() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			gotJSON, _ := json.Marshal(got.Operations)
+			wantJSON, _ := json.Marshal(tt.wantOperations)
+			if !bytes.Equal(gotJSON, wantJSON) {
+				t.Errorf("ParseSpecification() operations = %s, want %s", gotJSON, wantJSON)
+			}
+
+			if strings.Join(got.BasePaths, ",") != strings.Join(tt.wantBasePaths, ",") {
+				t.Errorf("ParseSpecification() base paths = %v, want %v", got.BasePaths, tt.wantBasePaths)
+			}
+		})
+	}
+}
+
+func TestParseSpecification_Empty(t *testing.T) {
+	if _, err := ParseSpecification([]byte("openapi: 3.0.0")); !errors.Is(err, ErrEmptySpecification) {
+		t.Errorf("expected ErrEmptySpecification, got %v", err)
+	}
+}
+
+func TestOpenAPIRecorder_Report(t *testing.T) {
+	spec, err := ParseSpecification([]byte(openAPIYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewOpenAPIRecorder(spec)
+	r.Record("get", "/v1/users", 200)
+	r.Record("GET", "/v1/users", 200)
+	r.Record("POST", "/v1/users", 422)
+	r.Record("GET", "/v1/users/me", 200)
+	r.Record("GET", "/v1/users/10", 404)
+	r.Record("GET", "/v1/users/10", 500)
+	r.Record("PUT", "/v1/users/10", 200)
+
+	report := r.Report()
+
+	if report.OperationsHit != 4 || report.OperationsTotal != 4 {
+		t.Errorf("operations hit %d/%d, expected 4/4", report.OperationsHit, report.OperationsTotal)
+	}
+
+	if report.StatusCodesHit != 4 || report.StatusCodesTotal != 6 {
+		t.Errorf("status codes hit %d/%d, expected 4/6", report.StatusCodesHit, report.StatusCodesTotal)
+	}
+
+	expected := map[string]map[string]int{
+		"GET /users":      {"200": 2},
+		"POST /users":     {"201": 0, "4XX": 1},
+		"GET /users/me":   {"200": 1},
+		"GET /users/{id}": {"200": 0, "DEFAULT": 2},
+	}
+
+	for _, op := range report.Operations {
+		for _, code := range op.StatusCodes {
+			if want := expected[op.Method+" "+op.Path][code.Code]; want != code.Hits {
+				t.Errorf("%s %s status code %s has %d hits, expected %d", op.Method, op.Path, code.Code, code.Hits, want)
+			}
+		}
+	}
+
+	if len(report.Unmatched) != 1 || report.Unmatched[0].Method != "PUT" || report.Unmatched[0].Hits != 1 {
+		t.Errorf("unexpected unmatched requests: %+v", report.Unmatched)
+	}
+}
+
+func TestOpenAPIRecorder_ReportUndocumentedStatusCode(t *testing.T) {
+	spec, err := ParseSpecification([]byte(openAPIYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewOpenAPIRecorder(spec)
+	r.Record("GET", "/users", 500)
+
+	for _, op := range r.Report().Operations {
+		if op.Method == "GET" && op.Path == "/users" {
+			if len(op.Undocumented) != 1 || op.Undocumented[0] != "500" {
+				t.Errorf("expected undocumented status code 500, got %v", op.Undocumented)
+			}
+
+			return
+		}
+	}
+
+	t.Errorf("operation GET /users not found in report")
+}
+
+func TestOpenAPIRecorder_ReportBasePathBoundary(t *testing.T) {
+	spec, err := ParseSpecification([]byte(openAPIYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewOpenAPIRecorder(spec)
+	r.Record("GET", "/v1users", 200)
+
+	report := r.Report()
+	if report.OperationsHit != 0 || len(report.Unmatched) != 1 || report.Unmatched[0].Path != "/v1users" {
+		t.Errorf("expected /v1users to be unmatched, got %d operations hit and unmatched requests: %+v", report.OperationsHit, report.Unmatched)
+	}
+}
+
+func TestReport_Write(t *testing.T) {
+	spec, err := ParseSpecification([]byte(swaggerJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewOpenAPIRecorder(spec)
+	r.Record("DELETE", "/api/pets/1", 204)
+	r.Record("GET", "/<script>", 404)
+	report := r.Report()
+
+	var jsonBuff bytes.Buffer
+	if err = report.WriteJSON(&jsonBuff); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	var decoded Report
+	if err = json.Unmarshal(jsonBuff.Bytes(), &decoded); err != nil {
+		t.Fatalf("WriteJSON() produced invalid JSON, err: %v", err)
+	}
+
+	if decoded.OperationsHit != 1 || len(decoded.Unmatched) != 1 {
+		t.Errorf("unexpected decoded report: %+v", decoded)
+	}
+
+	var htmlBuff bytes.Buffer
+	if err = report.WriteHTML(&htmlBuff); err != nil {
+		t.Fatalf("WriteHTML() error = %v", err)
+	}
+
+	html := htmlBuff.String()
+	if !strings.Contains(html, "/pets/{petId}") || strings.Contains(html, "<script>") {
+		t.Errorf("unexpected HTML report: %s", html)
+	}
+}
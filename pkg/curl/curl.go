@@ -0,0 +1,514 @@
+// Package curl holds utilities for converting curl commands into HTTP(s) requests.
+package curl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pawelWritesCode/gdutils/pkg/httpctx"
+)
+
+// ErrNotCurlCommand occurs when provided command does not start with curl.
+var ErrNotCurlCommand = errors.New("command should start with 'curl'")
+
+// Command holds information about parsed curl command.
+type Command struct {
+	// Method is HTTP method of request.
+	Method string
+
+	// URL is target URL of request.
+	URL string
+
+	// Headers are request headers in order of appearance.
+	Headers [][2]string
+
+	// Data are request body parts from -d, --data, --data-raw, --data-binary and --data-ascii flags.
+	Data []string
+
+	// Form are multipart form fields from -F and --form flags.
+	Form []string
+
+	// User holds credentials for basic auth in format user:password.
+	User string
+
+	// Cookie holds cookies in format name1=value1; name2=value2.
+	Cookie string
+
+	// Insecure tells whether TLS verification should be skipped.
+	Insecure bool
+
+	// Get tells whether data should be appended to URL as query instead of request body.
+	Get bool
+}
+
+// flagsWithValue are flags that require value.
+var flagsWithValue = map[string]string{
+	"-X": "request", "--request": "request",
+	"-H": "header", "--header": "header",
+	"-d": "data", "--data": "data", "--data-ascii": "data",
+	"--data-raw": "data-raw", "--data-binary": "data-binary", "--data-urlencode": "data-urlencode",
+	"-F": "form", "--form": "form",
+	"-u": "user", "--user": "user",
+	"-b": "cookie", "--cookie": "cookie",
+	"-A": "user-agent", "--user-agent": "user-agent",
+	"-e": "referer", "--referer": "referer",
+	"--url": "url",
+}
+
+// flagsWithoutValue are flags that do not require value.
+var flagsWithoutValue = map[string]string{
+	"-k": "insecure", "--insecure": "insecure",
+	"-G": "get", "--get": "get",
+	"-I": "head", "--head": "head",
+	"--compressed": "", "-s": "", "--silent": "", "-S": "", "--show-error": "",
+	"-L": "", "--location": "", "-v": "", "--verbose": "", "-i": "", "--include": "",
+}
+
+// Parse parses curl command into Command.
+func Parse(command string) (Command, error) {
+	args, err := Split(command)
+	if err != nil {
+		return Command{}, err
+	}
+
+	if len(args) == 0 || args[0] != "curl" {
+		return Command{}, ErrNotCurlCommand
+	}
+
+	var cmd Command
+	isHead := false
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			if cmd.URL != "" {
+				return Command{}, fmt.Errorf("multiple URLs are not supported: %s, %s", cmd.URL, arg)
+			}
+
+			cmd.URL = arg
+			continue
+		}
+
+		// long option or short options, which may be combined, for example -sSL or -XPOST
+		var options []string
+		if strings.HasPrefix(arg, "--") {
+			options = []string{arg}
+		} else {
+			for j := 1; j < len(arg); j++ {
+				option := "-" + arg[j:j+1]
+				if _, ok := flagsWithValue[option]; ok && j+1 < len(arg) {
+					options = append(options, option+"="+arg[j+1:])
+					break
+				}
+
+				options = append(options, option)
+			}
+		}
+
+		for _, option := range options {
+			name, value, hasValue := option, "", false
+			if !strings.HasPrefix(option, "--") && len(option) > 2 {
+				name, value, hasValue = option[:2], option[3:], true
+			}
+
+			if kind, ok := flagsWithoutValue[name]; ok {
+				switch kind {
+				case "insecure":
+					cmd.Insecure = true
+				case "get":
+					cmd.Get = true
+				case "head":
+					isHead = true
+				}
+
+				continue
+			}
+
+			if _, ok := flagsWithValue[name]; !ok {
+				return Command{}, fmt.Errorf("unsupported curl option: %s", name)
+			}
+
+			if !hasValue {
+				if i+1 >= len(args) {
+					return Command{}, fmt.Errorf("curl option %s requires value", name)
+				}
+
+				i++
+				value = args[i]
+			}
+
+			if err := cmd.apply(name, value); err != nil {
+				return Command{}, err
+			}
+		}
+	}
+
+	if cmd.URL == "" {
+		return Command{}, errors.New("curl command does not contain URL")
+	}
+
+	if !strings.Contains(cmd.URL, "://") {
+		cmd.URL = "http://" + cmd.URL
+	}
+
+	if len(cmd.Data) > 0 && len(cmd.Form) > 0 {
+		return Command{}, errors.New("curl command can't contain both data and form options")
+	}
+
+	if cmd.Method == "" {
+		switch {
+		case isHead:
+			cmd.Method = http.MethodHead
+		case cmd.Get:
+			cmd.Method = http.MethodGet
+		case len(cmd.Data) > 0 || len(cmd.Form) > 0:
+			cmd.Method = http.MethodPost
+		default:
+			cmd.Method = http.MethodGet
+		}
+	}
+
+	return cmd, nil
+}
+
+// apply applies curl option of given name with value on Command.
+func (c *Command) apply(name, value string) error {
+	switch flagsWithValue[name] {
+	case "request":
+		c.Method = strings.ToUpper(value)
+	case "header":
+		kv := strings.SplitN(value, ":", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("invalid header: %s, expected format 'Name: value'", value)
+		}
+
+		c.Headers = append(c.Headers, [2]string{strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])})
+	case "data", "data-binary":
+		if strings.HasPrefix(value, "@") {
+			content, err := ioutil.ReadFile(value[1:])
+			if err != nil {
+				return fmt.Errorf("could not read data from file %s, err: %w", value[1:], err)
+			}
+
+			value = string(content)
+
+			// like curl, --data strips carriage returns and newlines from file content, --data-binary does not
+			if flagsWithValue[name] == "data" {
+				value = strings.NewReplacer("\r", "", "\n", "").Replace(value)
+			}
+		}
+
+		c.Data = append(c.Data, value)
+	case "data-raw":
+		c.Data = append(c.Data, value)
+	case "data-urlencode":
+		c.Data = append(c.Data, urlEncodeData(value))
+	case "form":
+		c.Form = append(c.Form, value)
+	case "user":
+		c.User = value
+	case "cookie":
+		if !strings.Contains(value, "=") {
+			return fmt.Errorf("reading cookies from file '%s' is not supported", value)
+		}
+
+		if c.Cookie != "" {
+			c.Cookie += "; "
+		}
+
+		c.Cookie += value
+	case "user-agent":
+		c.Headers = append(c.Headers, [2]string{"User-Agent", value})
+	case "referer":
+		c.Headers = append(c.Headers, [2]string{"Referer", value})
+	case "url":
+		if c.URL != "" {
+			return fmt.Errorf("multiple URLs are not supported: %s, %s", c.URL, value)
+		}
+
+		c.URL = value
+	}
+
+	return nil
+}
+
+// Request creates *http.Request described by Command.
+func (c Command) Request() (*http.Request, error) {
+	rawURL := c.URL
+	var body io.Reader
+	contentType := ""
+
+	switch {
+	case len(c.Data) > 0 && c.Get:
+		separator := "?"
+		if strings.Contains(rawURL, "?") {
+			separator = "&"
+		}
+
+		rawURL += separator + strings.Join(c.Data, "&")
+	case len(c.Data) > 0:
+		body = strings.NewReader(strings.Join(c.Data, "&"))
+		contentType = "application/x-www-form-urlencoded"
+	case len(c.Form) > 0:
+		formBody, formContentType, err := c.multipartBody()
+		if err != nil {
+			return nil, err
+		}
+
+		body = formBody
+		contentType = formContentType
+	}
+
+	req, err := http.NewRequest(c.Method, rawURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("can't create request due to err: %w", err)
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	for _, header := range c.Headers {
+		if strings.EqualFold(header[0], "Host") {
+			req.Host = header[1]
+			continue
+		}
+
+		if strings.EqualFold(header[0], "Content-Type") {
+			// multipart boundary is generated during parsing, so it can't be overwritten
+			if len(c.Form) == 0 {
+				req.Header.Set(header[0], header[1])
+			}
+
+			continue
+		}
+
+		req.Header.Add(header[0], header[1])
+	}
+
+	if c.User != "" {
+		userPass := strings.SplitN(c.User, ":", 2)
+		password := ""
+		if len(userPass) == 2 {
+			password = userPass[1]
+		}
+
+		req.SetBasicAuth(userPass[0], password)
+	}
+
+	if c.Cookie != "" {
+		req.Header.Add("Cookie", c.Cookie)
+	}
+
+	if c.Insecure {
+		req = httpctx.WithInsecureSkipVerify(req)
+	}
+
+	return req, nil
+}
+
+// multipartBody creates multipart form body from Form fields.
+// Field values prefixed with @ are treated as files and values prefixed with < as files with field content.
+func (c Command) multipartBody() (io.Reader, string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	for _, field := range c.Form {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return nil, "", fmt.Errorf("invalid form field: %s, expected format 'name=value'", field)
+		}
+
+		name, value := kv[0], kv[1]
+		switch {
+		case strings.HasPrefix(value, "@"):
+			pth := strings.SplitN(value[1:], ";", 2)[0]
+			file, err := os.Open(pth)
+			if err != nil {
+				return nil, "", fmt.Errorf("could not open file %s, err: %w", pth, err)
+			}
+
+			part, err := writer.CreateFormFile(name, filepath.Base(pth))
+			if err != nil {
+				file.Close()
+				return nil, "", fmt.Errorf("writer could not create form file, err: %w", err)
+			}
+
+			_, err = io.Copy(part, file)
+			file.Close()
+			if err != nil {
+				return nil, "", fmt.Errorf("internal problem with copying, err: %w", err)
+			}
+		case strings.HasPrefix(value, "<"):
+			pth := strings.SplitN(value[1:], ";", 2)[0]
+			content, err := ioutil.ReadFile(pth)
+			if err != nil {
+				return nil, "", fmt.Errorf("could not read file %s, err: %w", pth, err)
+			}
+
+			if err = writer.WriteField(name, string(content)); err != nil {
+				return nil, "", fmt.Errorf("writer could not create form field, err: %w", err)
+			}
+		default:
+			if err := writer.WriteField(name, value); err != nil {
+				return nil, "", fmt.Errorf("writer could not create form field, err: %w", err)
+			}
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("problem with closing writer, err: %w", err)
+	}
+
+	return body, writer.FormDataContentType(), nil
+}
+
+// Split splits command into arguments the way POSIX shell does.
+// It supports single quotes, double quotes, $'...' quotes, backslash escapes and line continuations.
+func Split(command string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	runes := []rune(command)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\':
+			if i+1 >= len(runes) {
+				return nil, errors.New("command ends with unfinished escape sequence")
+			}
+
+			i++
+			if runes[i] == '\n' || (runes[i] == '\r' && i+1 < len(runes) && runes[i+1] == '\n') {
+				if runes[i] == '\r' {
+					i++
+				}
+
+				continue
+			}
+
+			current.WriteRune(runes[i])
+			inArg = true
+		case r == '\'':
+			end := indexRune(runes, '\'', i+1)
+			if end == -1 {
+				return nil, errors.New("command contains unterminated single quote")
+			}
+
+			current.WriteString(string(runes[i+1 : end]))
+			i = end
+			inArg = true
+		case r == '$' && i+1 < len(runes) && runes[i+1] == '\'':
+			next, err := readANSIQuoted(runes, i+2, &current)
+			if err != nil {
+				return nil, err
+			}
+
+			i = next
+			inArg = true
+		case r == '"':
+			next, err := readDoubleQuoted(runes, i+1, &current)
+			if err != nil {
+				return nil, err
+			}
+
+			i = next
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if inArg {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
+
+// readDoubleQuoted reads double-quoted string starting at index start into b and returns index of closing quote.
+func readDoubleQuoted(runes []rune, start int, b *strings.Builder) (int, error) {
+	for i := start; i < len(runes); i++ {
+		switch runes[i] {
+		case '"':
+			return i, nil
+		case '\\':
+			if i+1 < len(runes) && strings.ContainsRune("\"\\$`\n", runes[i+1]) {
+				i++
+				if runes[i] != '\n' {
+					b.WriteRune(runes[i])
+				}
+
+				continue
+			}
+
+			b.WriteRune(runes[i])
+		default:
+			b.WriteRune(runes[i])
+		}
+	}
+
+	return 0, errors.New("command contains unterminated double quote")
+}
+
+// readANSIQuoted reads $'...' quoted string starting at index start into b and returns index of closing quote.
+func readANSIQuoted(runes []rune, start int, b *strings.Builder) (int, error) {
+	escapes := map[rune]string{'n': "\n", 't': "\t", 'r': "\r", '\\': "\\", '\'': "'", '"': "\"", '0': "\x00"}
+	for i := start; i < len(runes); i++ {
+		switch runes[i] {
+		case '\'':
+			return i, nil
+		case '\\':
+			if i+1 < len(runes) {
+				if escaped, ok := escapes[runes[i+1]]; ok {
+					b.WriteString(escaped)
+					i++
+					continue
+				}
+			}
+
+			b.WriteRune(runes[i])
+		default:
+			b.WriteRune(runes[i])
+		}
+	}
+
+	return 0, errors.New("command contains unterminated $' quote")
+}
+
+// urlEncodeData encodes --data-urlencode value as curl does.
+func urlEncodeData(value string) string {
+	if idx := strings.Index(value, "="); idx != -1 {
+		return value[:idx+1] + url.QueryEscape(value[idx+1:])
+	}
+
+	return url.QueryEscape(value)
+}
+
+// indexRune returns index of first r in runes starting from start or -1 if not found.
+func indexRune(runes []rune, r rune, start int) int {
+	for i := start; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+
+	return -1
+}
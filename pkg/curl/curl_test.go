@@ -0,0 +1,199 @@
+package curl
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    []string
+		wantErr bool
+	}{
+		{name: "plain arguments", command: "curl -X POST http://a.com", want: []string{"curl", "-X", "POST", "http://a.com"}},
+		{name: "single quotes", command: `curl -H 'X-Name: a "b"'`, want: []string{"curl", "-H", `X-Name: a "b"`}},
+		{name: "double quotes with escapes", command: `curl -d "{\"a\": \"\$b\"}"`, want: []string{"curl", "-d", `{"a": "$b"}`}},
+		{name: "ANSI-C quotes", command: `curl --data-raw $'line1\nit\'s'`, want: []string{"curl", "--data-raw", "line1\nit's"}},
+		{name: "line continuations", command: "curl \\\n  -k \\\r\n  http://a.com", want: []string{"curl", "-k", "http://a.com"}},
+		{name: "escaped space", command: `curl http://a.com/a\ b`, want: []string{"curl", "http://a.com/a b"}},
+		{name: "unterminated single quote", command: `curl 'abc`, wantErr: true},
+		{name: "unterminated double quote", command: `curl "abc`, wantErr: true},
+		{name: "unfinished escape", command: `curl \`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Split(tt.command)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Split() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    Command
+		wantErr bool
+	}{
+		{name: "not curl", command: "wget http://a.com", wantErr: true},
+		{name: "missing URL", command: "curl -X GET", wantErr: true},
+		{name: "missing option value", command: "curl http://a.com -H", wantErr: true},
+		{name: "unsupported option", command: "curl http://a.com --proxy http://b.com", wantErr: true},
+		{name: "invalid header", command: "curl http://a.com -H abc", wantErr: true},
+		{name: "cookie jar file", command: "curl http://a.com -b cookies.txt", wantErr: true},
+		{name: "data and form", command: "curl http://a.com -d a=b -F c=d", wantErr: true},
+		{name: "simple GET without scheme", command: "curl a.com/users", want: Command{Method: "GET", URL: "http://a.com/users"}},
+		{name: "data implies POST", command: `curl https://a.com -H 'Content-Type: application/json' -d '{"a":1}'`, want: Command{
+			Method: "POST", URL: "https://a.com", Headers: [][2]string{{"Content-Type", "application/json"}}, Data: []string{`{"a":1}`},
+		}},
+		{name: "explicit method, combined flags, auth and cookies", command: "curl -sSk -XPUT --url https://a.com -u user:pass --cookie 'a=1' -b b=2 --data-binary x --data-raw y", want: Command{
+			Method: "PUT", URL: "https://a.com", User: "user:pass", Cookie: "a=1; b=2", Insecure: true, Data: []string{"x", "y"},
+		}},
+		{name: "get with data", command: "curl -G http://a.com --data-urlencode 'q=a b'", want: Command{
+			Method: "GET", URL: "http://a.com", Get: true, Data: []string{"q=a+b"},
+		}},
+		{name: "head", command: "curl -I http://a.com", want: Command{Method: "HEAD", URL: "http://a.com"}},
+		{name: "form", command: "curl http://a.com -F name=abc --form 'file=@/tmp/x.txt;type=text/plain'", want: Command{
+			Method: "POST", URL: "http://a.com", Form: []string{"name=abc", "file=@/tmp/x.txt;type=text/plain"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.command)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_NotCurl(t *testing.T) {
+	if _, err := Parse("http http://a.com"); !errors.Is(err, ErrNotCurlCommand) {
+		t.Errorf("expected ErrNotCurlCommand, got %v", err)
+	}
+}
+
+func TestParse_DataFromFile(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), "body.json")
+	if err := ioutil.WriteFile(pth, []byte("{\n\"a\": 1\n}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, err := Parse("curl http://a.com -d @" + pth + " --data-binary @" + pth)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cmd.Data[0] != `{"a": 1}` || cmd.Data[1] != "{\n\"a\": 1\n}" {
+		t.Errorf("unexpected data read from file: %q", cmd.Data)
+	}
+}
+
+func TestCommand_Request(t *testing.T) {
+	dir := t.TempDir()
+	pth := filepath.Join(dir, "avatar.txt")
+	if err := ioutil.WriteFile(pth, []byte("file content"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("data request", func(t *testing.T) {
+		cmd, err := Parse(`curl https://a.com/users -H 'Host: b.com' -H 'X-A: 1' -H 'X-A: 2' -u user:pass -b 'sid=abc' -d 'a=1' -d 'b=2'`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req, err := cmd.Request()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, _ := ioutil.ReadAll(req.Body)
+		if string(body) != "a=1&b=2" || req.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+			t.Errorf("unexpected body %s or content type %s", body, req.Header.Get("Content-Type"))
+		}
+
+		if user, pass, ok := req.BasicAuth(); !ok || user != "user" || pass != "pass" {
+			t.Errorf("unexpected basic auth: %s %s", user, pass)
+		}
+
+		if c, err := req.Cookie("sid"); err != nil || c.Value != "abc" {
+			t.Errorf("unexpected cookie: %v, err: %v", c, err)
+		}
+
+		if req.Host != "b.com" || !reflect.DeepEqual(req.Header.Values("X-A"), []string{"1", "2"}) {
+			t.Errorf("unexpected host %s or headers %v", req.Host, req.Header)
+		}
+	})
+
+	t.Run("query request", func(t *testing.T) {
+		cmd, err := Parse(`curl -G 'https://a.com/users?limit=1' -d page=2`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req, err := cmd.Request()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if req.URL.String() != "https://a.com/users?limit=1&page=2" || req.Body != nil {
+			t.Errorf("unexpected URL %s or body", req.URL)
+		}
+	})
+
+	t.Run("form request", func(t *testing.T) {
+		cmd, err := Parse(`curl https://a.com -H 'Content-Type: text/plain' -F name=abc -F 'avatar=@` + pth + `;type=text/plain' -F 'bio=<` + pth + `'`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req, err := cmd.Request()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/form-data") {
+			t.Fatalf("unexpected content type: %s", req.Header.Get("Content-Type"))
+		}
+
+		if err = req.ParseMultipartForm(1024); err != nil {
+			t.Fatal(err)
+		}
+
+		if req.FormValue("name") != "abc" || req.FormValue("bio") != "file content" {
+			t.Errorf("unexpected form values: %v", req.MultipartForm.Value)
+		}
+
+		if files := req.MultipartForm.File["avatar"]; len(files) != 1 || files[0].Filename != "avatar.txt" {
+			t.Errorf("unexpected form files: %v", req.MultipartForm.File)
+		}
+	})
+
+	t.Run("form request with missing file", func(t *testing.T) {
+		cmd, err := Parse(`curl https://a.com -F 'avatar=@` + filepath.Join(dir, "missing") + `'`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = cmd.Request(); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected missing file error, got %v", err)
+		}
+	})
+}
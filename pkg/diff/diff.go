@@ -0,0 +1,166 @@
+// Package diff holds utilities for presenting differences between expected and actual values in readable form.
+// Strings are presented as unified diff, structured data as list of differing paths.
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+
+	"github.com/pawelWritesCode/gdutils/pkg/compare"
+)
+
+const (
+	// DefaultContext is default number of unchanged lines shown around changed ones in unified diff.
+	DefaultContext = 3
+
+	// excerptRadius is number of runes shown around first difference of single line strings.
+	excerptRadius = 40
+)
+
+// Differ is entity that has ability to describe differences between expected and actual values.
+type Differ struct {
+	// Colored tells whether output should be colored with ANSI escape codes.
+	Colored bool
+
+	// Context is number of unchanged lines shown around changed ones in unified diff.
+	Context int
+}
+
+// New returns Differ with default context. Output is colored only when colored is true
+// and terminal supports colors, that is NO_COLOR environment variable is not set and output is terminal.
+func New(colored bool) Differ {
+	return Differ{Colored: colored && !color.NoColor, Context: DefaultContext}
+}
+
+// Strings describes differences between expected and actual string. Multi-line strings are presented as unified diff,
+// single line strings as excerpts around first difference. Empty string is returned when strings are equal.
+func (d Differ) Strings(expected, actual string) string {
+	if expected == actual {
+		return ""
+	}
+
+	if !strings.Contains(expected, "\n") && !strings.Contains(actual, "\n") {
+		return d.singleLine(expected, actual)
+	}
+
+	return d.Unified(expected, actual)
+}
+
+// Unified returns unified diff of expected and actual text compared line by line.
+func (d Differ) Unified(expected, actual string) string {
+	a, b := strings.Split(expected, "\n"), strings.Split(actual, "\n")
+	edits := editScript(a, b)
+
+	var sb strings.Builder
+	sb.WriteString(d.colorize("--- expected", '-') + "\n")
+	sb.WriteString(d.colorize("+++ actual", '+') + "\n")
+
+	for _, h := range hunks(edits, d.Context) {
+		sb.WriteString(d.colorize(h.header(), '@') + "\n")
+		for _, e := range h.edits {
+			sb.WriteString(d.colorize(string(e.kind)+e.text, e.kind) + "\n")
+		}
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// Values describes differences between expected and actual deserialized data path by path.
+// Empty string is returned when values are equal.
+func (d Differ) Values(expected, actual interface{}) string {
+	if e, ok := expected.(string); ok {
+		if a, ok := actual.(string); ok {
+			return d.Strings(e, a)
+		}
+	}
+
+	return d.Differences(compare.Compare(compare.Normalize(expected), compare.Normalize(actual), compare.ModeExact, nil))
+}
+
+// Differences presents differences found by compare package, one path per line.
+// Differences between long or multi-line strings are followed by their diff.
+func (d Differ) Differences(differences compare.Differences) string {
+	lines := make([]string, 0, len(differences))
+	for _, difference := range differences {
+		lines = append(lines, d.colorize(difference.String(), '!'))
+
+		e, eOk := difference.Expected.(string)
+		a, aOk := difference.Actual.(string)
+		if eOk && aOk && (strings.Contains(e+a, "\n") || utf8.RuneCountInString(e) > excerptRadius || utf8.RuneCountInString(a) > excerptRadius) {
+			lines = append(lines, indent(d.Strings(e, a), "    "))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// singleLine presents excerpts of expected and actual string around first difference, marked with caret.
+func (d Differ) singleLine(expected, actual string) string {
+	e, a := []rune(expected), []rune(actual)
+
+	pos := 0
+	for pos < len(e) && pos < len(a) && e[pos] == a[pos] {
+		pos++
+	}
+
+	start := pos - excerptRadius
+	prefix := ""
+	if start > 0 {
+		prefix = "..."
+	} else {
+		start = 0
+	}
+
+	excerpt := func(r []rune) string {
+		if start > len(r) {
+			return prefix
+		}
+
+		end := pos + excerptRadius
+		if end >= len(r) {
+			return prefix + string(r[start:])
+		}
+
+		return prefix + string(r[start:end]) + "..."
+	}
+
+	marker := strings.Repeat(" ", len("+ actual:   ")+utf8.RuneCountInString(prefix)+pos-start) + "^"
+
+	return strings.Join([]string{
+		d.colorize("- expected: "+excerpt(e), '-'),
+		d.colorize("+ actual:   "+excerpt(a), '+'),
+		marker + fmt.Sprintf(" first difference at position %d", pos),
+	}, "\n")
+}
+
+// colorize colors line according to its kind: '-' removed, '+' added, '@' hunk header, '!' difference.
+func (d Differ) colorize(line string, kind byte) string {
+	if !d.Colored {
+		return line
+	}
+
+	var c *color.Color
+	switch kind {
+	case '-':
+		c = color.New(color.FgRed)
+	case '+':
+		c = color.New(color.FgGreen)
+	case '@':
+		c = color.New(color.FgCyan)
+	case '!':
+		c = color.New(color.FgYellow)
+	default:
+		return line
+	}
+
+	c.EnableColor()
+
+	return c.Sprint(line)
+}
+
+func indent(text, prefix string) string {
+	return prefix + strings.ReplaceAll(text, "\n", "\n"+prefix)
+}
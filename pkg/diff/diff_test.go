@@ -0,0 +1,167 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDiffer_Unified(t *testing.T) {
+	d := Differ{Context: 1}
+
+	expected := "a\nb\nc\nd\ne\nf\ng"
+	actual := "a\nB\nc\nd\ne\nf\ng\nh"
+	want := strings.Join([]string{
+		"--- expected",
+		"+++ actual",
+		"@@ -1,3 +1,3 @@",
+		" a",
+		"-b",
+		"+B",
+		" c",
+		"@@ -7,1 +7,2 @@",
+		" g",
+		"+h",
+	}, "\n")
+
+	if got := d.Unified(expected, actual); got != want {
+		t.Errorf("Unified() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDiffer_Unified_emptyExpected(t *testing.T) {
+	got := Differ{Context: DefaultContext}.Unified("", "x\ny")
+	want := "--- expected\n+++ actual\n@@ -1,1 +1,2 @@\n-\n+x\n+y"
+
+	if got != want {
+		t.Errorf("Unified() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDiffer_Unified_largeInput(t *testing.T) {
+	expected := make([]string, 0, 4000)
+	actual := make([]string, 0, 4000)
+	for i := 0; i < 4000; i++ {
+		expected = append(expected, fmt.Sprintf("line %d", i))
+		actual = append(actual, fmt.Sprintf("changed %d", i))
+	}
+	actual[2000] = expected[2000]
+
+	got := Differ{Context: 1}.Unified(strings.Join(expected, "\n"), strings.Join(actual, "\n"))
+	if removed, added := strings.Count(got, "\n-line"), strings.Count(got, "\n+changed"); removed != 3999 || added != 3999 {
+		t.Errorf("Unified() removed %d and added %d lines, want 3999 of each", removed, added)
+	}
+
+	if !strings.Contains(got, "\n line 2000\n") {
+		t.Errorf("Unified() should keep common line of completely different inputs")
+	}
+}
+
+func TestEditScript(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		edits int
+	}{
+		{a: "", b: "", edits: 0},
+		{a: "abc", b: "abc", edits: 0},
+		{a: "abcabba", b: "cbabac", edits: 5},
+		{a: "abc", b: "", edits: 3},
+		{a: "", b: "abc", edits: 3},
+		{a: "xaxbx", b: "ab", edits: 3},
+		{a: "abcdefg", b: "gfedcba", edits: 12},
+	}
+
+	for _, tt := range tests {
+		a, b := strings.Split(tt.a, ""), strings.Split(tt.b, "")
+		script := editScript(a, b)
+
+		edits, x, y := 0, 0, 0
+		for _, e := range script {
+			if e.aPos != x || e.bPos != y {
+				t.Fatalf("editScript(%q, %q) = %+v, edit at wrong position", tt.a, tt.b, script)
+			}
+
+			switch e.kind {
+			case ' ':
+				if a[x] != b[y] {
+					t.Fatalf("editScript(%q, %q) = %+v, different lines kept", tt.a, tt.b, script)
+				}
+				x++
+				y++
+			case '-':
+				edits++
+				x++
+			case '+':
+				edits++
+				y++
+			}
+		}
+
+		if x != len(a) || y != len(b) || edits != tt.edits {
+			t.Errorf("editScript(%q, %q) has %d edits and ends at %d, %d, want %d edits", tt.a, tt.b, edits, x, y, tt.edits)
+		}
+	}
+}
+
+func TestDiffer_Strings(t *testing.T) {
+	d := Differ{Context: DefaultContext}
+
+	if got := d.Strings("abc", "abc"); got != "" {
+		t.Errorf("Strings() of equal strings = %q", got)
+	}
+
+	want := "- expected: abcd\n+ actual:   abXd\n              ^ first difference at position 2"
+	if got := d.Strings("abcd", "abXd"); got != want {
+		t.Errorf("Strings() =\n%s\nwant\n%s", got, want)
+	}
+
+	long := strings.Repeat("x", 100)
+	got := d.Strings(long+"a"+long, long+"b"+long)
+	if !strings.Contains(got, "- expected: ..."+strings.Repeat("x", 40)+"a"+strings.Repeat("x", 39)+"...") {
+		t.Errorf("Strings() should present excerpt around difference, got:\n%s", got)
+	}
+
+	if !strings.HasPrefix(d.Strings("a\nb", "a\nc"), "--- expected") {
+		t.Errorf("Strings() should present multi-line strings as unified diff")
+	}
+}
+
+func TestDiffer_Values(t *testing.T) {
+	d := Differ{Context: DefaultContext}
+
+	got := d.Values(
+		map[string]interface{}{"id": 1, "tags": []interface{}{"a"}, "bio": "line 1\nline 2"},
+		map[string]interface{}{"id": 2.0, "tags": []interface{}{"a", "b"}, "bio": "line 1\nline 3"},
+	)
+	want := strings.Join([]string{
+		`$.bio: expected string "line 1\nline 2", got string "line 1\nline 3"`,
+		"    --- expected",
+		"    +++ actual",
+		"    @@ -1,2 +1,2 @@",
+		"     line 1",
+		"    -line 2",
+		"    +line 3",
+		"$.id: expected number 1, got number 2",
+		`$.tags[1]: unexpected element string "b"`,
+	}, "\n")
+
+	if got != want {
+		t.Errorf("Values() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := d.Values(map[string]interface{}{"a": 1}, map[string]interface{}{"a": 1.0}); got != "" {
+		t.Errorf("Values() of equal data = %q", got)
+	}
+}
+
+func TestDiffer_colors(t *testing.T) {
+	d := Differ{Colored: true, Context: DefaultContext}
+
+	if got := d.Strings("a", "b"); !strings.Contains(got, "\x1b[31m- expected: a") {
+		t.Errorf("Strings() should be colored, got %q", got)
+	}
+
+	if got := (Differ{}).Strings("a", "b"); strings.Contains(got, "\x1b[") {
+		t.Errorf("Strings() should not be colored, got %q", got)
+	}
+}
@@ -0,0 +1,261 @@
+package diff
+
+import "fmt"
+
+// edit is single line of edit script: unchanged (' '), removed from expected ('-') or added in actual ('+').
+type edit struct {
+	kind byte
+	text string
+
+	// aPos and bPos are numbers of expected and actual lines preceding edit.
+	aPos, bPos int
+}
+
+// hunk is group of edits with surrounding context.
+type hunk struct {
+	edits []edit
+}
+
+func (h hunk) header() string {
+	aCount, bCount := 0, 0
+	for _, e := range h.edits {
+		if e.kind != '+' {
+			aCount++
+		}
+
+		if e.kind != '-' {
+			bCount++
+		}
+	}
+
+	return fmt.Sprintf("@@ -%s +%s @@", hunkRange(h.edits[0].aPos, aCount), hunkRange(h.edits[0].bPos, bCount))
+}
+
+func hunkRange(pos, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", pos)
+	}
+
+	return fmt.Sprintf("%d,%d", pos+1, count)
+}
+
+// editScript returns shortest edit script transforming a into b, found with linear space variant of Myers algorithm,
+// so memory usage grows with number of lines instead of number of lines multiplied by number of differences.
+func editScript(a, b []string) []edit {
+	m := &myers{a: a, b: b, edits: make([]edit, 0, len(a)+len(b))}
+	m.compare(0, len(a), 0, len(b))
+
+	return removalsFirst(m.edits)
+}
+
+// removalsFirst reorders every run of changed lines, so removed lines precede added ones.
+func removalsFirst(edits []edit) []edit {
+	for start := 0; start < len(edits); start++ {
+		if edits[start].kind == ' ' {
+			continue
+		}
+
+		end := start
+		var removed, added []edit
+		for ; end < len(edits) && edits[end].kind != ' '; end++ {
+			if edits[end].kind == '-' {
+				removed = append(removed, edits[end])
+			} else {
+				added = append(added, edits[end])
+			}
+		}
+
+		aPos, bPos := edits[start].aPos, edits[start].bPos
+		for i, e := range append(removed, added...) {
+			e.aPos, e.bPos = aPos, bPos
+			if e.kind == '-' {
+				aPos++
+			} else {
+				bPos++
+			}
+
+			edits[start+i] = e
+		}
+
+		start = end
+	}
+
+	return edits
+}
+
+// myers finds edit script by dividing compared ranges of lines at middle snake, that is part of shortest edit path
+// found by searching from both ends at once.
+type myers struct {
+	a, b  []string
+	edits []edit
+}
+
+// compare appends edit script transforming a[aLo:aHi] into b[bLo:bHi].
+func (m *myers) compare(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && m.a[aLo] == m.b[bLo] {
+		m.edits = append(m.edits, edit{kind: ' ', text: m.a[aLo], aPos: aLo, bPos: bLo})
+		aLo++
+		bLo++
+	}
+
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && m.a[aHi-suffix-1] == m.b[bHi-suffix-1] {
+		suffix++
+	}
+
+	aEnd, bEnd := aHi-suffix, bHi-suffix
+	switch {
+	case aLo == aEnd:
+		for y := bLo; y < bEnd; y++ {
+			m.edits = append(m.edits, edit{kind: '+', text: m.b[y], aPos: aLo, bPos: y})
+		}
+	case bLo == bEnd:
+		for x := aLo; x < aEnd; x++ {
+			m.edits = append(m.edits, edit{kind: '-', text: m.a[x], aPos: x, bPos: bLo})
+		}
+	default:
+		x1, y1, x2, y2 := m.middleSnake(aLo, aEnd, bLo, bEnd)
+		m.compare(aLo, x1, bLo, y1)
+		m.snake(x1, y1, x2, y2)
+		m.compare(x2, aEnd, y2, bEnd)
+	}
+
+	for i := 0; i < suffix; i++ {
+		m.edits = append(m.edits, edit{kind: ' ', text: m.a[aEnd+i], aPos: aEnd + i, bPos: bEnd + i})
+	}
+}
+
+// snake appends edits of middle snake from (x1, y1) to (x2, y2): equal lines and at most one removed or added line.
+func (m *myers) snake(x1, y1, x2, y2 int) {
+	for x1 < x2 && y1 < y2 && m.a[x1] == m.b[y1] {
+		m.edits = append(m.edits, edit{kind: ' ', text: m.a[x1], aPos: x1, bPos: y1})
+		x1++
+		y1++
+	}
+
+	if x2-x1 > y2-y1 {
+		m.edits = append(m.edits, edit{kind: '-', text: m.a[x1], aPos: x1, bPos: y1})
+		x1++
+	} else if y2-y1 > x2-x1 {
+		m.edits = append(m.edits, edit{kind: '+', text: m.b[y1], aPos: x1, bPos: y1})
+		y1++
+	}
+
+	for x1 < x2 && y1 < y2 {
+		m.edits = append(m.edits, edit{kind: ' ', text: m.a[x1], aPos: x1, bPos: y1})
+		x1++
+		y1++
+	}
+}
+
+// middleSnake returns start and end of middle snake of shortest edit path transforming a[aLo:aHi] into b[bLo:bHi].
+// Both ranges should be non-empty.
+func (m *myers) middleSnake(aLo, aHi, bLo, bHi int) (int, int, int, int) {
+	n, l := aHi-aLo, bHi-bLo
+	max := (n + l + 1) / 2
+	delta := n - l
+	odd := delta%2 != 0
+
+	// forward holds furthest x on diagonal k = x - y, backward holds furthest y on diagonal c = k - delta
+	// searched from the end, both relative to range start and indexed from offset.
+	offset := max + 1
+	forward := make([]int, 2*max+3)
+	backward := make([]int, 2*max+3)
+	forward[offset+1] = 0
+	backward[offset+1] = l
+
+	for d := 0; d <= max; d++ {
+		for k := -d; k <= d; k += 2 {
+			var px, x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				px = forward[offset+k+1]
+				x = px
+			} else {
+				px = forward[offset+k-1]
+				x = px + 1
+			}
+
+			y, py := x-k, x-k
+			if d != 0 && x == px {
+				py--
+			}
+
+			for x < n && y < l && x >= 0 && y >= 0 && m.a[aLo+x] == m.b[bLo+y] {
+				x++
+				y++
+			}
+
+			forward[offset+k] = x
+			if c := k - delta; odd && c >= -(d-1) && c <= d-1 && y >= backward[offset+c] {
+				return aLo + px, bLo + py, aLo + x, bLo + y
+			}
+		}
+
+		for c := -d; c <= d; c += 2 {
+			var py, y int
+			if c == -d || (c != d && backward[offset+c-1] > backward[offset+c+1]) {
+				py = backward[offset+c+1]
+				y = py
+			} else {
+				py = backward[offset+c-1]
+				y = py - 1
+			}
+
+			k := c + delta
+			x, px := y+k, y+k
+			if d != 0 && y == py {
+				px++
+			}
+
+			for x > 0 && y > 0 && x <= n && y <= l && m.a[aLo+x-1] == m.b[bLo+y-1] {
+				x--
+				y--
+			}
+
+			backward[offset+c] = y
+			if !odd && k >= -d && k <= d && x <= forward[offset+k] {
+				return aLo + x, bLo + y, aLo + px, bLo + py
+			}
+		}
+	}
+
+	return aLo, bLo, aHi, bHi
+}
+
+// hunks groups changed lines of edit script with context lines around them.
+func hunks(edits []edit, context int) []hunk {
+	var result []hunk
+
+	start, end := -1, -1
+	for i, e := range edits {
+		if e.kind == ' ' {
+			continue
+		}
+
+		from, to := i-context, i+context+1
+		if from < 0 {
+			from = 0
+		}
+
+		if to > len(edits) {
+			to = len(edits)
+		}
+
+		if start != -1 && from <= end {
+			end = to
+			continue
+		}
+
+		if start != -1 {
+			result = append(result, hunk{edits: edits[start:end]})
+		}
+
+		start, end = from, to
+	}
+
+	if start != -1 {
+		result = append(result, hunk{edits: edits[start:end]})
+	}
+
+	return result
+}
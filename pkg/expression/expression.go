@@ -0,0 +1,93 @@
+// Package expression holds utilities for evaluating boolean expressions written in expr language.
+// Language definition is available at: https://github.com/antonmedv/expr/blob/master/docs/Language-Definition.md
+package expression
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/antonmedv/expr"
+	"github.com/goccy/go-yaml"
+
+	"github.com/pawelWritesCode/gdutils/pkg/compare"
+	"github.com/pawelWritesCode/gdutils/pkg/format"
+)
+
+const (
+	// VarStatus is name of variable holding HTTP(s) response status code.
+	VarStatus = "status"
+
+	// VarHeaders is name of variable holding HTTP(s) response headers keyed by lowercase names.
+	// Values of many header lines are joined with comma.
+	VarHeaders = "headers"
+
+	// VarCookies is name of variable holding HTTP(s) response cookies values keyed by names.
+	VarCookies = "cookies"
+
+	// VarBody is name of variable holding deserialized JSON or YAML response body, it is nil for other formats.
+	VarBody = "body"
+
+	// VarRawBody is name of variable holding response body as string.
+	VarRawBody = "rawBody"
+
+	// VarCache is name of variable holding cache data.
+	VarCache = "cache"
+)
+
+// Evaluate evaluates boolean expression against provided environment.
+func Evaluate(expression string, env map[string]interface{}) (bool, error) {
+	program, err := expr.Compile(expression, expr.Env(env), expr.AsBool())
+	if err != nil {
+		return false, fmt.Errorf("could not compile expression, err: %w", err)
+	}
+
+	result, err := expr.Run(program, env)
+	if err != nil {
+		return false, fmt.Errorf("could not evaluate expression, err: %w", err)
+	}
+
+	return result.(bool), nil
+}
+
+// NewResponseEnv returns environment describing HTTP(s) response and cache data.
+// body should be already read response body, because response body may be read only once.
+func NewResponseEnv(resp *http.Response, body []byte, cache map[string]interface{}) (map[string]interface{}, error) {
+	headers := make(map[string]interface{}, len(resp.Header))
+	for name, values := range resp.Header {
+		headers[strings.ToLower(name)] = strings.Join(values, ", ")
+	}
+
+	cookies := make(map[string]interface{})
+	for _, cookie := range resp.Cookies() {
+		cookies[cookie.Name] = cookie.Value
+	}
+
+	var parsedBody interface{}
+	switch {
+	case format.IsJSON(body):
+		if err := json.Unmarshal(body, &parsedBody); err != nil {
+			return nil, fmt.Errorf("could not deserialize JSON response body, err: %w", err)
+		}
+	case format.IsYAML(body):
+		if err := yaml.Unmarshal(body, &parsedBody); err != nil {
+			return nil, fmt.Errorf("could not deserialize YAML response body, err: %w", err)
+		}
+
+		parsedBody = compare.Normalize(parsedBody)
+	}
+
+	if cache == nil {
+		cache = map[string]interface{}{}
+	}
+
+	return map[string]interface{}{
+		VarStatus:  resp.StatusCode,
+		VarHeaders: headers,
+		VarCookies: cookies,
+		VarBody:    parsedBody,
+		VarRawBody: string(body),
+		VarCache:   cache,
+	}, nil
+}
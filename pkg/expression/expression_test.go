@@ -0,0 +1,59 @@
+package expression
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Content-Type": {"application/json"},
+			"Set-Cookie":   {"sid=abc"},
+		},
+	}
+	body := []byte(`{"items": [{"price": 10}, {"price": 2.5}], "total": 2}`)
+
+	env, err := NewResponseEnv(resp, body, map[string]interface{}{"USER_ID": 10})
+	if err != nil {
+		t.Fatalf("NewResponseEnv() unexpected error = %v", err)
+	}
+
+	tests := []struct {
+		expression string
+		want       bool
+		wantErr    bool
+	}{
+		{expression: `all(body.items, {.price > 0}) && status == 200`, want: true},
+		{expression: `len(body.items) == body.total && any(body.items, {.price == 2.5})`, want: true},
+		{expression: `headers["content-type"] startsWith "application/json" && cookies.sid == "abc"`, want: true},
+		{expression: `cache.USER_ID == body.items[0].price`, want: true},
+		{expression: `rawBody contains "total" && status in 400..499`, want: false},
+		{expression: `status + 1`, wantErr: true},
+		{expression: `status ==`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			got, err := Evaluate(tt.expression, env)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Evaluate() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewResponseEnv_yaml(t *testing.T) {
+	env, err := NewResponseEnv(&http.Response{StatusCode: http.StatusCreated}, []byte("user:\n  name: ivo\n"), nil)
+	if err != nil {
+		t.Fatalf("NewResponseEnv() unexpected error = %v", err)
+	}
+
+	if got, err := Evaluate(`body.user.name == "ivo" && status == 201`, env); err != nil || !got {
+		t.Errorf("Evaluate() = %t, err: %v", got, err)
+	}
+}
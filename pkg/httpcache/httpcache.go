@@ -9,3 +9,6 @@ const LastHTTPRequestTimestamp = "LAST_HTTP_REQUEST_TIMESTAMP"
 
 // LastHTTPResponseTimestamp represents response timestamp
 const LastHTTPResponseTimestamp = "LAST_HTTP_RESPONSE_TIMESTAMP"
+
+// LastHTTPResponseTimings represents timings of last HTTP(s) request-response phases
+const LastHTTPResponseTimings = "LAST_HTTP_RESPONSE_TIMINGS"
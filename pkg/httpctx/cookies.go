@@ -0,0 +1,133 @@
+package httpctx
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CookieAttribute describes attribute of cookie set by Set-Cookie header.
+type CookieAttribute string
+
+const (
+	// CookieAttributeSecure describes Secure flag, its value is true or false.
+	CookieAttributeSecure CookieAttribute = "Secure"
+
+	// CookieAttributeHttpOnly describes HttpOnly flag, its value is true or false.
+	CookieAttributeHttpOnly CookieAttribute = "HttpOnly"
+
+	// CookieAttributeSameSite describes SameSite attribute, its value is Strict, Lax, None or empty string.
+	CookieAttributeSameSite CookieAttribute = "SameSite"
+
+	// CookieAttributePath describes Path attribute.
+	CookieAttributePath CookieAttribute = "Path"
+
+	// CookieAttributeDomain describes Domain attribute.
+	CookieAttributeDomain CookieAttribute = "Domain"
+)
+
+// ParseCookieAttribute returns CookieAttribute of given case-insensitive name.
+func ParseCookieAttribute(name string) (CookieAttribute, error) {
+	for _, attribute := range []CookieAttribute{CookieAttributeSecure, CookieAttributeHttpOnly, CookieAttributeSameSite, CookieAttributePath, CookieAttributeDomain} {
+		if strings.EqualFold(strings.TrimSpace(name), string(attribute)) {
+			return attribute, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown cookie attribute: %s, available attributes: %s, %s, %s, %s, %s", name,
+		CookieAttributeSecure, CookieAttributeHttpOnly, CookieAttributeSameSite, CookieAttributePath, CookieAttributeDomain)
+}
+
+// CookieAttributeValue returns value of cookie attribute.
+func CookieAttributeValue(cookie *http.Cookie, attribute CookieAttribute) (string, error) {
+	switch attribute {
+	case CookieAttributeSecure:
+		return strconv.FormatBool(cookie.Secure), nil
+	case CookieAttributeHttpOnly:
+		return strconv.FormatBool(cookie.HttpOnly), nil
+	case CookieAttributeSameSite:
+		return sameSiteName(cookie.SameSite), nil
+	case CookieAttributePath:
+		return cookie.Path, nil
+	case CookieAttributeDomain:
+		return cookie.Domain, nil
+	default:
+		return "", fmt.Errorf("unknown cookie attribute: %s", attribute)
+	}
+}
+
+// CookieAttributeEqual checks whether actual value of cookie attribute equals expected one.
+// SameSite and Domain are compared case-insensitively, leading dot of Domain is ignored.
+func CookieAttributeEqual(attribute CookieAttribute, expected, actual string) bool {
+	switch attribute {
+	case CookieAttributeSameSite:
+		return strings.EqualFold(expected, actual)
+	case CookieAttributeDomain:
+		return strings.EqualFold(strings.TrimPrefix(expected, "."), strings.TrimPrefix(actual, "."))
+	case CookieAttributeSecure, CookieAttributeHttpOnly:
+		b, err := strconv.ParseBool(expected)
+		return err == nil && strconv.FormatBool(b) == actual
+	default:
+		return expected == actual
+	}
+}
+
+// CookieExpiry returns moment when cookie expires, Max-Age has precedence over Expires.
+// False is returned for session cookie, which expires when browser is closed.
+func CookieExpiry(cookie *http.Cookie, now time.Time) (time.Time, bool) {
+	switch {
+	case cookie.MaxAge > 0:
+		return now.Add(time.Duration(cookie.MaxAge) * time.Second), true
+	case cookie.MaxAge < 0:
+		return now, true
+	case !cookie.Expires.IsZero():
+		return cookie.Expires, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// CookieHardeningIssues returns list of problems, which make cookie vulnerable to theft or cross-site attacks.
+// Hardened cookie has Secure and HttpOnly flags and SameSite attribute set to Strict or Lax.
+// Cookies with __Host- prefix should have Path=/ and no Domain attribute.
+func CookieHardeningIssues(cookie *http.Cookie) []string {
+	var issues []string
+	if !cookie.Secure {
+		issues = append(issues, "Secure flag is not set")
+	}
+
+	if !cookie.HttpOnly {
+		issues = append(issues, "HttpOnly flag is not set")
+	}
+
+	if cookie.SameSite != http.SameSiteStrictMode && cookie.SameSite != http.SameSiteLaxMode {
+		issues = append(issues, fmt.Sprintf("SameSite attribute should be Strict or Lax, got: '%s'", sameSiteName(cookie.SameSite)))
+	}
+
+	if strings.HasPrefix(cookie.Name, "__Host-") {
+		if cookie.Path != "/" {
+			issues = append(issues, fmt.Sprintf("cookie with __Host- prefix should have Path=/, got: '%s'", cookie.Path))
+		}
+
+		if cookie.Domain != "" {
+			issues = append(issues, fmt.Sprintf("cookie with __Host- prefix should not have Domain attribute, got: '%s'", cookie.Domain))
+		}
+	}
+
+	return issues
+}
+
+func sameSiteName(mode http.SameSite) string {
+	switch mode {
+	case http.SameSiteStrictMode:
+		return "Strict"
+	case http.SameSiteLaxMode:
+		return "Lax"
+	case http.SameSiteNoneMode:
+		return "None"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,104 @@
+package httpctx
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func cookieFromHeader(t *testing.T, header string) *http.Cookie {
+	t.Helper()
+
+	cookies := (&http.Response{Header: http.Header{"Set-Cookie": {header}}}).Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("could not parse cookie: %s", header)
+	}
+
+	return cookies[0]
+}
+
+func TestCookieAttributeValue(t *testing.T) {
+	cookie := cookieFromHeader(t, "sid=abc; Path=/app; Domain=example.com; Secure; HttpOnly; SameSite=Strict")
+	tests := []struct {
+		attribute CookieAttribute
+		expected  string
+		want      bool
+	}{
+		{attribute: CookieAttributeSecure, expected: "true", want: true},
+		{attribute: CookieAttributeHttpOnly, expected: "false", want: false},
+		{attribute: CookieAttributeSameSite, expected: "strict", want: true},
+		{attribute: CookieAttributePath, expected: "/app", want: true},
+		{attribute: CookieAttributeDomain, expected: ".Example.com", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.attribute), func(t *testing.T) {
+			actual, err := CookieAttributeValue(cookie, tt.attribute)
+			if err != nil {
+				t.Fatalf("CookieAttributeValue() unexpected error = %v", err)
+			}
+
+			if got := CookieAttributeEqual(tt.attribute, tt.expected, actual); got != tt.want {
+				t.Errorf("CookieAttributeEqual(%s, %s) = %t, want %t", tt.expected, actual, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCookieAttribute(t *testing.T) {
+	if got, err := ParseCookieAttribute("httponly"); err != nil || got != CookieAttributeHttpOnly {
+		t.Errorf("ParseCookieAttribute() = %s, err: %v", got, err)
+	}
+
+	if _, err := ParseCookieAttribute("Priority"); err == nil {
+		t.Errorf("ParseCookieAttribute() expected error")
+	}
+}
+
+func TestCookieExpiry(t *testing.T) {
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header string
+		want   time.Time
+		wantOk bool
+	}{
+		{name: "max age", header: "a=1; Max-Age=60; Expires=Wed, 21 Oct 2015 07:28:00 GMT", want: now.Add(time.Minute), wantOk: true},
+		{name: "zero max age", header: "a=1; Max-Age=0", want: now, wantOk: true},
+		{name: "expires", header: "a=1; Expires=Wed, 21 Oct 2015 07:28:00 GMT", want: time.Date(2015, 10, 21, 7, 28, 0, 0, time.UTC), wantOk: true},
+		{name: "session cookie", header: "a=1", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := CookieExpiry(cookieFromHeader(t, tt.header), now)
+			if ok != tt.wantOk || !got.Equal(tt.want) {
+				t.Errorf("CookieExpiry() = %v, %t, want %v, %t", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestCookieHardeningIssues(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{header: "sid=1; Secure; HttpOnly; SameSite=Lax"},
+		{header: "sid=1; SameSite=None", want: []string{
+			"Secure flag is not set",
+			"HttpOnly flag is not set",
+			"SameSite attribute should be Strict or Lax, got: 'None'",
+		}},
+		{header: "__Host-sid=1; Secure; HttpOnly; SameSite=Strict; Path=/a; Domain=example.com", want: []string{
+			"cookie with __Host- prefix should have Path=/, got: '/a'",
+			"cookie with __Host- prefix should not have Domain attribute, got: 'example.com'",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := CookieHardeningIssues(cookieFromHeader(t, tt.header)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CookieHardeningIssues() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
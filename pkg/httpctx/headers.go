@@ -0,0 +1,192 @@
+package httpctx
+
+import (
+	"fmt"
+	"mime"
+	"sort"
+	"strings"
+)
+
+// caseInsensitiveParams holds media type parameters, which values are compared case-insensitively.
+var caseInsensitiveParams = map[string]bool{"charset": true}
+
+// SplitHeaderValues splits values of header lines into list elements separated by comma.
+// Commas within quoted strings do not separate elements. Empty elements are omitted.
+func SplitHeaderValues(values []string) []string {
+	var elements []string
+	for _, value := range values {
+		inQuotes, escaped, start := false, false, 0
+		for i := 0; i < len(value); i++ {
+			switch c := value[i]; {
+			case escaped:
+				escaped = false
+			case c == '\\' && inQuotes:
+				escaped = true
+			case c == '"':
+				inQuotes = !inQuotes
+			case c == ',' && !inQuotes:
+				elements = appendNonEmpty(elements, value[start:i])
+				start = i + 1
+			}
+		}
+
+		elements = appendNonEmpty(elements, value[start:])
+	}
+
+	return elements
+}
+
+// CompareMediaTypes checks whether actual media type, for example from Content-Type header, matches expected one.
+// Types and parameter names are compared case-insensitively, as well as value of charset parameter.
+// Parameters not present in expected media type are not checked, so "multipart/form-data" matches
+// "multipart/form-data; boundary=abc".
+func CompareMediaTypes(expected, actual string) error {
+	expectedType, expectedParams, err := mime.ParseMediaType(expected)
+	if err != nil {
+		return fmt.Errorf("could not parse expected media type '%s', err: %w", expected, err)
+	}
+
+	actualType, actualParams, err := mime.ParseMediaType(actual)
+	if err != nil {
+		return fmt.Errorf("could not parse actual media type '%s', err: %w", actual, err)
+	}
+
+	if expectedType != actualType {
+		return fmt.Errorf("expected media type %s, got %s", expectedType, actualType)
+	}
+
+	names := make([]string, 0, len(expectedParams))
+	for name := range expectedParams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		expectedValue := expectedParams[name]
+		actualValue, ok := actualParams[name]
+		if !ok {
+			return fmt.Errorf("media type %s does not have parameter %s, expected value: %s", actual, name, expectedValue)
+		}
+
+		if expectedValue == actualValue || (caseInsensitiveParams[name] && strings.EqualFold(expectedValue, actualValue)) {
+			continue
+		}
+
+		return fmt.Errorf("media type parameter %s has value: %s, expected: %s", name, actualValue, expectedValue)
+	}
+
+	return nil
+}
+
+// Directive is single directive of header, for example max-age=3600 of Cache-Control header.
+type Directive struct {
+	// Name is lowercase name of directive.
+	Name string
+
+	// Value is value of directive without quotes, empty string when directive does not have value.
+	Value string
+
+	// HasValue tells whether directive has value.
+	HasValue bool
+}
+
+// Text returns value of directive, "true" for directive without value.
+func (d Directive) Text() string {
+	if !d.HasValue {
+		return "true"
+	}
+
+	return d.Value
+}
+
+// ParseDirectives parses directives of header lines separated by comma, for example Cache-Control: No-Cache, max-age=3600.
+// Parsing is lenient, so it suits headers which are not valid RFC 8941 dictionaries, see ParseDictionary:
+// names are case-insensitive, values may be tokens or quoted strings, parameters following semicolon are omitted
+// and malformed elements are kept as they are.
+func ParseDirectives(values []string) []Directive {
+	elements := SplitHeaderValues(values)
+	directives := make([]Directive, 0, len(elements))
+	for _, element := range elements {
+		directive := Directive{Name: element}
+		if i := strings.IndexAny(element, "=;"); i >= 0 {
+			directive.Name = element[:i]
+			if element[i] == '=' {
+				directive.Value, directive.HasValue = unquote(strings.TrimSpace(withoutParams(element[i+1:]))), true
+			}
+		}
+
+		directive.Name = strings.ToLower(strings.TrimSpace(directive.Name))
+		directives = append(directives, directive)
+	}
+
+	return directives
+}
+
+// FindDirective returns first directive of given name, compared case-insensitively.
+func FindDirective(directives []Directive, name string) (Directive, bool) {
+	for _, directive := range directives {
+		if strings.EqualFold(directive.Name, strings.TrimSpace(name)) {
+			return directive, true
+		}
+	}
+
+	return Directive{}, false
+}
+
+// DirectiveNames returns names of directives.
+func DirectiveNames(directives []Directive) []string {
+	names := make([]string, 0, len(directives))
+	for _, directive := range directives {
+		names = append(names, directive.Name)
+	}
+
+	return names
+}
+
+// withoutParams returns value without parameters following semicolon outside of quoted string.
+func withoutParams(value string) string {
+	quoted, escaped := false, false
+	for i := 0; i < len(value); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && value[i] == '\\':
+			escaped = true
+		case value[i] == '"':
+			quoted = !quoted
+		case value[i] == ';' && !quoted:
+			return value[:i]
+		}
+	}
+
+	return value
+}
+
+// unquote removes quotes and backslashes of quoted pairs from quoted string, other values are returned as they are.
+func unquote(value string) string {
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return value
+	}
+
+	var sb strings.Builder
+	escaped := false
+	for i := 1; i < len(value)-1; i++ {
+		if value[i] == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+
+		escaped = false
+		sb.WriteByte(value[i])
+	}
+
+	return sb.String()
+}
+
+func appendNonEmpty(elements []string, element string) []string {
+	if element = strings.TrimSpace(element); element != "" {
+		return append(elements, element)
+	}
+
+	return elements
+}
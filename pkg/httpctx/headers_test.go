@@ -0,0 +1,68 @@
+package httpctx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitHeaderValues(t *testing.T) {
+	got := SplitHeaderValues([]string{`gzip, br`, ` "a, b", ,c`})
+	want := []string{"gzip", "br", `"a, b"`, "c"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitHeaderValues() = %q, want %q", got, want)
+	}
+}
+
+func TestCompareMediaTypes(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		actual   string
+		wantErr  bool
+	}{
+		{name: "equal", expected: "application/json", actual: "application/json", wantErr: false},
+		{name: "case insensitive type and charset", expected: "application/json; charset=utf-8", actual: "Application/JSON;Charset=\"UTF-8\"", wantErr: false},
+		{name: "parameters not checked", expected: "multipart/form-data", actual: "multipart/form-data; boundary=abc", wantErr: false},
+		{name: "different type", expected: "application/json", actual: "application/xml", wantErr: true},
+		{name: "missing parameter", expected: "text/html; charset=utf-8", actual: "text/html", wantErr: true},
+		{name: "case sensitive boundary", expected: "multipart/form-data; boundary=ABC", actual: "multipart/form-data; boundary=abc", wantErr: true},
+		{name: "invalid actual", expected: "text/html", actual: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CompareMediaTypes(tt.expected, tt.actual); (err != nil) != tt.wantErr {
+				t.Errorf("CompareMediaTypes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseDirectives(t *testing.T) {
+	directives := ParseDirectives([]string{`max-age=3600, Private`, `No-Cache="Set-Cookie, \"Vary\""`, `no-store, a;q=0.5, b="x;y";p=1`})
+	want := []Directive{
+		{Name: "max-age", Value: "3600", HasValue: true},
+		{Name: "private"},
+		{Name: "no-cache", Value: `Set-Cookie, "Vary"`, HasValue: true},
+		{Name: "no-store"},
+		{Name: "a"},
+		{Name: "b", Value: "x;y", HasValue: true},
+	}
+
+	if !reflect.DeepEqual(directives, want) {
+		t.Fatalf("ParseDirectives() = %+v, want %+v", directives, want)
+	}
+
+	directive, ok := FindDirective(directives, "NO-CACHE")
+	if !ok || directive.Text() != `Set-Cookie, "Vary"` {
+		t.Errorf("FindDirective() = %+v, %v", directive, ok)
+	}
+
+	if directive, ok = FindDirective(directives, "private"); !ok || directive.Text() != "true" {
+		t.Errorf("FindDirective() = %+v, %v", directive, ok)
+	}
+
+	if _, ok = FindDirective(directives, "public"); ok {
+		t.Errorf("FindDirective() should not find missing directive")
+	}
+}
@@ -0,0 +1,548 @@
+package httpctx
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrStructuredField occurs when header value is not valid RFC 8941 structured field.
+var ErrStructuredField = errors.New("invalid structured field")
+
+// Token is RFC 8941 token, for example: no-cache or text/html.
+type Token string
+
+// Item is RFC 8941 item or inner list with parameters.
+// Value is one of: int64, float64, string, Token, []byte, bool or []Item for inner list.
+type Item struct {
+	Value  interface{}
+	Params []Param
+}
+
+// Param is single parameter of item.
+type Param struct {
+	Key   string
+	Value interface{}
+}
+
+// DictionaryMember is single member of dictionary.
+type DictionaryMember struct {
+	Key  string
+	Item Item
+}
+
+// Dictionary is RFC 8941 dictionary, for example: Cache-Control: max-age=3600, no-cache="Set-Cookie", private.
+type Dictionary []DictionaryMember
+
+// Get returns item of dictionary member with given key.
+func (d Dictionary) Get(key string) (Item, bool) {
+	for _, member := range d {
+		if member.Key == key {
+			return member.Item, true
+		}
+	}
+
+	return Item{}, false
+}
+
+// Keys returns keys of all dictionary members.
+func (d Dictionary) Keys() []string {
+	keys := make([]string, 0, len(d))
+	for _, member := range d {
+		keys = append(keys, member.Key)
+	}
+
+	return keys
+}
+
+// Text returns item value without RFC 8941 serialization markers, which makes it convenient for comparison
+// with user input: strings are unquoted, booleans are true/false and byte sequences are base64 encoded.
+// Inner lists and parameters are serialized according to RFC 8941.
+func (i Item) Text() string {
+	switch v := i.Value.(type) {
+	case string:
+		return v
+	case Token:
+		return string(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	default:
+		return serializeBareItem(v)
+	}
+}
+
+// String returns item serialized according to RFC 8941.
+func (i Item) String() string {
+	var sb strings.Builder
+	if inner, ok := i.Value.([]Item); ok {
+		sb.WriteString("(")
+		for n, item := range inner {
+			if n > 0 {
+				sb.WriteString(" ")
+			}
+
+			sb.WriteString(item.String())
+		}
+		sb.WriteString(")")
+	} else {
+		sb.WriteString(serializeBareItem(i.Value))
+	}
+
+	for _, param := range i.Params {
+		sb.WriteString(";" + param.Key)
+		if b, ok := param.Value.(bool); !ok || !b {
+			sb.WriteString("=" + serializeBareItem(param.Value))
+		}
+	}
+
+	return sb.String()
+}
+
+// ParseDictionary parses header value as RFC 8941 dictionary. Values of many header lines
+// should be joined with comma before parsing.
+func ParseDictionary(value string) (Dictionary, error) {
+	p := &sfParser{input: value}
+	p.skipSP()
+
+	var dict Dictionary
+	for !p.eof() {
+		key, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+
+		var item Item
+		if p.consume('=') {
+			if item, err = p.parseItemOrInnerList(); err != nil {
+				return nil, err
+			}
+		} else {
+			params, err := p.parseParams()
+			if err != nil {
+				return nil, err
+			}
+
+			item = Item{Value: true, Params: params}
+		}
+
+		dict = dict.set(key, item)
+		if err = p.nextMember(); err != nil {
+			return nil, err
+		}
+	}
+
+	return dict, nil
+}
+
+// ParseItem parses header value as RFC 8941 item, for example: text/html;q=0.9.
+func ParseItem(value string) (Item, error) {
+	p := &sfParser{input: value}
+	p.skipSP()
+
+	item, err := p.parseItem()
+	if err != nil {
+		return Item{}, err
+	}
+
+	p.skipSP()
+	if !p.eof() {
+		return Item{}, p.errorf("unexpected characters after item")
+	}
+
+	return item, nil
+}
+
+// ParseList parses header value as RFC 8941 list. Values of many header lines
+// should be joined with comma before parsing.
+func ParseList(value string) ([]Item, error) {
+	p := &sfParser{input: value}
+	p.skipSP()
+
+	var list []Item
+	for !p.eof() {
+		item, err := p.parseItemOrInnerList()
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, item)
+		if err = p.nextMember(); err != nil {
+			return nil, err
+		}
+	}
+
+	return list, nil
+}
+
+// Directives returns members of dictionary as directives. Member without value, which is boolean true
+// without parameters, becomes directive without value. Values of other members are their Text.
+func (d Dictionary) Directives() []Directive {
+	directives := make([]Directive, 0, len(d))
+	for _, member := range d {
+		if v, ok := member.Item.Value.(bool); ok && v && len(member.Item.Params) == 0 {
+			directives = append(directives, Directive{Name: member.Key})
+			continue
+		}
+
+		directives = append(directives, Directive{Name: member.Key, Value: member.Item.Text(), HasValue: true})
+	}
+
+	return directives
+}
+
+// set overrides value of existing member or appends new one, as required by RFC 8941.
+func (d Dictionary) set(key string, item Item) Dictionary {
+	for i := range d {
+		if d[i].Key == key {
+			d[i].Item = item
+			return d
+		}
+	}
+
+	return append(d, DictionaryMember{Key: key, Item: item})
+}
+
+// sfParser is RFC 8941 parser working on single header value.
+type sfParser struct {
+	input string
+	pos   int
+}
+
+func (p *sfParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *sfParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+
+	return p.input[p.pos]
+}
+
+func (p *sfParser) consume(c byte) bool {
+	if p.peek() == c && !p.eof() {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *sfParser) skipSP() {
+	for p.peek() == ' ' {
+		p.pos++
+	}
+}
+
+func (p *sfParser) skipOWS() {
+	for p.peek() == ' ' || p.peek() == '\t' {
+		p.pos++
+	}
+}
+
+func (p *sfParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at position %d of %q", ErrStructuredField, fmt.Sprintf(format, args...), p.pos, p.input)
+}
+
+// nextMember moves parser to next list or dictionary member.
+func (p *sfParser) nextMember() error {
+	p.skipOWS()
+	if p.eof() {
+		return nil
+	}
+
+	if !p.consume(',') {
+		return p.errorf("expected comma")
+	}
+
+	p.skipOWS()
+	if p.eof() {
+		return p.errorf("trailing comma")
+	}
+
+	return nil
+}
+
+func (p *sfParser) parseItemOrInnerList() (Item, error) {
+	if p.peek() == '(' {
+		return p.parseInnerList()
+	}
+
+	return p.parseItem()
+}
+
+func (p *sfParser) parseInnerList() (Item, error) {
+	p.consume('(')
+
+	var items []Item
+	for !p.eof() {
+		p.skipSP()
+		if p.consume(')') {
+			params, err := p.parseParams()
+			if err != nil {
+				return Item{}, err
+			}
+
+			return Item{Value: items, Params: params}, nil
+		}
+
+		item, err := p.parseItem()
+		if err != nil {
+			return Item{}, err
+		}
+
+		items = append(items, item)
+		if p.peek() != ' ' && p.peek() != ')' {
+			return Item{}, p.errorf("expected space or closing parenthesis in inner list")
+		}
+	}
+
+	return Item{}, p.errorf("unterminated inner list")
+}
+
+func (p *sfParser) parseItem() (Item, error) {
+	value, err := p.parseBareItem()
+	if err != nil {
+		return Item{}, err
+	}
+
+	params, err := p.parseParams()
+	if err != nil {
+		return Item{}, err
+	}
+
+	return Item{Value: value, Params: params}, nil
+}
+
+func (p *sfParser) parseParams() ([]Param, error) {
+	var params []Param
+	for p.consume(';') {
+		p.skipSP()
+		key, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+
+		var value interface{} = true
+		if p.consume('=') {
+			if value, err = p.parseBareItem(); err != nil {
+				return nil, err
+			}
+		}
+
+		overridden := false
+		for i := range params {
+			if params[i].Key == key {
+				params[i].Value = value
+				overridden = true
+			}
+		}
+
+		if !overridden {
+			params = append(params, Param{Key: key, Value: value})
+		}
+	}
+
+	return params, nil
+}
+
+func (p *sfParser) parseKey() (string, error) {
+	c := p.peek()
+	if !isLCAlpha(c) && c != '*' {
+		return "", p.errorf("key should start with lowercase letter or asterisk")
+	}
+
+	start := p.pos
+	for !p.eof() {
+		c = p.peek()
+		if !isLCAlpha(c) && !isDigit(c) && !strings.ContainsRune("_-.*", rune(c)) {
+			break
+		}
+
+		p.pos++
+	}
+
+	return p.input[start:p.pos], nil
+}
+
+func (p *sfParser) parseBareItem() (interface{}, error) {
+	c := p.peek()
+	switch {
+	case c == '-' || isDigit(c):
+		return p.parseNumber()
+	case c == '"':
+		return p.parseString()
+	case c == '*' || isAlpha(c):
+		return p.parseToken(), nil
+	case c == ':':
+		return p.parseByteSequence()
+	case c == '?':
+		return p.parseBoolean()
+	default:
+		return nil, p.errorf("unexpected character %q", c)
+	}
+}
+
+func (p *sfParser) parseNumber() (interface{}, error) {
+	start := p.pos
+	p.consume('-')
+
+	isDecimal := false
+	for !p.eof() {
+		c := p.peek()
+		if c == '.' && !isDecimal {
+			isDecimal = true
+		} else if !isDigit(c) {
+			break
+		}
+
+		p.pos++
+	}
+
+	number := p.input[start:p.pos]
+	digits := strings.TrimPrefix(number, "-")
+	if digits == "" || !isDigit(digits[0]) {
+		return nil, p.errorf("number should contain digits")
+	}
+
+	if !isDecimal {
+		if len(digits) > 15 {
+			return nil, p.errorf("integer %s has too many digits", number)
+		}
+
+		return strconv.ParseInt(number, 10, 64)
+	}
+
+	parts := strings.SplitN(digits, ".", 2)
+	if len(parts[0]) > 12 || len(parts[1]) == 0 || len(parts[1]) > 3 {
+		return nil, p.errorf("invalid decimal %s", number)
+	}
+
+	return strconv.ParseFloat(number, 64)
+}
+
+func (p *sfParser) parseString() (string, error) {
+	p.consume('"')
+
+	var sb strings.Builder
+	for !p.eof() {
+		c := p.input[p.pos]
+		p.pos++
+
+		switch {
+		case c == '\\':
+			next := p.peek()
+			if next != '"' && next != '\\' {
+				return "", p.errorf("invalid escape sequence in string")
+			}
+
+			sb.WriteByte(next)
+			p.pos++
+		case c == '"':
+			return sb.String(), nil
+		case c < 0x20 || c > 0x7e:
+			return "", p.errorf("invalid character in string")
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return "", p.errorf("unterminated string")
+}
+
+func (p *sfParser) parseToken() Token {
+	start := p.pos
+	p.pos++
+	for !p.eof() {
+		c := p.peek()
+		if !isTChar(c) && c != ':' && c != '/' {
+			break
+		}
+
+		p.pos++
+	}
+
+	return Token(p.input[start:p.pos])
+}
+
+func (p *sfParser) parseByteSequence() ([]byte, error) {
+	p.consume(':')
+
+	end := strings.IndexByte(p.input[p.pos:], ':')
+	if end == -1 {
+		return nil, p.errorf("unterminated byte sequence")
+	}
+
+	encoded := p.input[p.pos : p.pos+end]
+	p.pos += end + 1
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, p.errorf("invalid base64 in byte sequence")
+	}
+
+	return decoded, nil
+}
+
+func (p *sfParser) parseBoolean() (bool, error) {
+	p.consume('?')
+	switch {
+	case p.consume('1'):
+		return true, nil
+	case p.consume('0'):
+		return false, nil
+	default:
+		return false, p.errorf("boolean should be ?0 or ?1")
+	}
+}
+
+func serializeBareItem(value interface{}) string {
+	switch v := value.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		s := strconv.FormatFloat(v, 'f', -1, 64)
+		if !strings.Contains(s, ".") {
+			s += ".0"
+		}
+
+		return s
+	case string:
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v) + `"`
+	case Token:
+		return string(v)
+	case []byte:
+		return ":" + base64.StdEncoding.EncodeToString(v) + ":"
+	case bool:
+		if v {
+			return "?1"
+		}
+
+		return "?0"
+	case []Item:
+		return Item{Value: v}.String()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLCAlpha(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
+
+func isAlpha(c byte) bool {
+	return isLCAlpha(c) || (c >= 'A' && c <= 'Z')
+}
+
+func isTChar(c byte) bool {
+	return isAlpha(c) || isDigit(c) || strings.IndexByte("!#$%&'*+-.^_`|~", c) != -1
+}
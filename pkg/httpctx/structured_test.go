@@ -0,0 +1,135 @@
+package httpctx
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseDictionary(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    Dictionary
+		wantErr bool
+	}{
+		{name: "cache control", value: `max-age=3600, no-cache="Set-Cookie", private`, want: Dictionary{
+			{Key: "max-age", Item: Item{Value: int64(3600)}},
+			{Key: "no-cache", Item: Item{Value: "Set-Cookie"}},
+			{Key: "private", Item: Item{Value: true}},
+		}},
+		{name: "all bare item types", value: `a=-1.5, b=?0, c=:aGk=:, d=text/html;q=0.9, e=(1 "x");p`, want: Dictionary{
+			{Key: "a", Item: Item{Value: -1.5}},
+			{Key: "b", Item: Item{Value: false}},
+			{Key: "c", Item: Item{Value: []byte("hi")}},
+			{Key: "d", Item: Item{Value: Token("text/html"), Params: []Param{{Key: "q", Value: 0.9}}}},
+			{Key: "e", Item: Item{Value: []Item{{Value: int64(1)}, {Value: "x"}}, Params: []Param{{Key: "p", Value: true}}}},
+		}},
+		{name: "duplicated key overrides value", value: "a=1, b=2, a=3", want: Dictionary{
+			{Key: "a", Item: Item{Value: int64(3)}},
+			{Key: "b", Item: Item{Value: int64(2)}},
+		}},
+		{name: "empty", value: " ", want: nil},
+		{name: "uppercase key", value: "Max-Age=1", wantErr: true},
+		{name: "trailing comma", value: "a=1,", wantErr: true},
+		{name: "unterminated string", value: `a="abc`, wantErr: true},
+		{name: "too long integer", value: "a=1234567890123456", wantErr: true},
+		{name: "invalid decimal", value: "a=1.2345", wantErr: true},
+		{name: "missing comma", value: "a=1 b=2", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDictionary(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDictionary() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil && !errors.Is(err, ErrStructuredField) {
+				t.Errorf("ParseDictionary() error should wrap ErrStructuredField, got %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDictionary() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseList(t *testing.T) {
+	got, err := ParseList(`sugar, tea;fresh, (rum "x");proof=40`)
+	if err != nil {
+		t.Fatalf("ParseList() unexpected error = %v", err)
+	}
+
+	want := []Item{
+		{Value: Token("sugar")},
+		{Value: Token("tea"), Params: []Param{{Key: "fresh", Value: true}}},
+		{Value: []Item{{Value: Token("rum")}, {Value: "x"}}, Params: []Param{{Key: "proof", Value: int64(40)}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseList() = %#v, want %#v", got, want)
+	}
+}
+
+func TestParseItem(t *testing.T) {
+	got, err := ParseItem(` text/html;q=0.9;level=1 `)
+	if err != nil {
+		t.Fatalf("ParseItem() unexpected error = %v", err)
+	}
+
+	want := Item{Value: Token("text/html"), Params: []Param{{Key: "q", Value: 0.9}, {Key: "level", Value: int64(1)}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseItem() = %#v, want %#v", got, want)
+	}
+
+	for _, value := range []string{"a, b", "(1 2)", `"abc`, ""} {
+		if _, err = ParseItem(value); !errors.Is(err, ErrStructuredField) {
+			t.Errorf("ParseItem(%q) error = %v, want ErrStructuredField", value, err)
+		}
+	}
+}
+
+func TestDictionary_Directives(t *testing.T) {
+	dict, err := ParseDictionary(`max-age=3600, no-cache="Set-Cookie", private, a;q=0.5, b=(1 2)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Directive{
+		{Name: "max-age", Value: "3600", HasValue: true},
+		{Name: "no-cache", Value: "Set-Cookie", HasValue: true},
+		{Name: "private"},
+		{Name: "a", Value: "true", HasValue: true},
+		{Name: "b", Value: "(1 2)", HasValue: true},
+	}
+	if got := dict.Directives(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Directives() = %+v, want %+v", got, want)
+	}
+}
+
+func TestItem_TextAndString(t *testing.T) {
+	tests := []struct {
+		item       Item
+		wantText   string
+		wantString string
+	}{
+		{item: Item{Value: int64(3600)}, wantText: "3600", wantString: "3600"},
+		{item: Item{Value: 2.0}, wantText: "2.0", wantString: "2.0"},
+		{item: Item{Value: `a"b`}, wantText: `a"b`, wantString: `"a\"b"`},
+		{item: Item{Value: true}, wantText: "true", wantString: "?1"},
+		{item: Item{Value: []byte("hi")}, wantText: "aGk=", wantString: ":aGk=:"},
+		{item: Item{Value: Token("gzip"), Params: []Param{{Key: "q", Value: 0.5}, {Key: "x", Value: true}}}, wantText: "gzip", wantString: "gzip;q=0.5;x"},
+		{item: Item{Value: []Item{{Value: int64(1)}, {Value: Token("a")}}}, wantText: "(1 a)", wantString: "(1 a)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wantString, func(t *testing.T) {
+			if got := tt.item.Text(); got != tt.wantText {
+				t.Errorf("Text() = %s, want %s", got, tt.wantText)
+			}
+
+			if got := tt.item.String(); got != tt.wantString {
+				t.Errorf("String() = %s, want %s", got, tt.wantString)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package httpctx
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+const (
+	// TimingPhaseDNS describes DNS lookup phase.
+	TimingPhaseDNS TimingPhase = "dns"
+
+	// TimingPhaseConnect describes TCP connection phase.
+	TimingPhaseConnect TimingPhase = "connect"
+
+	// TimingPhaseTLS describes TLS handshake phase.
+	TimingPhaseTLS TimingPhase = "tls"
+
+	// TimingPhaseTTFB describes phase between writing whole request and obtaining first byte of response.
+	TimingPhaseTTFB TimingPhase = "ttfb"
+
+	// TimingPhaseDownload describes phase between obtaining first byte of response and reading whole response body.
+	TimingPhaseDownload TimingPhase = "download"
+
+	// TimingPhaseTotal describes whole request-response cycle.
+	TimingPhaseTotal TimingPhase = "total"
+)
+
+// TimingPhase describes phase of HTTP(s) request-response cycle.
+type TimingPhase string
+
+// Timings holds duration of every phase of HTTP(s) request-response cycle.
+// Phases that did not occur, for example DNS lookup on reused connection, have zero duration.
+type Timings struct {
+	DNSLookup       time.Duration
+	TCPConnection   time.Duration
+	TLSHandshake    time.Duration
+	TimeToFirstByte time.Duration
+	ContentDownload time.Duration
+	Total           time.Duration
+}
+
+// TimingsRecorder is entity that has ability to measure HTTP(s) request-response phases with net/http/httptrace.
+// Safe for concurrent use.
+type TimingsRecorder struct {
+	mu sync.Mutex
+
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+	done         time.Time
+}
+
+// NewTimingsRecorder returns *TimingsRecorder ready to measure single request-response cycle.
+func NewTimingsRecorder() *TimingsRecorder {
+	return &TimingsRecorder{}
+}
+
+// Phase returns duration of given phase.
+func (t Timings) Phase(phase TimingPhase) (time.Duration, error) {
+	switch phase {
+	case TimingPhaseDNS:
+		return t.DNSLookup, nil
+	case TimingPhaseConnect:
+		return t.TCPConnection, nil
+	case TimingPhaseTLS:
+		return t.TLSHandshake, nil
+	case TimingPhaseTTFB:
+		return t.TimeToFirstByte, nil
+	case TimingPhaseDownload:
+		return t.ContentDownload, nil
+	case TimingPhaseTotal:
+		return t.Total, nil
+	default:
+		return 0, fmt.Errorf("unknown timing phase: %s, available phases: %s, %s, %s, %s, %s, %s", phase,
+			TimingPhaseDNS, TimingPhaseConnect, TimingPhaseTLS, TimingPhaseTTFB, TimingPhaseDownload, TimingPhaseTotal)
+	}
+}
+
+// String returns human readable breakdown of timings.
+func (t Timings) String() string {
+	return fmt.Sprintf("%-20s%v\n%-20s%v\n%-20s%v\n%-20s%v\n%-20s%v\n%-20s%v",
+		"DNS lookup:", t.DNSLookup, "TCP connection:", t.TCPConnection, "TLS handshake:", t.TLSHandshake,
+		"Time to first byte:", t.TimeToFirstByte, "Content download:", t.ContentDownload, "Total:", t.Total)
+}
+
+// ClientTrace returns *httptrace.ClientTrace that feeds TimingsRecorder.
+func (r *TimingsRecorder) ClientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { r.mark(&r.dnsStart, false) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { r.mark(&r.dnsDone, true) },
+		ConnectStart: func(string, string) {
+			r.mark(&r.connectStart, false)
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				r.mark(&r.connectDone, true)
+			}
+		},
+		TLSHandshakeStart:    func() { r.mark(&r.tlsStart, false) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { r.mark(&r.tlsDone, true) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { r.mark(&r.wroteRequest, true) },
+		GotFirstResponseByte: func() { r.mark(&r.firstByte, false) },
+	}
+}
+
+// Start marks beginning of request-response cycle.
+func (r *TimingsRecorder) Start() {
+	r.mark(&r.start, true)
+}
+
+// Done marks moment when whole response body was read.
+func (r *TimingsRecorder) Done() {
+	r.mark(&r.done, true)
+}
+
+// Timings returns measured timings.
+func (r *TimingsRecorder) Timings() Timings {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return Timings{
+		DNSLookup:       between(r.dnsStart, r.dnsDone),
+		TCPConnection:   between(r.connectStart, r.connectDone),
+		TLSHandshake:    between(r.tlsStart, r.tlsDone),
+		TimeToFirstByte: between(r.wroteRequest, r.firstByte),
+		ContentDownload: between(r.firstByte, r.done),
+		Total:           between(r.start, r.done),
+	}
+}
+
+// mark saves current time in field. If overwrite is false, only the first occurrence is preserved.
+func (r *TimingsRecorder) mark(field *time.Time, overwrite bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if overwrite || field.IsZero() {
+		*field = time.Now()
+	}
+}
+
+// between returns duration between from and to or 0 if any of them is missing.
+func between(from, to time.Time) time.Duration {
+	if from.IsZero() || to.IsZero() || to.Before(from) {
+		return 0
+	}
+
+	return to.Sub(from)
+}
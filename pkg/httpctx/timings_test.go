@@ -0,0 +1,91 @@
+package httpctx
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"testing"
+	"time"
+)
+
+func TestTimingsRecorder_TLSServer(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+		w.Write([]byte("hello"))
+	}))
+	defer srv.Close()
+
+	recorder := NewTimingsRecorder()
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), recorder.ClientTrace()))
+	recorder.Start()
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = ioutil.ReadAll(resp.Body); err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	recorder.Done()
+
+	timings := recorder.Timings()
+	if timings.TCPConnection <= 0 {
+		t.Errorf("TCP connection time was not measured: %v", timings.TCPConnection)
+	}
+
+	if timings.TLSHandshake <= 0 {
+		t.Errorf("TLS handshake time was not measured: %v", timings.TLSHandshake)
+	}
+
+	if timings.TimeToFirstByte < 5*time.Millisecond {
+		t.Errorf("time to first byte should include server processing time, got: %v", timings.TimeToFirstByte)
+	}
+
+	if timings.Total < timings.TCPConnection+timings.TLSHandshake+timings.TimeToFirstByte {
+		t.Errorf("total time %v is less than sum of phases", timings.Total)
+	}
+}
+
+func TestTimings_Phase(t *testing.T) {
+	timings := Timings{
+		DNSLookup:       1,
+		TCPConnection:   2,
+		TLSHandshake:    3,
+		TimeToFirstByte: 4,
+		ContentDownload: 5,
+		Total:           6,
+	}
+
+	tests := []struct {
+		phase   TimingPhase
+		want    time.Duration
+		wantErr bool
+	}{
+		{phase: TimingPhaseDNS, want: 1},
+		{phase: TimingPhaseConnect, want: 2},
+		{phase: TimingPhaseTLS, want: 3},
+		{phase: TimingPhaseTTFB, want: 4},
+		{phase: TimingPhaseDownload, want: 5},
+		{phase: TimingPhaseTotal, want: 6},
+		{phase: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.phase), func(t *testing.T) {
+			got, err := timings.Phase(tt.phase)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Phase() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Phase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package httpctx
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// tlsVersions maps TLS versions to their names.
+var tlsVersions = map[uint16]string{
+	tls.VersionTLS10: "TLS 1.0",
+	tls.VersionTLS11: "TLS 1.1",
+	tls.VersionTLS12: "TLS 1.2",
+	tls.VersionTLS13: "TLS 1.3",
+}
+
+// ParseTLSVersion parses TLS version name, for example: "TLS 1.2", "TLSv1.3", "tls1.2" or "1.3".
+func ParseTLSVersion(name string) (uint16, error) {
+	normalized := strings.ToLower(strings.NewReplacer(" ", "", "_", "").Replace(name))
+	normalized = strings.TrimPrefix(strings.TrimPrefix(normalized, "tls"), "v")
+
+	for version, versionName := range tlsVersions {
+		if strings.TrimPrefix(versionName, "TLS ") == normalized {
+			return version, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown TLS version: %s, available: TLS 1.0, TLS 1.1, TLS 1.2, TLS 1.3", name)
+}
+
+// TLSVersionName returns name of TLS version, for example: "TLS 1.2".
+func TLSVersionName(version uint16) string {
+	if name, ok := tlsVersions[version]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("0x%04X", version)
+}
+
+// insecureSkipVerifyKey is context key of requests that should be sent without TLS certificate verification.
+type insecureSkipVerifyKey struct{}
+
+// WithInsecureSkipVerify returns shallow copy of req, which should be sent without TLS certificate verification.
+func WithInsecureSkipVerify(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), insecureSkipVerifyKey{}, true))
+}
+
+// IsInsecureSkipVerify tells whether req should be sent without TLS certificate verification.
+func IsInsecureSkipVerify(req *http.Request) bool {
+	insecure, _ := req.Context().Value(insecureSkipVerifyKey{}).(bool)
+
+	return insecure
+}
+
+// InsecureRequestDoer returns copy of doer, which does not verify TLS certificates. Doer, which already
+// does not verify them, is returned as it is. Copy has its own connection pool, so it should be built once and reused.
+// Only *http.Client using *http.Transport can be reconfigured, for any other doer error is returned.
+func InsecureRequestDoer(doer RequestDoer) (RequestDoer, error) {
+	cli, ok := doer.(*http.Client)
+	if !ok {
+		return nil, fmt.Errorf("TLS verification can't be skipped by %T, only *http.Client is supported", doer)
+	}
+
+	roundTripper := cli.Transport
+	if roundTripper == nil {
+		roundTripper = http.DefaultTransport
+	}
+
+	transport, ok := roundTripper.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("TLS verification can't be skipped by *http.Client with %T, only *http.Transport is supported", roundTripper)
+	}
+
+	if transport.TLSClientConfig != nil && transport.TLSClientConfig.InsecureSkipVerify {
+		return cli, nil
+	}
+
+	transport = transport.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+
+	transport.TLSClientConfig.InsecureSkipVerify = true
+
+	insecureCli := *cli
+	insecureCli.Transport = transport
+
+	return &insecureCli, nil
+}
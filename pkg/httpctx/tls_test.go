@@ -0,0 +1,90 @@
+package httpctx
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseTLSVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    uint16
+		wantErr bool
+	}{
+		{name: "TLS 1.2", want: tls.VersionTLS12},
+		{name: "TLSv1.3", want: tls.VersionTLS13},
+		{name: "tls1.1", want: tls.VersionTLS11},
+		{name: "1.0", want: tls.VersionTLS10},
+		{name: "TLS_1.3", want: tls.VersionTLS13},
+		{name: "SSL 3.0", wantErr: true},
+		{name: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTLSVersion(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTLSVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseTLSVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTLSVersionName(t *testing.T) {
+	if got := TLSVersionName(tls.VersionTLS12); got != "TLS 1.2" {
+		t.Errorf("TLSVersionName() = %s, want TLS 1.2", got)
+	}
+
+	if got := TLSVersionName(0x0300); got != "0x0300" {
+		t.Errorf("TLSVersionName() = %s, want 0x0300", got)
+	}
+}
+
+func TestInsecureRequestDoer(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://a.com", nil)
+	if IsInsecureSkipVerify(req) {
+		t.Errorf("IsInsecureSkipVerify() should be false for plain request")
+	}
+
+	if !IsInsecureSkipVerify(WithInsecureSkipVerify(req)) {
+		t.Errorf("IsInsecureSkipVerify() should be true for request returned by WithInsecureSkipVerify")
+	}
+
+	cli := &http.Client{Timeout: time.Second}
+	doer, err := InsecureRequestDoer(cli)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	insecureCli := doer.(*http.Client)
+	if insecureCli == cli || insecureCli.Timeout != time.Second {
+		t.Errorf("InsecureRequestDoer() should return configured copy of client")
+	}
+
+	if !insecureCli.Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("InsecureRequestDoer() should skip TLS verification")
+	}
+
+	if cli.Transport != nil {
+		t.Errorf("InsecureRequestDoer() should not modify original client")
+	}
+
+	if doer, err = InsecureRequestDoer(insecureCli); err != nil || doer != insecureCli {
+		t.Errorf("InsecureRequestDoer() should return client, which already skips TLS verification, as it is")
+	}
+
+	if _, err = InsecureRequestDoer(&http.Client{Transport: roundTripperFunc(nil)}); err == nil {
+		t.Errorf("InsecureRequestDoer() should return error for not supported transport")
+	}
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
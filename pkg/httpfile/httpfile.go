@@ -0,0 +1,557 @@
+// Package httpfile holds utilities for working with .http files used by JetBrains HTTP Client and VS Code REST Client.
+package httpfile
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrMissingVariable occurs when placeholder references variable that could not be resolved.
+var ErrMissingVariable = errors.New("missing variable")
+
+// Request describes single request defined in .http file.
+type Request struct {
+	// Name is request name from "# @name" comment or from "###" separator line.
+	Name string
+
+	// Method is HTTP method of request, GET by default.
+	Method string
+
+	// URL is request URL, may contain placeholders.
+	URL string
+
+	// Headers are request headers in order of appearance, may contain placeholders.
+	Headers [][2]string
+
+	// Body is request body, may contain placeholders.
+	Body string
+
+	// BodyFile is path to file with request body, defined with "< ./path" syntax.
+	BodyFile string
+}
+
+// File describes parsed .http file.
+type File struct {
+	// Dir is directory of file, used to resolve relative paths to body files.
+	Dir string
+
+	// Variables are file variables defined with "@name = value" syntax.
+	Variables map[string]string
+
+	// Requests are all requests defined in file.
+	Requests []Request
+}
+
+// Lookup describes function that resolves variable of given name.
+type Lookup func(name string) (string, bool)
+
+var (
+	placeholderRegExp   = regexp.MustCompile(`{{\s*([^{}]+?)\s*}}`)
+	requestLineRegExp   = regexp.MustCompile(`^(GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS|TRACE|CONNECT)\s+((?:{{[^{}]*}}|\S)+)(\s+HTTP/[\d.]+)?$`)
+	fileVariableRegExp  = regexp.MustCompile(`^@([\w.-]+)\s*=\s*(.*)$`)
+	nameAnnotationRegEx = regexp.MustCompile(`^(#|//)\s*@name\s+(\S+)`)
+)
+
+// Load reads and parses .http file located under provided OS path.
+func Load(pth string) (File, error) {
+	data, err := ioutil.ReadFile(pth)
+	if err != nil {
+		return File{}, fmt.Errorf("could not read .http file %s, err: %w", pth, err)
+	}
+
+	f, err := Parse(data)
+	if err != nil {
+		return File{}, err
+	}
+
+	f.Dir = filepath.Dir(pth)
+
+	return f, nil
+}
+
+// Parse parses content of .http file. Requests are separated by lines starting with "###".
+func Parse(data []byte) (File, error) {
+	f := File{Variables: map[string]string{}}
+
+	var blocks [][]string
+	var blockNames []string
+	current, currentName := []string{}, ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(line, "###") {
+			blocks = append(blocks, current)
+			blockNames = append(blockNames, currentName)
+			current, currentName = []string{}, strings.TrimSpace(strings.TrimPrefix(line, "###"))
+			continue
+		}
+
+		current = append(current, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return File{}, fmt.Errorf("could not read .http file content, err: %w", err)
+	}
+
+	blocks = append(blocks, current)
+	blockNames = append(blockNames, currentName)
+
+	for i, block := range blocks {
+		req, found, err := f.parseBlock(block, blockNames[i])
+		if err != nil {
+			return File{}, err
+		}
+
+		if found {
+			f.Requests = append(f.Requests, req)
+		}
+	}
+
+	return f, nil
+}
+
+// Request returns request of given name.
+func (f File) Request(name string) (Request, error) {
+	names := make([]string, 0, len(f.Requests))
+	for _, req := range f.Requests {
+		if req.Name == name {
+			return req, nil
+		}
+
+		names = append(names, req.Name)
+	}
+
+	return Request{}, fmt.Errorf("could not find request named '%s', available requests: %s", name, strings.Join(names, ", "))
+}
+
+// Lookup returns Lookup that resolves variables with provided lookups first and then falls back to file variables.
+// File variables may reference other variables, cyclic references are reported as missing variables.
+// Placeholders {{$dotenv NAME}} are resolved from .env file located in the same directory as .http file.
+func (f File) Lookup(lookups ...Lookup) Lookup {
+	resolving := map[string]bool{}
+	var dotenv map[string]string
+
+	var lookup Lookup
+	lookup = func(name string) (string, bool) {
+		for _, l := range lookups {
+			if value, ok := l(name); ok {
+				return value, true
+			}
+		}
+
+		if fields := strings.Fields(name); len(fields) == 2 && fields[0] == "$dotenv" {
+			if dotenv == nil {
+				dotenv = loadDotenv(filepath.Join(f.Dir, ".env"))
+			}
+
+			value, ok := dotenv[fields[1]]
+
+			return value, ok
+		}
+
+		value, ok := f.Variables[name]
+		if !ok || resolving[name] {
+			return "", false
+		}
+
+		resolving[name] = true
+		defer delete(resolving, name)
+
+		resolved, err := Resolve(value, lookup)
+		if err != nil {
+			return "", false
+		}
+
+		return resolved, true
+	}
+
+	return lookup
+}
+
+// HTTPRequest creates *http.Request with all placeholders resolved by lookup.
+func (r Request) HTTPRequest(dir string, lookup Lookup) (*http.Request, error) {
+	rawURL, err := Resolve(r.URL, lookup)
+	if err != nil {
+		return nil, fmt.Errorf("request '%s' URL, err: %w", r.Name, err)
+	}
+
+	body := r.Body
+	if r.BodyFile != "" {
+		pth := r.BodyFile
+		if !filepath.IsAbs(pth) {
+			pth = filepath.Join(dir, pth)
+		}
+
+		content, err := ioutil.ReadFile(pth)
+		if err != nil {
+			return nil, fmt.Errorf("request '%s' could not read body file %s, err: %w", r.Name, pth, err)
+		}
+
+		body = string(content)
+	}
+
+	body, err = Resolve(body, lookup)
+	if err != nil {
+		return nil, fmt.Errorf("request '%s' body, err: %w", r.Name, err)
+	}
+
+	var req *http.Request
+	if body == "" {
+		req, err = http.NewRequest(r.Method, rawURL, nil)
+	} else {
+		req, err = http.NewRequest(r.Method, rawURL, strings.NewReader(body))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("can't create request due to err: %w", err)
+	}
+
+	for _, header := range r.Headers {
+		value, err := Resolve(header[1], lookup)
+		if err != nil {
+			return nil, fmt.Errorf("request '%s' header %s, err: %w", r.Name, header[0], err)
+		}
+
+		if strings.EqualFold(header[0], "Host") {
+			req.Host = value
+			continue
+		}
+
+		req.Header.Add(header[0], value)
+	}
+
+	return req, nil
+}
+
+// Resolve replaces all {{name}} placeholders in text with values obtained by lookup.
+// Placeholders {{$processEnv NAME}} are resolved from environment variables. Following dynamic variables are supported:
+//   - {{$guid}}, {{$uuid}}, {{$random.uuid}} - random UUID v4,
+//   - {{$timestamp}} - current UNIX timestamp, optionally shifted by offset, for example {{$timestamp -1 d}},
+//   - {{$isoTimestamp}} - current UTC time in ISO 8601 format,
+//   - {{$randomInt}} - random integer from [0, 1000), or from [min, max) for {{$randomInt min max}}.
+//
+// All other placeholders, including {{$dotenv NAME}}, are resolved by lookup.
+func Resolve(text string, lookup Lookup) (string, error) {
+	var resolveErr error
+	resolved := placeholderRegExp.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := placeholderRegExp.FindStringSubmatch(placeholder)[1]
+
+		fields := strings.Fields(name)
+		if len(fields) == 2 && fields[0] == "$processEnv" {
+			value, ok := os.LookupEnv(fields[1])
+			if !ok && resolveErr == nil {
+				resolveErr = fmt.Errorf("%w: environment variable %s", ErrMissingVariable, fields[1])
+			}
+
+			return value
+		}
+
+		if value, ok, err := dynamicVariable(fields); ok {
+			if err != nil && resolveErr == nil {
+				resolveErr = fmt.Errorf("placeholder %s, err: %w", placeholder, err)
+			}
+
+			return value
+		}
+
+		value, ok := lookup(name)
+		if !ok {
+			if resolveErr == nil {
+				resolveErr = fmt.Errorf("%w: %s", ErrMissingVariable, name)
+			}
+
+			return placeholder
+		}
+
+		return value
+	})
+
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+
+	return resolved, nil
+}
+
+// LoadEnvironment reads environment of given name from environment file in JetBrains HTTP Client format, for example:
+//
+//	{"dev": {"host": "http://localhost:8080"}, "prod": {"host": "https://example.com"}}
+//
+// Variables from "$shared" environment are included as well and may be overwritten by chosen environment.
+func LoadEnvironment(pth, name string) (map[string]string, error) {
+	data, err := ioutil.ReadFile(pth)
+	if err != nil {
+		return nil, fmt.Errorf("could not read environment file %s, err: %w", pth, err)
+	}
+
+	var environments map[string]map[string]interface{}
+	if err = json.Unmarshal(data, &environments); err != nil {
+		return nil, fmt.Errorf("could not parse environment file %s, err: %w", pth, err)
+	}
+
+	env, ok := environments[name]
+	if !ok {
+		return nil, fmt.Errorf("environment file %s does not have environment '%s'", pth, name)
+	}
+
+	variables := map[string]string{}
+	for _, source := range []map[string]interface{}{environments["$shared"], env} {
+		for key, value := range source {
+			variables[key] = fmt.Sprint(value)
+		}
+	}
+
+	return variables, nil
+}
+
+// dynamicVariable resolves dynamic variable described by placeholder fields.
+// Second return value tells whether fields describe dynamic variable.
+func dynamicVariable(fields []string) (string, bool, error) {
+	if len(fields) == 0 {
+		return "", false, nil
+	}
+
+	switch fields[0] {
+	case "$guid", "$uuid", "$random.uuid":
+		value, err := randomUUID()
+
+		return value, true, err
+	case "$isoTimestamp":
+		return time.Now().UTC().Format(time.RFC3339), true, nil
+	case "$timestamp":
+		if len(fields) == 1 {
+			return strconv.FormatInt(time.Now().Unix(), 10), true, nil
+		}
+
+		if len(fields) != 3 {
+			return "", true, errors.New("expected {{$timestamp}} or {{$timestamp offset unit}}")
+		}
+
+		offset, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return "", true, fmt.Errorf("invalid offset %s, err: %w", fields[1], err)
+		}
+
+		shifted, err := shiftTime(time.Now(), offset, fields[2])
+		if err != nil {
+			return "", true, err
+		}
+
+		return strconv.FormatInt(shifted.Unix(), 10), true, nil
+	case "$randomInt":
+		min, max := int64(0), int64(1000)
+		if len(fields) == 3 {
+			var errMin, errMax error
+			min, errMin = strconv.ParseInt(fields[1], 10, 64)
+			max, errMax = strconv.ParseInt(fields[2], 10, 64)
+			if errMin != nil || errMax != nil {
+				return "", true, fmt.Errorf("invalid range %s %s", fields[1], fields[2])
+			}
+		} else if len(fields) != 1 {
+			return "", true, errors.New("expected {{$randomInt}} or {{$randomInt min max}}")
+		}
+
+		if min >= max {
+			return "", true, fmt.Errorf("min %d should be less than max %d", min, max)
+		}
+
+		n, err := rand.Int(rand.Reader, big.NewInt(max-min))
+		if err != nil {
+			return "", true, err
+		}
+
+		return strconv.FormatInt(min+n.Int64(), 10), true, nil
+	}
+
+	return "", false, nil
+}
+
+// shiftTime shifts t by offset expressed in unit used by VS Code REST Client: y, M, w, d, h, m, s or ms.
+func shiftTime(t time.Time, offset int, unit string) (time.Time, error) {
+	switch unit {
+	case "y":
+		return t.AddDate(offset, 0, 0), nil
+	case "M":
+		return t.AddDate(0, offset, 0), nil
+	case "w":
+		return t.AddDate(0, 0, 7*offset), nil
+	case "d":
+		return t.AddDate(0, 0, offset), nil
+	case "h":
+		return t.Add(time.Duration(offset) * time.Hour), nil
+	case "m":
+		return t.Add(time.Duration(offset) * time.Minute), nil
+	case "s":
+		return t.Add(time.Duration(offset) * time.Second), nil
+	case "ms":
+		return t.Add(time.Duration(offset) * time.Millisecond), nil
+	}
+
+	return time.Time{}, fmt.Errorf("unknown offset unit %s, expected one of: y, M, w, d, h, m, s, ms", unit)
+}
+
+// randomUUID returns random UUID in version 4.
+func randomUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// loadDotenv reads variables from .env file, lines have format NAME=value and lines starting with # are skipped.
+// Missing or unreadable file results in no variables.
+func loadDotenv(pth string) map[string]string {
+	variables := map[string]string{}
+	data, err := ioutil.ReadFile(pth)
+	if err != nil {
+		return variables
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		value := strings.TrimSpace(kv[1])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+
+		variables[strings.TrimSpace(kv[0])] = value
+	}
+
+	return variables
+}
+
+// parseBlock parses lines between "###" separators. Second return value tells whether block contains request.
+func (f File) parseBlock(lines []string, name string) (Request, bool, error) {
+	req := Request{Name: name}
+	i := 0
+
+	// preamble: comments, file variables and annotations before request line
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+
+		if matches := nameAnnotationRegEx.FindStringSubmatch(line); matches != nil {
+			req.Name = matches[2]
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		if matches := fileVariableRegExp.FindStringSubmatch(line); matches != nil {
+			f.Variables[matches[1]] = strings.TrimSpace(matches[2])
+			continue
+		}
+
+		break
+	}
+
+	if i == len(lines) {
+		return Request{}, false, nil
+	}
+
+	requestLine := strings.TrimSpace(lines[i])
+	if matches := requestLineRegExp.FindStringSubmatch(requestLine); matches != nil {
+		req.Method, req.URL = matches[1], matches[2]
+	} else if !strings.ContainsAny(requestLine, " \t") {
+		req.Method, req.URL = http.MethodGet, requestLine
+	} else {
+		return Request{}, false, fmt.Errorf("invalid request line: '%s'", requestLine)
+	}
+	i++
+
+	// multi-line query, for example:
+	//	GET https://example.com/users
+	//	    ?page=1
+	//	    &limit=10
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || !(strings.HasPrefix(line, "?") || strings.HasPrefix(line, "&")) {
+			break
+		}
+
+		req.URL += line
+	}
+
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			i++
+			break
+		}
+
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		kv := strings.SplitN(line, ":", 2)
+		if len(kv) != 2 {
+			return Request{}, false, fmt.Errorf("request '%s' has invalid header: '%s'", req.Name, line)
+		}
+
+		req.Headers = append(req.Headers, [2]string{strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])})
+	}
+
+	var body []string
+	inHandler := false
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		switch {
+		// response handler scripts and response references are not supported, so they are skipped
+		case inHandler:
+			inHandler = !strings.Contains(trimmed, "%}")
+			continue
+		case strings.HasPrefix(trimmed, "> {%"):
+			inHandler = !strings.Contains(trimmed, "%}")
+			continue
+		case strings.HasPrefix(trimmed, "<> ") || strings.HasPrefix(trimmed, "> "):
+			continue
+		}
+
+		body = append(body, lines[i])
+	}
+
+	bodyText := strings.TrimSpace(strings.Join(body, "\n"))
+	if strings.HasPrefix(bodyText, "< ") && !strings.Contains(bodyText, "\n") {
+		req.BodyFile = strings.TrimSpace(strings.TrimPrefix(bodyText, "< "))
+	} else {
+		req.Body = bodyText
+	}
+
+	if req.Name == "" {
+		req.Name = fmt.Sprintf("%s %s", req.Method, req.URL)
+	}
+
+	return req, true, nil
+}
@@ -0,0 +1,297 @@
+package httpfile
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const exampleFile = `@host = https://a.com
+@usersURL = {{host}}/users
+
+### list users
+GET {{usersURL}}
+    ?page=1
+    &limit={{limit}}
+Accept: application/json
+
+###
+# @name createUser
+POST {{usersURL}} HTTP/1.1
+Content-Type: application/json
+// comment between headers
+Authorization: Bearer {{token}}
+
+{
+  "name": "{{$processEnv HTTPFILE_TEST_NAME}}"
+}
+
+> {%
+    client.global.set("id", response.body.id);
+%}
+
+### upload
+PUT {{host}}/files
+Host: b.com
+
+< ./body.txt
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse([]byte(exampleFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantVariables := map[string]string{"host": "https://a.com", "usersURL": "{{host}}/users"}
+	if !reflect.DeepEqual(f.Variables, wantVariables) {
+		t.Errorf("Parse() variables = %v, want %v", f.Variables, wantVariables)
+	}
+
+	wantRequests := []Request{
+		{Name: "list users", Method: "GET", URL: "{{usersURL}}?page=1&limit={{limit}}", Headers: [][2]string{{"Accept", "application/json"}}},
+		{Name: "createUser", Method: "POST", URL: "{{usersURL}}", Headers: [][2]string{
+			{"Content-Type", "application/json"}, {"Authorization", "Bearer {{token}}"},
+		}, Body: "{\n  \"name\": \"{{$processEnv HTTPFILE_TEST_NAME}}\"\n}"},
+		{Name: "upload", Method: "PUT", URL: "{{host}}/files", Headers: [][2]string{{"Host", "b.com"}}, BodyFile: "./body.txt"},
+	}
+	if !reflect.DeepEqual(f.Requests, wantRequests) {
+		t.Errorf("Parse() requests = %+v, want %+v", f.Requests, wantRequests)
+	}
+}
+
+func TestParse_InvalidContent(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "invalid request line", data: "GET a.com b.com c"},
+		{name: "invalid header", data: "GET a.com\nAccept"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.data)); err == nil {
+				t.Errorf("Parse() expected error")
+			}
+		})
+	}
+}
+
+func TestFile_Request(t *testing.T) {
+	f, err := Parse([]byte("GET a.com\n\n###\n// @name b\nPOST b.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if req, err := f.Request("b"); err != nil || req.URL != "b.com" {
+		t.Errorf("Request() = %+v, err: %v", req, err)
+	}
+
+	if req, err := f.Request("GET a.com"); err != nil || req.Method != "GET" {
+		t.Errorf("Request() = %+v, err: %v", req, err)
+	}
+
+	if _, err := f.Request("c"); err == nil {
+		t.Errorf("Request() expected error for missing request")
+	}
+}
+
+func TestRequest_HTTPRequest(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "body.txt"), []byte("file for {{token}}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	pth := filepath.Join(dir, "requests.http")
+	if err := ioutil.WriteFile(pth, []byte(exampleFile), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := Load(pth)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("HTTPFILE_TEST_NAME", "ivo")
+	defer os.Unsetenv("HTTPFILE_TEST_NAME")
+
+	lookup := f.Lookup(func(name string) (string, bool) {
+		values := map[string]string{"token": "abc", "limit": "10"}
+		value, ok := values[name]
+
+		return value, ok
+	})
+
+	t.Run("query request", func(t *testing.T) {
+		req, err := f.Requests[0].HTTPRequest(f.Dir, lookup)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if req.Method != "GET" || req.URL.String() != "https://a.com/users?page=1&limit=10" || req.Header.Get("Accept") != "application/json" {
+			t.Errorf("unexpected request %s %s, headers: %v", req.Method, req.URL, req.Header)
+		}
+	})
+
+	t.Run("body request", func(t *testing.T) {
+		req, err := f.Requests[1].HTTPRequest(f.Dir, lookup)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, _ := ioutil.ReadAll(req.Body)
+		if string(body) != "{\n  \"name\": \"ivo\"\n}" || req.Header.Get("Authorization") != "Bearer abc" {
+			t.Errorf("unexpected body %s or headers %v", body, req.Header)
+		}
+	})
+
+	t.Run("body file request", func(t *testing.T) {
+		req, err := f.Requests[2].HTTPRequest(f.Dir, lookup)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, _ := ioutil.ReadAll(req.Body)
+		if string(body) != "file for abc" || req.Host != "b.com" {
+			t.Errorf("unexpected body %s or host %s", body, req.Host)
+		}
+	})
+
+	t.Run("missing variable", func(t *testing.T) {
+		_, err := f.Requests[0].HTTPRequest(f.Dir, f.Lookup())
+		if !errors.Is(err, ErrMissingVariable) {
+			t.Errorf("expected ErrMissingVariable, got %v", err)
+		}
+	})
+}
+
+func TestFile_Lookup_CyclicVariables(t *testing.T) {
+	f, err := Parse([]byte("@a = {{b}}\n@b = {{a}}\nGET {{a}}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Resolve(f.Requests[0].URL, f.Lookup()); !errors.Is(err, ErrMissingVariable) {
+		t.Errorf("expected ErrMissingVariable, got %v", err)
+	}
+}
+
+func TestFile_Lookup_Dotenv(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, ".env"), []byte("# comment\nTOKEN=abc\nNAME=\"ivo\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	pth := filepath.Join(dir, "requests.http")
+	if err := ioutil.WriteFile(pth, []byte("GET https://a.com/{{$dotenv NAME}}?token={{ $dotenv TOKEN }}\n\n###\nGET https://a.com/{{$dotenv MISSING}}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("NAME", "from-process-env")
+	defer os.Unsetenv("NAME")
+
+	f, err := Load(pth)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Resolve(f.Requests[0].URL, f.Lookup())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != "https://a.com/ivo?token=abc" {
+		t.Errorf("unexpected URL %s", got)
+	}
+
+	if _, err = Resolve(f.Requests[1].URL, f.Lookup()); !errors.Is(err, ErrMissingVariable) {
+		t.Errorf("expected ErrMissingVariable, got %v", err)
+	}
+}
+
+func TestResolve_DynamicVariables(t *testing.T) {
+	noLookup := func(name string) (string, bool) { return "", false }
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		text    string
+		check   func(value string) bool
+		wantErr bool
+	}{
+		{name: "guid", text: "{{$guid}}", check: regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString},
+		{name: "uuid", text: "{{$uuid}}", check: regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString},
+		{name: "timestamp", text: "{{$timestamp}}", check: func(value string) bool {
+			ts, err := strconv.ParseInt(value, 10, 64)
+			return err == nil && ts >= now.Unix() && ts <= now.Unix()+60
+		}},
+		{name: "timestamp with offset", text: "{{$timestamp -1 d}}", check: func(value string) bool {
+			ts, err := strconv.ParseInt(value, 10, 64)
+			return err == nil && ts >= now.AddDate(0, 0, -1).Unix() && ts <= now.AddDate(0, 0, -1).Unix()+60
+		}},
+		{name: "iso timestamp", text: "{{$isoTimestamp}}", check: func(value string) bool {
+			_, err := time.Parse(time.RFC3339, value)
+			return err == nil
+		}},
+		{name: "random int in range", text: "{{$randomInt 5 7}}", check: func(value string) bool {
+			return value == "5" || value == "6"
+		}},
+		{name: "random int", text: "{{$randomInt}}", check: func(value string) bool {
+			n, err := strconv.Atoi(value)
+			return err == nil && n >= 0 && n < 1000
+		}},
+		{name: "timestamp with unknown unit", text: "{{$timestamp 1 x}}", wantErr: true},
+		{name: "random int with empty range", text: "{{$randomInt 7 5}}", wantErr: true},
+		{name: "random int with invalid range", text: "{{$randomInt a 5}}", wantErr: true},
+		{name: "unknown dynamic variable", text: "{{$unknown}}", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(tt.text, noLookup)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !tt.check(got) {
+				t.Errorf("Resolve() got unexpected value %s", got)
+			}
+		})
+	}
+}
+
+func TestLoadEnvironment(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), "http-client.env.json")
+	content := `{"$shared": {"host": "http://localhost", "version": 1}, "dev": {"token": "abc"}, "prod": {"host": "https://a.com"}}`
+	if err := ioutil.WriteFile(pth, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		env     string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "shared and dev variables", env: "dev", want: map[string]string{"host": "http://localhost", "version": "1", "token": "abc"}},
+		{name: "prod overwrites shared", env: "prod", want: map[string]string{"host": "https://a.com", "version": "1"}},
+		{name: "missing environment", env: "test", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadEnvironment(pth, tt.env)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadEnvironment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadEnvironment() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package mathutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// divisibilityEpsilon is tolerance of floating point remainder used by IsDivisibleBy.
+const divisibilityEpsilon = 1e-9
+
+// ToFloat64 converts number of any Go numeric type, json.Number or string holding number into float64.
+func ToFloat64(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case json.Number:
+		return v.Float64()
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("value %q is not a number", v)
+		}
+
+		return f, nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	default:
+		return 0, fmt.Errorf("value %v of type %T is not a number", value, value)
+	}
+}
+
+// IsDivisibleBy checks whether value is divisible by divisor without remainder.
+// Divisor should not be zero.
+func IsDivisibleBy(value, divisor float64) (bool, error) {
+	if divisor == 0 {
+		return false, fmt.Errorf("divisor can't be zero")
+	}
+
+	if value == math.Trunc(value) && divisor == math.Trunc(divisor) && math.Abs(value) < 1<<53 && math.Abs(divisor) < 1<<53 {
+		return int64(value)%int64(divisor) == 0, nil
+	}
+
+	return math.Abs(math.Remainder(value, divisor)) < divisibilityEpsilon, nil
+}
@@ -0,0 +1,66 @@
+package mathutils
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestToFloat64(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    float64
+		wantErr bool
+	}{
+		{name: "float64", value: 1.5, want: 1.5},
+		{name: "uint64", value: uint64(10), want: 10},
+		{name: "int64", value: int64(-3), want: -3},
+		{name: "float32", value: float32(0.5), want: 0.5},
+		{name: "json number", value: json.Number("12.25"), want: 12.25},
+		{name: "string", value: " 100 ", want: 100},
+		{name: "not number string", value: "abc", wantErr: true},
+		{name: "bool", value: true, wantErr: true},
+		{name: "nil", value: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToFloat64(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToFloat64() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ToFloat64() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsDivisibleBy(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   float64
+		divisor float64
+		want    bool
+		wantErr bool
+	}{
+		{name: "integers divisible", value: 10, divisor: 5, want: true},
+		{name: "integers not divisible", value: 10, divisor: 3, want: false},
+		{name: "negative integers", value: -9, divisor: 3, want: true},
+		{name: "floats divisible", value: 0.3, divisor: 0.1, want: true},
+		{name: "floats not divisible", value: 0.35, divisor: 0.1, want: false},
+		{name: "zero divisor", value: 1, divisor: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IsDivisibleBy(tt.value, tt.divisor)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("IsDivisibleBy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("IsDivisibleBy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+// Package pagination holds utilities for traversing paginated HTTP(s) API resources.
+package pagination
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// StrategyLink describes pagination that follows URL from Link header with rel="next".
+	StrategyLink Strategy = "link"
+
+	// StrategyCursor describes pagination that passes cursor obtained from response body to the next request.
+	StrategyCursor Strategy = "cursor"
+
+	// StrategyPage describes pagination that increments page number query param by one.
+	StrategyPage Strategy = "page"
+
+	// StrategyOffset describes pagination that increments offset query param by page size.
+	StrategyOffset Strategy = "offset"
+)
+
+// Strategy describes the way of obtaining next page of resource.
+type Strategy string
+
+// Config describes how to traverse paginated resource.
+type Config struct {
+	// Strategy is pagination strategy, one of: link, cursor, page, offset.
+	Strategy Strategy `json:"strategy" yaml:"strategy"`
+
+	// Param is name of query param that holds cursor, page number or offset.
+	// For cursor strategy it may be empty, then cursor is treated as URL of the next page.
+	Param string `json:"param" yaml:"param"`
+
+	// Cursor is expression pointing at next page cursor in response body. Used only by cursor strategy.
+	Cursor string `json:"cursor" yaml:"cursor"`
+
+	// Start is number of first page or first offset, used when request does not have Param query param set.
+	// Defaults to 1 for page strategy and 0 for offset strategy.
+	Start *int `json:"start" yaml:"start"`
+
+	// Size is offset increment. Used only by offset strategy, if 0, number of items on current page is used.
+	Size int `json:"size" yaml:"size"`
+}
+
+// Validate checks whether Config is complete for chosen Strategy.
+func (c Config) Validate() error {
+	switch c.Strategy {
+	case StrategyLink:
+		return nil
+	case StrategyCursor:
+		if c.Cursor == "" {
+			return fmt.Errorf("%s pagination requires 'cursor' expression", c.Strategy)
+		}
+
+		return nil
+	case StrategyPage, StrategyOffset:
+		if c.Param == "" {
+			return fmt.Errorf("%s pagination requires 'param' query param name", c.Strategy)
+		}
+
+		if c.Size < 0 {
+			return fmt.Errorf("%s pagination 'size' should not be negative", c.Strategy)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("unknown pagination strategy: '%s', available: %s, %s, %s, %s",
+			c.Strategy, StrategyLink, StrategyCursor, StrategyPage, StrategyOffset)
+	}
+}
+
+// FirstPosition returns page number or offset of the first page of request URL u.
+func (c Config) FirstPosition(u *url.URL) (int, error) {
+	if raw := u.Query().Get(c.Param); raw != "" {
+		position, err := strconv.Atoi(raw)
+		if err != nil {
+			return 0, fmt.Errorf("query param %s value '%s' is not integer", c.Param, raw)
+		}
+
+		return position, nil
+	}
+
+	if c.Start != nil {
+		return *c.Start, nil
+	}
+
+	if c.Strategy == StrategyPage {
+		return 1, nil
+	}
+
+	return 0, nil
+}
+
+// NextLink returns URL of the next page from Link header (RFC 8288) resolved against base URL.
+// Second return value tells whether next link was found.
+func NextLink(base *url.URL, header http.Header) (*url.URL, bool) {
+	for _, value := range header.Values("Link") {
+		for _, l := range parseLinks(value) {
+			for _, rel := range strings.Fields(l.params["rel"]) {
+				if strings.EqualFold(rel, "next") {
+					next, err := base.Parse(l.target)
+					if err != nil {
+						return nil, false
+					}
+
+					return next, true
+				}
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// FormatCursor returns cursor found in response body as text. Numbers are written without exponent,
+// for example: 1000000 instead of 1e+06.
+func FormatCursor(cursor interface{}) string {
+	switch c := cursor.(type) {
+	case float64:
+		return strconv.FormatFloat(c, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(c), 'f', -1, 32)
+	default:
+		return fmt.Sprint(cursor)
+	}
+}
+
+// WithQueryParam returns copy of u with query param name set to value.
+func WithQueryParam(u *url.URL, name, value string) *url.URL {
+	next := *u
+	query := next.Query()
+	query.Set(name, value)
+	next.RawQuery = query.Encode()
+
+	return &next
+}
+
+// link is single link-value of Link header.
+type link struct {
+	// target is URI reference written between angle brackets.
+	target string
+
+	// params holds link-params by lower-cased names, quoted values are unquoted.
+	params map[string]string
+}
+
+// parseLinks parses link-values of Link header value, for example: </users?page=2>; rel="next", </users?page=9>; rel="last".
+// Commas and semicolons within URI reference or quoted strings do not separate link-values nor link-params.
+// Malformed link-values are omitted.
+func parseLinks(value string) []link {
+	var links []link
+	pos := 0
+	for pos < len(value) {
+		start := strings.IndexByte(value[pos:], '<')
+		if start < 0 {
+			break
+		}
+
+		end := strings.IndexByte(value[pos+start:], '>')
+		if end < 0 {
+			break
+		}
+
+		l := link{target: value[pos+start+1 : pos+start+end], params: map[string]string{}}
+		pos += start + end + 1
+
+		for {
+			pos = skipSpaces(value, pos)
+			if pos >= len(value) || value[pos] != ';' {
+				break
+			}
+
+			var name, val string
+			name, val, pos = parseParam(value, pos+1)
+			if _, ok := l.params[name]; !ok && name != "" {
+				l.params[name] = val
+			}
+		}
+
+		links = append(links, l)
+
+		// skip to the next link-value, omitting malformed remainder of current one
+		for pos < len(value) && value[pos] != ',' {
+			pos++
+		}
+	}
+
+	return links
+}
+
+// parseParam parses link-param of form name="value" or name=value starting at pos. It returns lower-cased name,
+// unquoted value and position right after link-param.
+func parseParam(value string, pos int) (string, string, int) {
+	start := skipSpaces(value, pos)
+	pos = start
+	for pos < len(value) && !strings.ContainsRune("=;,", rune(value[pos])) {
+		pos++
+	}
+
+	name := strings.ToLower(strings.TrimSpace(value[start:pos]))
+	if pos >= len(value) || value[pos] != '=' {
+		return name, "", pos
+	}
+
+	pos = skipSpaces(value, pos+1)
+	if pos < len(value) && value[pos] == '"' {
+		var sb strings.Builder
+		for pos++; pos < len(value); pos++ {
+			if value[pos] == '\\' && pos+1 < len(value) {
+				pos++
+			} else if value[pos] == '"' {
+				pos++
+				break
+			}
+
+			sb.WriteByte(value[pos])
+		}
+
+		return name, sb.String(), pos
+	}
+
+	start = pos
+	for pos < len(value) && value[pos] != ';' && value[pos] != ',' {
+		pos++
+	}
+
+	return name, strings.TrimSpace(value[start:pos]), pos
+}
+
+func skipSpaces(value string, pos int) int {
+	for pos < len(value) && (value[pos] == ' ' || value[pos] == '\t') {
+		pos++
+	}
+
+	return pos
+}
@@ -0,0 +1,136 @@
+package pagination
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{name: "unknown strategy", config: Config{Strategy: "abc"}, wantErr: true},
+		{name: "cursor without cursor expression", config: Config{Strategy: StrategyCursor, Param: "cursor"}, wantErr: true},
+		{name: "page without param", config: Config{Strategy: StrategyPage}, wantErr: true},
+		{name: "offset with negative size", config: Config{Strategy: StrategyOffset, Param: "offset", Size: -1}, wantErr: true},
+		{name: "link", config: Config{Strategy: StrategyLink}, wantErr: false},
+		{name: "cursor", config: Config{Strategy: StrategyCursor, Cursor: "meta.next"}, wantErr: false},
+		{name: "page", config: Config{Strategy: StrategyPage, Param: "page"}, wantErr: false},
+		{name: "offset", config: Config{Strategy: StrategyOffset, Param: "offset", Size: 10}, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_FirstPosition(t *testing.T) {
+	five := 5
+	tests := []struct {
+		name    string
+		config  Config
+		rawURL  string
+		want    int
+		wantErr bool
+	}{
+		{name: "page default", config: Config{Strategy: StrategyPage, Param: "page"}, rawURL: "http://a.com/x", want: 1},
+		{name: "offset default", config: Config{Strategy: StrategyOffset, Param: "offset"}, rawURL: "http://a.com/x", want: 0},
+		{name: "start from config", config: Config{Strategy: StrategyPage, Param: "page", Start: &five}, rawURL: "http://a.com/x", want: 5},
+		{name: "start from query", config: Config{Strategy: StrategyPage, Param: "page", Start: &five}, rawURL: "http://a.com/x?page=3", want: 3},
+		{name: "invalid query value", config: Config{Strategy: StrategyPage, Param: "page"}, rawURL: "http://a.com/x?page=a", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := url.Parse(tt.rawURL)
+			got, err := tt.config.FirstPosition(u)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FirstPosition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("FirstPosition() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextLink(t *testing.T) {
+	base, _ := url.Parse("https://api.example.com/users?page=1")
+	tests := []struct {
+		name      string
+		links     []string
+		want      string
+		wantFound bool
+	}{
+		{name: "no Link header", links: nil, wantFound: false},
+		{name: "no next relation", links: []string{`<https://api.example.com/users?page=1>; rel="prev"`}, wantFound: false},
+		{name: "absolute next", links: []string{`<https://api.example.com/users?page=2>; rel="next", <https://api.example.com/users?page=9>; rel="last"`},
+			want: "https://api.example.com/users?page=2", wantFound: true},
+		{name: "relative next in second header", links: []string{`</users?page=0>; rel="first"`, `</users?page=2>; rel=next`},
+			want: "https://api.example.com/users?page=2", wantFound: true},
+		{name: "multiple relations", links: []string{`</users?page=2>; title="x"; rel="next last"`},
+			want: "https://api.example.com/users?page=2", wantFound: true},
+		{name: "comma and semicolon in URL", links: []string{`</users?page=1>; rel="prev", </users?ids=1,2;x&page=2>; rel="next"`},
+			want: "https://api.example.com/users?ids=1,2;x&page=2", wantFound: true},
+		{name: "comma in quoted param", links: []string{`</users?page=9>; title="last, really; rel=next"; rel="last", </users?page=2>; REL=next`},
+			want: "https://api.example.com/users?page=2", wantFound: true},
+		{name: "first relation wins", links: []string{`</users?page=2>; rel="prev"; rel="next"`}, wantFound: false},
+		{name: "malformed link-value", links: []string{`users?page=5; rel="next", </users?page=2>; rel="next"`},
+			want: "https://api.example.com/users?page=2", wantFound: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for _, l := range tt.links {
+				header.Add("Link", l)
+			}
+
+			got, found := NextLink(base, header)
+			if found != tt.wantFound {
+				t.Fatalf("NextLink() found = %v, want %v", found, tt.wantFound)
+			}
+
+			if found && got.String() != tt.want {
+				t.Errorf("NextLink() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithQueryParam(t *testing.T) {
+	u, _ := url.Parse("https://api.example.com/users?limit=10&page=1")
+	got := WithQueryParam(u, "page", "2")
+
+	if got.String() != "https://api.example.com/users?limit=10&page=2" {
+		t.Errorf("WithQueryParam() = %s", got)
+	}
+
+	if u.String() != "https://api.example.com/users?limit=10&page=1" {
+		t.Errorf("WithQueryParam() modified original URL: %s", u)
+	}
+}
+
+func TestFormatCursor(t *testing.T) {
+	tests := []struct {
+		cursor interface{}
+		want   string
+	}{
+		{cursor: float64(1000000), want: "1000000"},
+		{cursor: 12.5, want: "12.5"},
+		{cursor: json.Number("12345678901234567890"), want: "12345678901234567890"},
+		{cursor: uint64(1000000), want: "1000000"},
+		{cursor: "abc", want: "abc"},
+	}
+	for _, tt := range tests {
+		if got := FormatCursor(tt.cursor); got != tt.want {
+			t.Errorf("FormatCursor(%#v) = %s, want %s", tt.cursor, got, tt.want)
+		}
+	}
+}
@@ -4,11 +4,17 @@ package pathfinder
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/oliveagle/jsonpath"
 	"github.com/pawelWritesCode/qjson"
 )
 
+// qjsonExprRegExp matches expressions supported by pawelWritesCode/qjson library: keys separated by dot,
+// each optionally followed by index, for example: users[0].name.
+var qjsonExprRegExp = regexp.MustCompile(`^[^.\[\]]+(\[\d+\])?(\.[^.\[\]]+(\[\d+\])?)*$`)
+
 // QJSONFinder represents implementation of JSON path from https://github.com/pawelWritesCode/qjson library
 type QJSONFinder struct{}
 
@@ -35,12 +41,29 @@ func NewDynamicJSONPathFinder(qjson QJSONFinder, oliveagleJSONFinder OliveagleJS
 	return &DynamicJSONPathFinder{qjson: qjson, oliveagleJSONFinder: oliveagleJSONFinder}
 }
 
-// Find obtains data from jsonBytes according to given expr valid with oliveagle/jsonpath library
+// Find obtains data from jsonBytes according to given expr valid with pawelWritesCode/qjson library
 func (Q QJSONFinder) Find(expr string, jsonBytes []byte) (interface{}, error) {
-	return qjson.Resolve(expr, jsonBytes)
+	if !json.Valid(jsonBytes) {
+		return nil, fmt.Errorf("data is not valid JSON")
+	}
+
+	if !qjsonExprRegExp.MatchString(expr) {
+		return nil, fmt.Errorf("invalid qjson expression: %s", expr)
+	}
+
+	node, err := qjson.Resolve(expr, jsonBytes)
+	if err != nil {
+		if isQJSONNotFoundError(err) {
+			return nil, fmt.Errorf("%w: %v", ErrNotFound, err)
+		}
+
+		return nil, err
+	}
+
+	return node, nil
 }
 
-// Find obtains data from jsonBytes according to given expr valid with pawelWritesCode/qjson library
+// Find obtains data from jsonBytes according to given expr valid with oliveagle/jsonpath library
 func (o OliveagleJSONFinder) Find(expr string, jsonBytes []byte) (interface{}, error) {
 	var jsonData interface{}
 	err := json.Unmarshal(jsonBytes, &jsonData)
@@ -48,7 +71,17 @@ func (o OliveagleJSONFinder) Find(expr string, jsonBytes []byte) (interface{}, e
 		return nil, err
 	}
 
-	return jsonpath.JsonPathLookup(interface{}(jsonData), expr)
+	compiled, err := jsonpath.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := compiled.Lookup(jsonData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+
+	return node, nil
 }
 
 // Find obtains data from jsonBytes according to given expr.
@@ -64,3 +97,10 @@ func (d DynamicJSONPathFinder) Find(expr string, jsonBytes []byte) (interface{},
 
 	return d.qjson.Find(expr, jsonBytes)
 }
+
+// isQJSONNotFoundError checks whether error of pawelWritesCode/qjson library reports missing key or index.
+func isQJSONNotFoundError(err error) bool {
+	msg := err.Error()
+
+	return (strings.HasPrefix(msg, "key ") && strings.HasSuffix(msg, " does not exist")) || strings.Contains(msg, " does not have index ")
+}
@@ -1,9 +1,15 @@
 // Package pathfinder holds utilities for working with different data formats.
 package pathfinder
 
+import "errors"
+
+// ErrNotFound occurs when data and expression are valid, but expression does not point at any node.
+var ErrNotFound = errors.New("node not found")
+
 // PathFinder describes ability to obtain node(s) from data in fixed data format
 type PathFinder interface {
 
-	// Find obtains data from bytes according to given expression
+	// Find obtains data from bytes according to given expression.
+	// Missing node should be reported as error wrapping ErrNotFound.
 	Find(expr string, bytes []byte) (interface{}, error)
 }
@@ -0,0 +1,47 @@
+package pathfinder
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPathFinders_ErrNotFound(t *testing.T) {
+	tests := []struct {
+		name         string
+		finder       PathFinder
+		expr         string
+		data         string
+		wantNotFound bool
+	}{
+		{name: "JSON path missing key", finder: NewOliveagleJSONFinder(), expr: "$.user.email", data: `{"user": {"name": "abc"}}`, wantNotFound: true},
+		{name: "JSON path index of object", finder: NewOliveagleJSONFinder(), expr: "$.user[1]", data: `{"user": {"name": "abc"}}`, wantNotFound: true},
+		{name: "JSON path invalid expression", finder: NewOliveagleJSONFinder(), expr: "user.email", data: `{"user": {}}`},
+		{name: "JSON path malformed data", finder: NewOliveagleJSONFinder(), expr: "$.user", data: `{"user": `},
+		{name: "qjson missing key", finder: NewQJSONFinder(), expr: "user.email", data: `{"user": {"name": "abc"}}`, wantNotFound: true},
+		{name: "qjson missing index", finder: NewQJSONFinder(), expr: "users[3]", data: `{"users": ["abc"]}`, wantNotFound: true},
+		{name: "qjson not digit index", finder: NewQJSONFinder(), expr: "users[a]", data: `{"users": ["abc"]}`},
+		{name: "qjson unterminated index", finder: NewQJSONFinder(), expr: "users[", data: `{"users": ["abc"]}`},
+		{name: "qjson empty key", finder: NewQJSONFinder(), expr: "user..email", data: `{"user": {}}`},
+		{name: "qjson index of object", finder: NewQJSONFinder(), expr: "user[0]", data: `{"user": {"name": "abc"}}`},
+		{name: "qjson malformed data", finder: NewQJSONFinder(), expr: "user", data: `<user/>`},
+		{name: "YAML path missing key", finder: NewGoccyGoYamlFinder(), expr: "$.user.email", data: "user:\n  name: abc\n", wantNotFound: true},
+		{name: "YAML path index out of range", finder: NewGoccyGoYamlFinder(), expr: "$.users[3]", data: "users:\n  - abc\n", wantNotFound: true},
+		{name: "YAML path invalid expression", finder: NewGoccyGoYamlFinder(), expr: "$..[", data: "user: abc\n"},
+		{name: "XPath missing element", finder: NewAntchfxXMLFinder(), expr: "//email", data: `<user><name>abc</name></user>`, wantNotFound: true},
+		{name: "XPath invalid expression", finder: NewAntchfxXMLFinder(), expr: "//email[", data: `<user/>`},
+		{name: "XPath malformed data", finder: NewAntchfxXMLFinder(), expr: "//email", data: `<user></name>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.finder.Find(tt.expr, []byte(tt.data))
+			if err == nil {
+				t.Fatalf("Find() should return error")
+			}
+
+			if got := errors.Is(err, ErrNotFound); got != tt.wantNotFound {
+				t.Errorf("errors.Is(%v, ErrNotFound) = %v, want %v", err, got, tt.wantNotFound)
+			}
+		})
+	}
+}
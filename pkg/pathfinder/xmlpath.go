@@ -38,5 +38,41 @@ func (a AntchfxXMLFinder) Find(expr string, bytes []byte) (interface{}, error) {
 		return results, nil
 	}
 
-	return nil, fmt.Errorf("could not find %s in given XML bytes", expr)
+	return nil, fmt.Errorf("%w: could not find %s in given XML bytes", ErrNotFound, expr)
+}
+
+// FindXMLElement returns XML of single element found with XPath expr. Namespaces declared on ancestors
+// of element are declared on it, so returned XML is standalone document.
+func FindXMLElement(expr string, bytes []byte) ([]byte, error) {
+	parser, err := xmlquery.Parse(bytes2.NewReader(bytes))
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := xmlquery.QueryAll(parser, expr)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nodes) != 1 || nodes[0].Type != xmlquery.ElementNode {
+		return nil, fmt.Errorf("expected single element for %s in given XML bytes, found %d node(s)", expr, len(nodes))
+	}
+
+	element := nodes[0]
+	declared := map[string]bool{}
+	for n := element; n != nil; n = n.Parent {
+		for _, attr := range n.Attr {
+			isDeclaration := attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns")
+			if !isDeclaration || declared[attr.Name.Space+":"+attr.Name.Local] {
+				continue
+			}
+
+			declared[attr.Name.Space+":"+attr.Name.Local] = true
+			if n != element {
+				element.Attr = append(element.Attr, attr)
+			}
+		}
+	}
+
+	return []byte(element.OutputXML(true)), nil
 }
@@ -169,3 +169,34 @@ func TestAntchfxXMLFinder_Find(t *testing.T) {
 		})
 	}
 }
+
+func TestFindXMLElement(t *testing.T) {
+	s := []byte(`<?xml version="1.0"?>
+<s:Envelope xmlns:s="urn:soap" xmlns="urn:shop"><s:Body><order id="1"><item>a</item><item>b</item></order></s:Body></s:Envelope>`)
+
+	tests := []struct {
+		name    string
+		expr    string
+		want    string
+		wantErr bool
+	}{
+		{name: "element with namespaces of ancestors", expr: "//order", want: `<order id="1" xmlns:s="urn:soap" xmlns="urn:shop"><item>a</item><item>b</item></order>`},
+		{name: "prefixed element", expr: "//s:Body", want: `<s:Body xmlns:s="urn:soap" xmlns="urn:shop"><order id="1"><item>a</item><item>b</item></order></s:Body>`},
+		{name: "many elements", expr: "//item", wantErr: true},
+		{name: "attribute", expr: "//order/@id", wantErr: true},
+		{name: "not existing element", expr: "//user", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindXMLElement(tt.expr, s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FindXMLElement() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("FindXMLElement() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package pathfinder
 
 import (
 	"bytes"
+	"fmt"
 
 	"github.com/goccy/go-yaml"
 )
@@ -20,6 +21,11 @@ func (g GoccyGoYamlFinder) Find(expr string, jsonBytes []byte) (interface{}, err
 
 	var result interface{}
 	err = yamlPath.Read(bytes.NewReader(jsonBytes), &result)
+	// invalid query means that node type does not match expression, for example: index of map
+	if yaml.IsNotFoundNodeError(err) || yaml.IsInvalidQueryError(err) {
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,202 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/pawelWritesCode/gdutils/pkg/compare"
+	"github.com/pawelWritesCode/gdutils/pkg/format"
+)
+
+// jsonNumberRegExp matches numbers written according to JSON grammar. Values like 007 or +1 are not matched.
+var jsonNumberRegExp = regexp.MustCompile(`^-?(0|[1-9]\d*)(\.\d+)?([eE][+-]?\d+)?$`)
+
+// ToJSONDocument recognizes format of data and converts it to JSON document, which may be validated against JSON schema.
+// Supported formats are JSON, YAML and XML. XML is converted according to mapping described in XMLToJSON.
+// Data starting with { or [ is JSON document, so error is returned when it is malformed, instead of trying YAML.
+// Data in any other format is returned unchanged as plain text, so it may be rejected by validator.
+func ToJSONDocument(data []byte) (format.DataFormat, []byte, error) {
+	return ToJSONDocumentOfContentType(data, "")
+}
+
+// ToJSONDocumentOfContentType works like ToJSONDocument, but data starting with { or [ is converted from YAML
+// when contentType, for example value of Content-Type header, declares YAML media type.
+func ToJSONDocumentOfContentType(data []byte, contentType string) (format.DataFormat, []byte, error) {
+	switch {
+	case format.IsJSON(data):
+		return format.JSON, data, nil
+	case startsLikeJSON(data) && !isYAMLMediaType(contentType):
+		var v interface{}
+		err := json.Unmarshal(data, &v)
+		return format.JSON, nil, fmt.Errorf("invalid JSON document, err: %w", err)
+	case format.IsXML(data):
+		document, err := XMLToJSON(data)
+		return format.XML, document, err
+	case format.IsYAML(data):
+		document, err := YAMLToJSON(data)
+		return format.YAML, document, err
+	default:
+		return format.PlainText, data, nil
+	}
+}
+
+// startsLikeJSON checks whether first non-whitespace character of data opens JSON object or array.
+func startsLikeJSON(data []byte) bool {
+	data = bytes.TrimSpace(data)
+
+	return len(data) > 0 && (data[0] == '{' || data[0] == '[')
+}
+
+// isYAMLMediaType checks whether contentType declares YAML, for example: application/yaml or application/x-yaml.
+func isYAMLMediaType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return strings.HasSuffix(mediaType, "yaml")
+}
+
+// YAMLToJSON converts YAML document to JSON. Keys of mappings are converted to strings.
+func YAMLToJSON(data []byte) ([]byte, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("invalid YAML document, err: %w", err)
+	}
+
+	return json.Marshal(compare.Normalize(v))
+}
+
+// XMLToJSON converts XML document to JSON according to following mapping:
+//
+// Root element is converted to value, its name is omitted.
+// Element without attributes and child elements is converted to its trimmed text:
+// true and false become booleans, numbers written according to JSON grammar become numbers,
+// empty text becomes null and any other text remains string.
+// Other elements are converted to objects. Attributes become keys prefixed with @, child elements become keys
+// named after their local names, repeated child elements of the same name become arrays
+// and non-whitespace text of element becomes key #text. Namespaces and their prefixes are omitted.
+//
+// For example, <order id="1"><item>a</item><item>b</item><note/></order> is converted to
+// {"@id": 1, "item": ["a", "b"], "note": null}.
+//
+// XML does not describe types, so they are guessed from every document separately and the same element may be
+// converted differently: <zip>12345</zip> becomes number while <zip>01234</zip> remains string, and single <item>
+// becomes object or scalar while repeated ones become array. Schemas should allow each variant, for example
+// {"type": ["string", "number"]} or {"oneOf": [{"type": "array", "items": item}, item]}.
+func XMLToJSON(data []byte) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var root *xmlElement
+	var stack []*xmlElement
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid XML document, err: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			e := &xmlElement{name: t.Name.Local}
+			for _, attr := range t.Attr {
+				if attr.Name.Space != "xmlns" && attr.Name.Local != "xmlns" {
+					e.attrs = append(e.attrs, attr)
+				}
+			}
+
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, e)
+			} else {
+				root = e
+			}
+
+			stack = append(stack, e)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, errors.New("invalid XML document, root element not found")
+	}
+
+	return json.Marshal(root.value())
+}
+
+type xmlElement struct {
+	name     string
+	attrs    []xml.Attr
+	children []*xmlElement
+	text     bytes.Buffer
+}
+
+// value converts element to JSON value.
+func (e *xmlElement) value() interface{} {
+	text := strings.TrimSpace(e.text.String())
+	if len(e.attrs) == 0 && len(e.children) == 0 {
+		return xmlScalar(text)
+	}
+
+	object := make(map[string]interface{}, len(e.attrs)+len(e.children))
+	for _, attr := range e.attrs {
+		object["@"+attr.Name.Local] = xmlScalar(attr.Value)
+	}
+
+	for _, child := range e.children {
+		v := child.value()
+		existing, ok := object[child.name]
+		switch {
+		case !ok:
+			object[child.name] = v
+		case isSlice(existing):
+			object[child.name] = append(existing.([]interface{}), v)
+		default:
+			object[child.name] = []interface{}{existing, v}
+		}
+	}
+
+	if text != "" {
+		object["#text"] = xmlScalar(text)
+	}
+
+	return object
+}
+
+func isSlice(v interface{}) bool {
+	_, ok := v.([]interface{})
+
+	return ok
+}
+
+func xmlScalar(text string) interface{} {
+	switch {
+	case text == "":
+		return nil
+	case text == "true" || text == "false":
+		b, _ := strconv.ParseBool(text)
+		return b
+	case jsonNumberRegExp.MatchString(text):
+		return json.Number(text)
+	default:
+		return text
+	}
+}
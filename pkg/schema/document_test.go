@@ -0,0 +1,110 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/pawelWritesCode/gdutils/pkg/format"
+)
+
+func TestToJSONDocument(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		wantFormat format.DataFormat
+		want       string
+	}{
+		{name: "JSON", data: `{"a": [1, 2]}`, wantFormat: format.JSON, want: `{"a": [1, 2]}`},
+		{name: "YAML", data: "user:\n  name: ivo\n  age: 30\n  tags: [a, b]\n  1: one", wantFormat: format.YAML,
+			want: `{"user":{"1":"one","age":30,"name":"ivo","tags":["a","b"]}}`},
+		{name: "XML", data: `<?xml version="1.0"?>
+<order xmlns="urn:shop" xmlns:x="urn:x" id="007" paid="true">
+	<item>a</item>
+	<item x:sku="1">b</item>
+	<note/>
+	<total>10.50</total>
+	<user><name>ivo</name></user>
+</order>`, wantFormat: format.XML,
+			want: `{"@id":"007","@paid":true,"item":["a",{"#text":"b","@sku":1}],"note":null,"total":10.50,"user":{"name":"ivo"}}`},
+		{name: "plain text", data: `abc`, wantFormat: format.PlainText, want: `abc`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFormat, got, err := ToJSONDocument([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("ToJSONDocument() error = %v", err)
+			}
+
+			if gotFormat != tt.wantFormat {
+				t.Errorf("ToJSONDocument() format = %s, want %s", gotFormat, tt.wantFormat)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("ToJSONDocument() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToJSONDocumentOfContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		contentType string
+		wantFormat  format.DataFormat
+		want        string
+		wantErr     string
+	}{
+		{name: "malformed JSON object", data: `{"a":1,}`, wantFormat: format.JSON,
+			wantErr: "invalid JSON document, err: invalid character '}' looking for beginning of object key string"},
+		{name: "malformed JSON array", data: " [1, 2", contentType: "application/json", wantFormat: format.JSON,
+			wantErr: "invalid JSON document, err: unexpected end of JSON input"},
+		{name: "YAML flow mapping without content type", data: `{a: 1}`, wantFormat: format.JSON,
+			wantErr: "invalid JSON document, err: invalid character 'a' looking for beginning of object key string"},
+		{name: "YAML flow mapping of YAML content type", data: "{a: 1, b: [x]}", contentType: "application/x-yaml; charset=utf-8",
+			wantFormat: format.YAML, want: `{"a":1,"b":["x"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFormat, got, err := ToJSONDocumentOfContentType([]byte(tt.data), tt.contentType)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ToJSONDocumentOfContentType() error = %v, want %s", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("ToJSONDocumentOfContentType() error = %v", err)
+			}
+
+			if gotFormat != tt.wantFormat {
+				t.Errorf("ToJSONDocumentOfContentType() format = %s, want %s", gotFormat, tt.wantFormat)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("ToJSONDocumentOfContentType() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONSchemaRawValidator_Validate_paths(t *testing.T) {
+	schema := `{"type": "object", "properties": {"items": {"type": "array", "items": {"type": "object", "required": ["price"]}}}, "required": ["id"]}`
+
+	err := NewJSONSchemaRawValidator().Validate(`{"items": [{"price": 1}, {}]}`, schema)
+	want := "#: missing properties: 'id'\n\tkeyword: #/required\n\texpected: [\"id\"]\n\tactual: object\n" +
+		"#/items/1: missing properties: 'price'\n\tkeyword: #/properties/items/items/required\n\texpected: [\"price\"]\n\tactual: object"
+	if err == nil || err.Error() != want {
+		t.Errorf("Validate() error = %v, want %s", err, want)
+	}
+}
+
+func TestJSONSchemaRawValidator_Validate_unevaluatedKeywords(t *testing.T) {
+	schema := `{"$schema": "https://json-schema.org/draft/2020-12/schema", "properties": {"UnevaluatedProperties": {"prefixItems": [{"type": "integer"}], "unevaluatedItems": false}}, "unevaluatedProperties": false}`
+
+	err := NewJSONSchemaRawValidator().Validate(`{"UnevaluatedProperties": [1, "a"], "extra": 1}`, schema)
+	want := "#/UnevaluatedProperties/1: not allowed\n\tkeyword: #/properties/UnevaluatedProperties/unevaluatedItems\n\texpected: false\n\tactual: \"a\"\n" +
+		"#/extra: not allowed\n\tkeyword: #/unevaluatedProperties\n\texpected: false\n\tactual: 1"
+	if err == nil || err.Error() != want {
+		t.Errorf("Validate() error = %v, want %s", err, want)
+	}
+}
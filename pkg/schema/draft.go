@@ -0,0 +1,196 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// Draft is version of JSON schema specification.
+type Draft string
+
+const (
+	// Draft4 is JSON schema draft-04, identified by $schema http://json-schema.org/draft-04/schema#.
+	Draft4 Draft = "draft-04"
+
+	// Draft6 is JSON schema draft-06, identified by $schema http://json-schema.org/draft-06/schema#.
+	Draft6 Draft = "draft-06"
+
+	// Draft7 is JSON schema draft-07, identified by $schema http://json-schema.org/draft-07/schema#.
+	// It is used for schema without $schema keyword, which does not use constructs specific to other drafts.
+	Draft7 Draft = "draft-07"
+
+	// Draft2019 is JSON schema draft 2019-09, identified by $schema https://json-schema.org/draft/2019-09/schema.
+	Draft2019 Draft = "2019-09"
+
+	// Draft2020 is JSON schema draft 2020-12, identified by $schema https://json-schema.org/draft/2020-12/schema.
+	Draft2020 Draft = "2020-12"
+)
+
+// drafts maps supported drafts to their implementations.
+var drafts = map[Draft]*jsonschema.Draft{
+	Draft4:    jsonschema.Draft4,
+	Draft6:    jsonschema.Draft6,
+	Draft7:    jsonschema.Draft7,
+	Draft2019: jsonschema.Draft2019,
+	Draft2020: jsonschema.Draft2020,
+}
+
+// modernKeywords are keywords introduced in draft 2019-09 or 2020-12.
+var modernKeywords = map[string]bool{
+	"$defs":                 true,
+	"$anchor":               true,
+	"$dynamicRef":           true,
+	"$dynamicAnchor":        true,
+	"$recursiveRef":         true,
+	"$recursiveAnchor":      true,
+	"unevaluatedProperties": true,
+	"unevaluatedItems":      true,
+	"prefixItems":           true,
+	"dependentRequired":     true,
+	"dependentSchemas":      true,
+	"minContains":           true,
+	"maxContains":           true,
+}
+
+// dataKeywords are keywords holding instance data instead of subschemas.
+var dataKeywords = map[string]bool{
+	"enum":     true,
+	"const":    true,
+	"default":  true,
+	"examples": true,
+}
+
+// DetectDraft returns draft of JSON schema. Draft is taken from $schema keyword when it is present,
+// otherwise schema using keywords of newer drafts, like $defs or unevaluatedProperties, is treated as draft 2020-12,
+// schema using constructs removed after draft-04, like boolean exclusiveMinimum or id keyword, as draft-04
+// and any other schema as draft-07.
+func DetectDraft(schema []byte) (Draft, error) {
+	var doc interface{}
+	if err := json.Unmarshal(schema, &doc); err != nil {
+		return "", fmt.Errorf("invalid JSON schema, err: %w", err)
+	}
+
+	if m, ok := doc.(map[string]interface{}); ok {
+		if uri, ok := m["$schema"].(string); ok {
+			return draftOf(uri)
+		}
+	}
+
+	if usesModernKeywords(doc) {
+		return Draft2020, nil
+	}
+
+	if usesDraft4Constructs(doc) {
+		return Draft4, nil
+	}
+
+	return Draft7, nil
+}
+
+// withDraft adds $schema keyword of detected draft to schema without it, so every schema is compiled
+// according to its own draft, no matter which schema refers to it.
+func withDraft(schema []byte) ([]byte, error) {
+	draft, err := DetectDraft(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(schema))
+	decoder.UseNumber()
+
+	var doc interface{}
+	if err = decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema, err: %w", err)
+	}
+
+	m, ok := doc.(map[string]interface{})
+	if !ok {
+		return schema, nil
+	}
+
+	if _, ok = m["$schema"]; ok {
+		return schema, nil
+	}
+
+	m["$schema"] = drafts[draft].URL()
+
+	return json.Marshal(m)
+}
+
+// draftOf returns draft identified by $schema uri, for example: https://json-schema.org/draft/2020-12/schema.
+func draftOf(uri string) (Draft, error) {
+	normalized := strings.TrimSuffix(strings.TrimSuffix(uri, "#"), "/")
+	normalized = strings.TrimPrefix(strings.TrimPrefix(normalized, "https://"), "http://")
+
+	switch normalized {
+	case "json-schema.org/draft-04/schema":
+		return Draft4, nil
+	case "json-schema.org/draft-06/schema":
+		return Draft6, nil
+	case "json-schema.org/draft-07/schema":
+		return Draft7, nil
+	case "json-schema.org/draft/2019-09/schema":
+		return Draft2019, nil
+	case "json-schema.org/draft/2020-12/schema", "json-schema.org/schema":
+		return Draft2020, nil
+	}
+
+	return "", fmt.Errorf("unsupported $schema: %s", uri)
+}
+
+func usesModernKeywords(v interface{}) bool {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for key, value := range t {
+			if modernKeywords[key] || usesModernKeywords(value) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, value := range t {
+			if usesModernKeywords(value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// usesDraft4Constructs checks whether schema uses constructs valid only in draft-04: boolean exclusiveMinimum
+// or exclusiveMaximum and id keyword, instead of $id.
+func usesDraft4Constructs(v interface{}) bool {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for key, value := range t {
+			switch key {
+			case "exclusiveMinimum", "exclusiveMaximum":
+				if _, ok := value.(bool); ok {
+					return true
+				}
+			case "id":
+				if _, ok := value.(string); ok {
+					if _, hasID := t["$id"]; !hasID {
+						return true
+					}
+				}
+			}
+
+			if !dataKeywords[key] && usesDraft4Constructs(value) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, value := range t {
+			if usesDraft4Constructs(value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,96 @@
+package schema
+
+import (
+	"testing"
+)
+
+func TestDetectDraft(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		want    Draft
+		wantErr bool
+	}{
+		{name: "draft-04", schema: `{"$schema": "http://json-schema.org/draft-04/schema#"}`, want: Draft4},
+		{name: "draft-07", schema: `{"$schema": "http://json-schema.org/draft-07/schema#", "$defs": {}}`, want: Draft7},
+		{name: "2019-09", schema: `{"$schema": "https://json-schema.org/draft/2019-09/schema"}`, want: Draft2019},
+		{name: "2020-12", schema: `{"$schema": "https://json-schema.org/draft/2020-12/schema"}`, want: Draft2020},
+		{name: "without $schema", schema: `{"type": "object", "properties": {"a": {"type": "string"}}}`, want: Draft7},
+		{name: "without $schema with nested $defs", schema: `{"allOf": [{"$defs": {"a": {}}}]}`, want: Draft2020},
+		{name: "without $schema with unevaluatedProperties", schema: `{"unevaluatedProperties": false}`, want: Draft2020},
+		{name: "without $schema with boolean exclusiveMinimum", schema: `{"minimum": 5, "exclusiveMinimum": true}`, want: Draft4},
+		{name: "without $schema with nested boolean exclusiveMaximum", schema: `{"properties": {"a": {"maximum": 5, "exclusiveMaximum": false}}}`, want: Draft4},
+		{name: "without $schema with id", schema: `{"id": "http://example.com/a.json", "type": "object"}`, want: Draft4},
+		{name: "without $schema with numeric exclusiveMinimum", schema: `{"exclusiveMinimum": 5}`, want: Draft7},
+		{name: "without $schema with id property", schema: `{"properties": {"id": {"type": "string"}}, "required": ["id"]}`, want: Draft7},
+		{name: "without $schema with id in enum", schema: `{"enum": [{"id": "a"}], "default": {"exclusiveMinimum": true}}`, want: Draft7},
+		{name: "boolean schema", schema: `true`, want: Draft7},
+		{name: "unsupported $schema", schema: `{"$schema": "https://example.com/schema"}`, wantErr: true},
+		{name: "invalid JSON", schema: `{`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DetectDraft([]byte(tt.schema))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DetectDraft() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("DetectDraft() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONSchemaRawValidator_Validate_drafts(t *testing.T) {
+	schema := `{
+  "$defs": {
+    "name": {"type": "string", "minLength": 1}
+  },
+  "type": "object",
+  "properties": {"name": {"$ref": "#/$defs/name"}},
+  "allOf": [{"properties": {"age": {"type": "integer"}}}],
+  "unevaluatedProperties": false
+}`
+
+	tests := []struct {
+		name     string
+		document string
+		want     string
+	}{
+		{name: "valid document", document: `{"name": "ivo", "age": 30}`},
+		{name: "unevaluated property", document: `{"name": "ivo", "age": 30, "extra": true}`,
+			want: "#/extra: not allowed\n\tkeyword: #/unevaluatedProperties\n\texpected: false\n\tactual: true"},
+		{name: "invalid $defs reference", document: `{"name": ""}`, want: "#/name: length must be >= 1, but got 0\n\tkeyword: #/properties/name/$ref/minLength\n\texpected: 1\n\tactual: \"\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewJSONSchemaRawValidator().Validate(tt.document, schema)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Validate() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONSchemaRawValidator_Validate_draft4WithoutSchema(t *testing.T) {
+	schema := `{"minimum": 5, "exclusiveMinimum": true}`
+
+	if err := NewJSONSchemaRawValidator().Validate(`6`, schema); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	if err := NewJSONSchemaRawValidator().Validate(`5`, schema); err == nil {
+		t.Errorf("Validate() should reject value equal to exclusive minimum of draft-04 schema")
+	}
+}
@@ -0,0 +1,92 @@
+package schema
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// FormatChecker reports whether JSON value satisfies custom format. Value is one of: string, json.Number, bool,
+// nil, []interface{} or map[string]interface{}.
+type FormatChecker func(value interface{}) bool
+
+// FormatRegisterer describes entity that accepts custom formats of JSON schema "format" keyword.
+type FormatRegisterer interface {
+	// RegisterFormat registers checker of format with given name, for example: iban.
+	RegisterFormat(name string, checker FormatChecker) error
+}
+
+// FormatRegistry holds custom formats of JSON schema "format" keyword. It is safe for concurrent use.
+type FormatRegistry struct {
+	mu       sync.RWMutex
+	checkers map[string]FormatChecker
+}
+
+// NewFormatRegistry returns *FormatRegistry without any custom formats.
+func NewFormatRegistry() *FormatRegistry {
+	return &FormatRegistry{checkers: map[string]FormatChecker{}}
+}
+
+// Register registers checker of format with given name. Checker of already registered format is replaced.
+func (r *FormatRegistry) Register(name string, checker FormatChecker) error {
+	if r == nil {
+		return errors.New("format registry is not initialized, use validator constructor")
+	}
+
+	if name == "" {
+		return errors.New("format name should not be empty string")
+	}
+
+	if checker == nil {
+		return errors.New("format checker should not be nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkers[name] = checker
+
+	return nil
+}
+
+// checker returns checker of format with given name.
+func (r *FormatRegistry) checker(name string) (FormatChecker, bool) {
+	if r == nil {
+		return nil, false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	checker, ok := r.checkers[name]
+
+	return checker, ok
+}
+
+// formatCompiler is extension of JSON schema compiler asserting "format" keyword with custom formats.
+type formatCompiler struct {
+	formats *FormatRegistry
+}
+
+func (fc formatCompiler) Compile(_ jsonschema.CompilerContext, m map[string]interface{}) (jsonschema.ExtSchema, error) {
+	name, ok := m["format"].(string)
+	if !ok || fc.formats == nil {
+		return nil, nil
+	}
+
+	return formatSchema{name: name, formats: fc.formats}, nil
+}
+
+// formatSchema looks up checker during validation, so formats registered after schema compilation apply as well.
+type formatSchema struct {
+	name    string
+	formats *FormatRegistry
+}
+
+func (fs formatSchema) Validate(ctx jsonschema.ValidationContext, v interface{}) error {
+	checker, ok := fs.formats.checker(fs.name)
+	if !ok || checker(v) {
+		return nil
+	}
+
+	return ctx.Error("format", "'%v' is not valid '%s'", v, fs.name)
+}
@@ -0,0 +1,75 @@
+package schema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFormatRegistry_Register(t *testing.T) {
+	isIBAN := func(v interface{}) bool {
+		s, ok := v.(string)
+		return !ok || strings.HasPrefix(s, "PL") && len(s) == 28
+	}
+
+	var nilRegistry *FormatRegistry
+	if err := nilRegistry.Register("iban", isIBAN); err == nil {
+		t.Errorf("Register() should return error for uninitialized registry")
+	}
+
+	r := NewFormatRegistry()
+	if err := r.Register("", isIBAN); err == nil {
+		t.Errorf("Register() should return error for empty name")
+	}
+
+	if err := r.Register("iban", nil); err == nil {
+		t.Errorf("Register() should return error for nil checker")
+	}
+
+	if err := r.Register("iban", isIBAN); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	if _, ok := r.checker("iban"); !ok {
+		t.Errorf("checker() should return registered checker")
+	}
+}
+
+func TestJSONSchemaRawValidator_RegisterFormat(t *testing.T) {
+	schema := `{"type": "object", "properties": {"account": {"type": "string", "format": "iban"}, "email": {"format": "email"}}}`
+	v := NewJSONSchemaRawValidator()
+
+	if err := v.Validate(`{"account": "abc"}`, schema); err != nil {
+		t.Errorf("Validate() with unknown format error = %v", err)
+	}
+
+	err := v.RegisterFormat("iban", func(value interface{}) bool {
+		s, ok := value.(string)
+		return !ok || strings.HasPrefix(s, "PL") && len(s) == 28
+	})
+	if err != nil {
+		t.Fatalf("RegisterFormat() error = %v", err)
+	}
+
+	if err = v.Validate(`{"account": "PL61109010140000071219812874"}`, schema); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	err = v.Validate(`{"account": "abc", "email": "ivo"}`, schema)
+	var ve *ValidationError
+	if !errors.As(err, &ve) || len(ve.Violations) != 2 {
+		t.Fatalf("Validate() error = %v, want 2 violations", err)
+	}
+
+	if got := ve.Violations[0]; got.Message != "'abc' is not valid 'iban'" || got.Expected != `"iban"` {
+		t.Errorf("Validate() violation = %+v, want violation of iban format", got)
+	}
+
+	if got := ve.Violations[1]; got.Message != "'ivo' is not valid 'email'" || got.InstancePath != "/email" {
+		t.Errorf("Validate() violation = %+v, want violation of email format", got)
+	}
+
+	if err = (JSONSchemaRawValidator{}).RegisterFormat("iban", func(interface{}) bool { return true }); err == nil {
+		t.Errorf("RegisterFormat() should return error for validator created without constructor")
+	}
+}
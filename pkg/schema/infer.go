@@ -0,0 +1,233 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pawelWritesCode/gdutils/pkg/format"
+)
+
+const (
+	// maxEnumValues is maximal number of distinct strings described by inferred enum.
+	maxEnumValues = 5
+
+	// minEnumOccurrences is minimal number of occurrences of every string described by inferred enum.
+	minEnumOccurrences = 3
+)
+
+var (
+	uuidRegexp  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	emailRegexp = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s.]+$`)
+)
+
+// Infer returns JSON schema, draft 2020-12, describing every document. Documents may be in JSON, YAML or XML format,
+// they are converted to JSON with ToJSONDocument first. Schema describes:
+//   - types, integer values become number when they are mixed with floats,
+//   - properties of objects, properties present in every object are required,
+//   - items of arrays, inferred from all elements,
+//   - enum of strings, when from 2 to 5 distinct values occurred, each at least 3 times,
+//   - format of strings, when every value is date-time, uuid or email.
+func Infer(documents ...[]byte) ([]byte, error) {
+	if len(documents) == 0 {
+		return nil, errors.New("at least one document is required to infer JSON schema")
+	}
+
+	root := &shape{}
+	for i, document := range documents {
+		dataFormat, data, err := ToJSONDocument(document)
+		if err != nil {
+			return nil, fmt.Errorf("document %d could not be converted to JSON, err: %w", i+1, err)
+		}
+
+		if dataFormat == format.PlainText {
+			return nil, fmt.Errorf("document %d is not in JSON, YAML nor XML format", i+1)
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+
+		var value interface{}
+		if err = decoder.Decode(&value); err != nil {
+			return nil, fmt.Errorf("document %d is not valid JSON, err: %w", i+1, err)
+		}
+
+		root.add(value)
+	}
+
+	schema := root.schema()
+	schema["$schema"] = drafts[Draft2020].URL()
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// shape accumulates values found at the same place of documents.
+type shape struct {
+	count int
+	types map[string]bool
+
+	// strings counts occurrences of distinct strings, at most maxEnumValues+1 of them,
+	// and stringCount is number of strings.
+	strings     map[string]int
+	stringCount int
+
+	// notDateTime, notUUID and notEmail tell whether any string does not have given format.
+	notDateTime bool
+	notUUID     bool
+	notEmail    bool
+
+	objects    int
+	properties map[string]*shape
+
+	items *shape
+}
+
+func (s *shape) add(value interface{}) {
+	if s.types == nil {
+		s.types = map[string]bool{}
+	}
+
+	s.count++
+	switch v := value.(type) {
+	case nil:
+		s.types["null"] = true
+	case bool:
+		s.types["boolean"] = true
+	case json.Number:
+		if strings.ContainsAny(v.String(), ".eE") {
+			s.types["number"] = true
+		} else {
+			s.types["integer"] = true
+		}
+	case string:
+		s.types["string"] = true
+		s.addString(v)
+	case []interface{}:
+		s.types["array"] = true
+		if s.items == nil {
+			s.items = &shape{}
+		}
+
+		for _, item := range v {
+			s.items.add(item)
+		}
+	case map[string]interface{}:
+		s.types["object"] = true
+		s.objects++
+		if s.properties == nil {
+			s.properties = map[string]*shape{}
+		}
+
+		for key, property := range v {
+			if s.properties[key] == nil {
+				s.properties[key] = &shape{}
+			}
+
+			s.properties[key].add(property)
+		}
+	}
+}
+
+func (s *shape) addString(value string) {
+	s.stringCount++
+	if s.strings == nil {
+		s.strings = map[string]int{}
+	}
+
+	if _, ok := s.strings[value]; ok || len(s.strings) <= maxEnumValues {
+		s.strings[value]++
+	}
+
+	if _, err := time.Parse(time.RFC3339, value); err != nil {
+		s.notDateTime = true
+	}
+
+	s.notUUID = s.notUUID || !uuidRegexp.MatchString(value)
+	s.notEmail = s.notEmail || !emailRegexp.MatchString(value)
+}
+
+// schema returns JSON schema describing accumulated values.
+func (s *shape) schema() map[string]interface{} {
+	schema := map[string]interface{}{}
+	if len(s.types) == 0 {
+		return schema
+	}
+
+	if s.types["number"] {
+		delete(s.types, "integer")
+	}
+
+	types := make([]string, 0, len(s.types))
+	for t := range s.types {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	if len(types) == 1 {
+		schema["type"] = types[0]
+	} else {
+		schema["type"] = types
+	}
+
+	if s.stringCount > 0 {
+		switch {
+		case !s.notDateTime:
+			schema["format"] = "date-time"
+		case !s.notUUID:
+			schema["format"] = "uuid"
+		case !s.notEmail:
+			schema["format"] = "email"
+		case s.isEnum() && len(types) == 1:
+			enum := make([]string, 0, len(s.strings))
+			for value := range s.strings {
+				enum = append(enum, value)
+			}
+			sort.Strings(enum)
+			schema["enum"] = enum
+		}
+	}
+
+	if s.objects > 0 {
+		properties := map[string]interface{}{}
+		required := []string{}
+		for key, property := range s.properties {
+			properties[key] = property.schema()
+			if property.count == s.objects {
+				required = append(required, key)
+			}
+		}
+		sort.Strings(required)
+
+		schema["properties"] = properties
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+	}
+
+	if s.items != nil && s.items.count > 0 {
+		schema["items"] = s.items.schema()
+	}
+
+	return schema
+}
+
+// isEnum checks whether strings should be described by enum: more than one distinct string occurred,
+// but not more than maxEnumValues, and each of them at least minEnumOccurrences times.
+func (s *shape) isEnum() bool {
+	if len(s.strings) < 2 || len(s.strings) > maxEnumValues {
+		return false
+	}
+
+	for _, count := range s.strings {
+		if count < minEnumOccurrences {
+			return false
+		}
+	}
+
+	return true
+}
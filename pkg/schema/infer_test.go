@@ -0,0 +1,138 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInfer(t *testing.T) {
+	first := `{
+	"id": "9b2f7c1e-2a1d-4c3b-9f8e-1a2b3c4d5e6f",
+	"email": "ivo@example.com",
+	"created": "2021-05-01T10:00:00Z",
+	"score": 1,
+	"tags": [],
+	"items": [{"status": "new", "price": 1}, {"status": "paid", "price": 2.5, "note": null}]
+}`
+	second := "id: 0d6f2a5b-8c4e-4b1a-a3f2-7e9d8c6b5a41\nemail: eve@example.org\ncreated: 2021-06-01T12:30:00+02:00\nscore: 2\n" +
+		"tags: [a]\nitems:\n  - status: new\n    price: 3\n  - status: paid\n    price: 4\n    note: fragile\n"
+
+	got, err := Infer([]byte(first), []byte(second))
+	if err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+
+	want := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "properties": {
+    "created": {
+      "format": "date-time",
+      "type": "string"
+    },
+    "email": {
+      "format": "email",
+      "type": "string"
+    },
+    "id": {
+      "format": "uuid",
+      "type": "string"
+    },
+    "items": {
+      "items": {
+        "properties": {
+          "note": {
+            "type": [
+              "null",
+              "string"
+            ]
+          },
+          "price": {
+            "type": "number"
+          },
+          "status": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "price",
+          "status"
+        ],
+        "type": "object"
+      },
+      "type": "array"
+    },
+    "score": {
+      "type": "integer"
+    },
+    "tags": {
+      "items": {
+        "type": "string"
+      },
+      "type": "array"
+    }
+  },
+  "required": [
+    "created",
+    "email",
+    "id",
+    "items",
+    "score",
+    "tags"
+  ],
+  "type": "object"
+}`
+	if string(got) != want {
+		t.Errorf("Infer() =\n%s\nwant\n%s", got, want)
+	}
+
+	for _, document := range []string{first, second} {
+		if err = NewJSONSchemaRawValidator().Validate(toJSON(t, document), string(got)); err != nil {
+			t.Errorf("inferred schema does not describe document, err: %v", err)
+		}
+	}
+
+	if _, err = Infer(); err == nil {
+		t.Errorf("Infer() should return error without documents")
+	}
+
+	if _, err = Infer([]byte(`abc`)); err == nil {
+		t.Errorf("Infer() should return error for plain text document")
+	}
+}
+
+func TestInfer_enum(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []string
+		wantEnum bool
+	}{
+		{name: "each of few values occurred often", statuses: []string{"new", "paid", "new", "paid", "sent", "new", "paid", "sent", "sent"}, wantEnum: true},
+		{name: "single value", statuses: []string{"new", "new", "new", "new"}},
+		{name: "value occurred too rarely", statuses: []string{"new", "new", "new", "paid", "paid", "paid", "sent"}},
+		{name: "too many values", statuses: []string{"a", "b", "c", "d", "e", "f", "a", "b", "c", "d", "e", "f", "a", "b", "c", "d", "e", "f"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document := `[{"status": "` + strings.Join(tt.statuses, `"}, {"status": "`) + `"}]`
+
+			got, err := Infer([]byte(document))
+			if err != nil {
+				t.Fatalf("Infer() error = %v", err)
+			}
+
+			if gotEnum := strings.Contains(string(got), `"enum"`); gotEnum != tt.wantEnum {
+				t.Errorf("Infer() =\n%s\nshould describe enum: %t", got, tt.wantEnum)
+			}
+		})
+	}
+}
+
+func toJSON(t *testing.T, document string) string {
+	_, data, err := ToJSONDocument([]byte(document))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
@@ -0,0 +1,328 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// URLMapper describes entity that loads JSON schemas of remote URLs from local files.
+type URLMapper interface {
+	// MapURL makes schemas under remote URL prefix load from local file or directory.
+	MapURL(prefix, localPath string) error
+}
+
+// Registry holds JSON schemas compiled once. On first use it preloads every JSON schema from its directory,
+// so $ref between them, by relative path or by $id, is resolved without network access. Remote URLs may be
+// mapped to local files with MapURL. Registry is safe for concurrent use.
+type Registry struct {
+	// dir represents path to JSON schemas directory, it may be empty string.
+	dir string
+
+	// formats holds custom formats of "format" keyword.
+	formats *FormatRegistry
+
+	mu       sync.Mutex
+	mappings map[string]string
+	loaded   bool
+	compiler *jsonschema.Compiler
+
+	// schemas holds compiled schemas and errs holds errors of preloaded schemas, both by URL.
+	schemas map[string]*jsonschema.Schema
+	errs    map[string]error
+
+	// docs holds loaded schemas, to describe violations.
+	docs documents
+}
+
+// registries holds registries shared by validators, by absolute path to JSON schemas directory.
+var registries = struct {
+	sync.Mutex
+	byDir map[string]*Registry
+}{byDir: map[string]*Registry{}}
+
+// NewRegistry returns registry of JSON schemas from dir, asserting custom formats held by formats.
+// Returned registry is not shared with other validators, see SharedRegistry.
+func NewRegistry(dir string, formats *FormatRegistry) *Registry {
+	return &Registry{dir: dir, formats: formats, mappings: map[string]string{}}
+}
+
+// SharedRegistry returns registry of JSON schemas from dir, shared by every caller within process, so each schema
+// is compiled once, no matter how many validators, for example one per scenario, use it. Its custom formats
+// and URL mappings are shared as well.
+func SharedRegistry(dir string) *Registry {
+	key := dir
+	if abs, err := filepath.Abs(dir); dir != "" && err == nil {
+		key = abs
+	}
+
+	registries.Lock()
+	defer registries.Unlock()
+
+	r, ok := registries.byDir[key]
+	if !ok {
+		r = NewRegistry(dir, NewFormatRegistry())
+		registries.byDir[key] = r
+	}
+
+	return r
+}
+
+// MapURL makes schemas under remote URL prefix load from local file or directory, for example:
+// MapURL("https://example.com/schemas/", "remote") loads https://example.com/schemas/user.json from remote/user.json.
+// Relative localPath is relative to registry directory. Schemas compiled so far are dropped,
+// unless prefix is already mapped to the same localPath.
+func (r *Registry) MapURL(prefix, localPath string) error {
+	if prefix == "" {
+		return fmt.Errorf("URL prefix should not be empty string")
+	}
+
+	if !filepath.IsAbs(localPath) {
+		localPath = filepath.Join(r.dir, localPath)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if mapped, ok := r.mappings[prefix]; ok && mapped == localPath {
+		return nil
+	}
+
+	r.mappings[prefix] = localPath
+	r.loaded = false
+
+	return nil
+}
+
+// Load preloads and compiles every JSON schema from registry directory, unless it has been done already.
+// It returns error describing every file that could not be compiled.
+func (r *Registry) Load() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.load()
+
+	urls := make([]string, 0, len(r.errs))
+	for url := range r.errs {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+
+	lines := make([]string, 0, len(urls))
+	for _, url := range urls {
+		lines = append(lines, fmt.Sprintf("%s: %v", url, r.errs[url]))
+	}
+
+	if len(lines) > 0 {
+		return fmt.Errorf("could not compile JSON schemas:\n%s", strings.Join(lines, "\n"))
+	}
+
+	return nil
+}
+
+// Validate validates document against schema available under url, for example: file:///schemas/user.json
+// or $id of preloaded schema. Schema is compiled at most once.
+func (r *Registry) Validate(document, url string) error {
+	compiled, err := r.schema(url)
+	if err != nil {
+		return err
+	}
+
+	return validateDocument(compiled, document, r.keyword)
+}
+
+// keyword returns value of keyword at absolute location within loaded schemas.
+func (r *Registry) keyword(location string) (interface{}, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.docs.keyword(location)
+}
+
+// schema returns compiled schema available under url. Schemas not found in registry directory are compiled
+// on first use and cached as well.
+func (r *Registry) schema(url string) (*jsonschema.Schema, error) {
+	url = normalizeURL(url)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.load()
+
+	if compiled, ok := r.schemas[url]; ok {
+		return compiled, nil
+	}
+
+	if err, ok := r.errs[url]; ok {
+		return nil, err
+	}
+
+	compiled, err := r.compiler.Compile(url)
+	if err != nil {
+		return nil, fmt.Errorf("could not compile JSON schema, err: %w", err)
+	}
+
+	r.schemas[url] = compiled
+
+	return compiled, nil
+}
+
+// load adds every JSON file from registry directory to compiler under its file URL and $id, then compiles them.
+// It should be called with mu locked.
+func (r *Registry) load() {
+	if r.loaded {
+		return
+	}
+
+	r.compiler = newCompiler(r.formats)
+	r.compiler.LoadURL = r.loadURL
+	r.schemas = map[string]*jsonschema.Schema{}
+	r.errs = map[string]error{}
+	r.docs = documents{}
+	r.loaded = true
+
+	if r.dir == "" {
+		return
+	}
+
+	var urls []string
+	_ = filepath.WalkDir(r.dir, func(pth string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(pth) != ".json" {
+			return nil
+		}
+
+		url := normalizeURL("file://" + pth)
+		data, err := r.readFile(pth)
+		if err != nil {
+			r.errs[url] = err
+			return nil
+		}
+
+		if err = r.compiler.AddResource(url, bytes.NewReader(data)); err != nil {
+			r.errs[url] = err
+			return nil
+		}
+
+		r.docs.add(url, data)
+
+		if id := schemaID(data); id != "" && id != url {
+			if err = r.compiler.AddResource(id, bytes.NewReader(data)); err != nil {
+				r.errs[url] = err
+				return nil
+			}
+
+			urls = append(urls, id)
+		}
+
+		urls = append(urls, url)
+
+		return nil
+	})
+
+	for _, url := range urls {
+		compiled, err := r.compiler.Compile(url)
+		if err != nil {
+			r.errs[url] = fmt.Errorf("could not compile JSON schema, err: %w", err)
+			continue
+		}
+
+		r.schemas[url] = compiled
+	}
+}
+
+// loadURL loads schemas not found in registry directory, from local file when URL is mapped with MapURL.
+func (r *Registry) loadURL(url string) (io.ReadCloser, error) {
+	prefix := ""
+	for p := range r.mappings {
+		if strings.HasPrefix(url, p) && len(p) > len(prefix) {
+			prefix = p
+		}
+	}
+
+	var data []byte
+	var err error
+	if prefix != "" {
+		data, err = r.readFile(filepath.Join(r.mappings[prefix], filepath.FromSlash(strings.TrimPrefix(url, prefix))))
+	} else {
+		data, err = r.readURL(url)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.docs.add(url, data)
+
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (r *Registry) readURL(url string) ([]byte, error) {
+	reader, err := jsonschema.LoadURL(url)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return withDraft(data)
+}
+
+func (r *Registry) readFile(pth string) ([]byte, error) {
+	data, err := ioutil.ReadFile(pth)
+	if err != nil {
+		return nil, err
+	}
+
+	return withDraft(data)
+}
+
+// newCompiler returns JSON schema compiler asserting "format" keyword, custom formats included.
+func newCompiler(formats *FormatRegistry) *jsonschema.Compiler {
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat = true
+	compiler.RegisterExtension("formats", nil, formatCompiler{formats: formats})
+
+	return compiler
+}
+
+// schemaID returns absolute $id of schema, without empty fragment, or empty string.
+func schemaID(schema []byte) string {
+	var doc struct {
+		ID string `json:"$id"`
+	}
+
+	if err := json.Unmarshal(schema, &doc); err != nil {
+		return ""
+	}
+
+	id := strings.TrimSuffix(doc.ID, "#")
+	if !strings.Contains(id, "://") || strings.Contains(id, "#") {
+		return ""
+	}
+
+	return id
+}
+
+// normalizeURL makes file URLs absolute and clean, so the same file is always cached under the same URL.
+func normalizeURL(url string) string {
+	if !strings.HasPrefix(url, "file://") {
+		return url
+	}
+
+	pth, err := filepath.Abs(filepath.FromSlash(strings.TrimPrefix(url, "file://")))
+	if err != nil {
+		return url
+	}
+
+	return "file://" + filepath.ToSlash(pth)
+}
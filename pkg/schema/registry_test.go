@@ -0,0 +1,218 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRegistry_Validate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"user.json": `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"$id": "https://example.com/schemas/user.json",
+	"type": "object",
+	"properties": {
+		"address": {"$ref": "common/address.json"},
+		"country": {"$ref": "https://remote.example.org/schemas/country.json"}
+	},
+	"required": ["address"],
+	"unevaluatedProperties": false
+}`,
+		"common/address.json": `{
+	"$id": "https://example.com/schemas/common/address.json",
+	"type": "object",
+	"properties": {"city": {"$ref": "#/$defs/city"}},
+	"required": ["city"],
+	"$defs": {"city": {"type": "string", "minLength": 1}}
+}`,
+		"remote/country.json": `{"enum": ["PL", "DE"]}`,
+		"invalid.json":        `{"type": 1}`,
+		"notes.txt":           `not a schema`,
+	}
+	for name, content := range files {
+		pth := filepath.Join(dir, filepath.FromSlash(name))
+		if err = os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = ioutil.WriteFile(pth, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := NewRegistry(dir, NewFormatRegistry())
+	if err = r.MapURL("https://remote.example.org/schemas/", "remote"); err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.Load()
+	if err == nil || !strings.Contains(err.Error(), "invalid.json") || strings.Contains(err.Error(), "user.json") {
+		t.Errorf("Load() error = %v, want error of invalid.json only", err)
+	}
+
+	tests := []struct {
+		name     string
+		document string
+		url      string
+		want     string
+	}{
+		{name: "valid document by file URL", document: `{"address": {"city": "Berlin"}, "country": "DE"}`,
+			url: "file://" + filepath.ToSlash(filepath.Join(dir, "user.json"))},
+		{name: "valid document by $id", document: `{"address": {"city": "Berlin"}}`,
+			url: "https://example.com/schemas/user.json"},
+		{name: "invalid document", document: `{"address": {"city": ""}, "country": "XX", "extra": 1}`,
+			url: "https://example.com/schemas/user.json",
+			want: "#/address/city: length must be >= 1, but got 0\n\tkeyword: #/properties/address/$ref/properties/city/$ref/minLength\n\texpected: 1\n\tactual: \"\"\n" +
+				"#/country: value must be one of \"PL\", \"DE\"\n\tkeyword: #/properties/country/$ref/enum\n\texpected: [\"PL\",\"DE\"]\n\tactual: \"XX\"\n" +
+				"#/extra: not allowed\n\tkeyword: #/unevaluatedProperties\n\texpected: false\n\tactual: 1"},
+		{name: "invalid schema", document: `{}`, url: "file://" + filepath.ToSlash(filepath.Join(dir, "invalid.json")),
+			want: "could not compile JSON schema"},
+		{name: "not existing schema", document: `{}`, url: "file://" + filepath.ToSlash(filepath.Join(dir, "order.json")),
+			want: "could not compile JSON schema"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.Validate(tt.document, tt.url)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistry_schema_cache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err = ioutil.WriteFile(filepath.Join(dir, "id.json"), []byte(`{"type": "integer"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRegistry(dir, NewFormatRegistry())
+	url := "file://" + filepath.ToSlash(filepath.Join(dir, ".", "id.json"))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- r.Validate(`1`, url)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Validate() error = %v", err)
+		}
+	}
+
+	first, err := r.schema(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, _ := r.schema("file://" + filepath.ToSlash(filepath.Join(dir, "id.json")))
+	if first != second {
+		t.Errorf("schema() should return the same compiled schema for the same file")
+	}
+
+	if err = ioutil.WriteFile(filepath.Join(dir, "id.json"), []byte(`{"type": "string"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = r.Validate(`1`, url); err != nil {
+		t.Errorf("Validate() should use compiled schema instead of reloading file, error = %v", err)
+	}
+}
+
+func TestSharedRegistry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err = ioutil.WriteFile(filepath.Join(dir, "id.json"), []byte(`{"type": "integer", "format": "even"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if SharedRegistry(dir) != SharedRegistry(filepath.Join(dir, ".")) {
+		t.Fatalf("SharedRegistry() should return the same registry for the same directory")
+	}
+
+	if SharedRegistry(dir) == SharedRegistry(filepath.Join(dir, "other")) {
+		t.Fatalf("SharedRegistry() should return different registries for different directories")
+	}
+
+	even := func(value interface{}) bool {
+		return strings.HasSuffix(fmt.Sprint(value), "0") || strings.HasSuffix(fmt.Sprint(value), "2")
+	}
+
+	// every scenario creates its own validator
+	var wg sync.WaitGroup
+	errs := make(chan error, 40)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v := NewDefaultJSONSchemaReferenceValidator(dir)
+			if err := v.RegisterFormat("even", even); err != nil {
+				errs <- err
+				return
+			}
+
+			if err := v.MapURL("https://remote.example.org/schemas/", "remote"); err != nil {
+				errs <- err
+				return
+			}
+
+			errs <- v.Validate(`2`, "id.json")
+			if err := v.Validate(`1`, "id.json"); err == nil {
+				errs <- errors.New("Validate() should reject value of invalid custom format")
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Validate() error = %v", err)
+		}
+	}
+
+	if err = ioutil.WriteFile(filepath.Join(dir, "id.json"), []byte(`{"type": "string"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = NewDefaultJSONSchemaReferenceValidator(dir).Validate(`2`, "id.json"); err != nil {
+		t.Errorf("Validate() of new validator should use schema compiled by other validators, error = %v", err)
+	}
+}
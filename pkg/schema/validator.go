@@ -1,17 +1,25 @@
 package schema
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"path"
+	"strings"
 
-	jschema "github.com/xeipuuv/gojsonschema"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	_ "github.com/santhosh-tekuri/jsonschema/v5/httploader"
 
 	"github.com/pawelWritesCode/gdutils/pkg/httpctx"
 	"github.com/pawelWritesCode/gdutils/pkg/osutils"
 	v "github.com/pawelWritesCode/gdutils/pkg/validator"
 )
 
+// rawSchemaURL is base URL of JSON schemas passed as string.
+const rawSchemaURL = "memory:///schema.json"
+
 // JSONSchemaReferenceValidator is entity that has ability to validate data against JSON schema passed as reference.
 type JSONSchemaReferenceValidator struct {
 	fileValidator v.Validator
@@ -19,25 +27,43 @@ type JSONSchemaReferenceValidator struct {
 
 	// schemasDir represents absolute path to JSON schemas directory.
 	schemasDir string
+
+	// formats holds custom formats of "format" keyword.
+	formats *FormatRegistry
+
+	// registry holds schemas compiled once and shared by validators of the same JSON schemas directory.
+	registry *Registry
 }
 
 // JSONSchemaRawValidator is entity that has ability to validate data against JSON schema passed as string
-type JSONSchemaRawValidator struct{}
+type JSONSchemaRawValidator struct {
+	// formats holds custom formats of "format" keyword.
+	formats *FormatRegistry
+}
 
 func NewDefaultJSONSchemaReferenceValidator(schemasDir string) JSONSchemaReferenceValidator {
 	return NewJSONSchemaReferenceValidator(schemasDir, osutils.NewFileValidator(), httpctx.NewURLValidator())
 }
 
+// NewJSONSchemaReferenceValidator returns validator using registry shared by every validator of schemasDir,
+// see SharedRegistry. Custom formats and URL mappings are shared between them as well.
 func NewJSONSchemaReferenceValidator(schemasDir string, fileValidator v.Validator, urlValidator v.Validator) JSONSchemaReferenceValidator {
+	return NewJSONSchemaReferenceValidatorWithRegistry(schemasDir, fileValidator, urlValidator, SharedRegistry(schemasDir))
+}
+
+// NewJSONSchemaReferenceValidatorWithRegistry returns validator using provided registry of JSON schemas.
+func NewJSONSchemaReferenceValidatorWithRegistry(schemasDir string, fileValidator v.Validator, urlValidator v.Validator, registry *Registry) JSONSchemaReferenceValidator {
 	return JSONSchemaReferenceValidator{
 		fileValidator: fileValidator,
 		urlValidator:  urlValidator,
 		schemasDir:    schemasDir,
+		formats:       registry.formats,
+		registry:      registry,
 	}
 }
 
 func NewJSONSchemaRawValidator() JSONSchemaRawValidator {
-	return JSONSchemaRawValidator{}
+	return JSONSchemaRawValidator{formats: NewFormatRegistry()}
 }
 
 // Validate validates document against JSON schema located in schemaPath.
@@ -48,21 +74,26 @@ func (jsv JSONSchemaReferenceValidator) Validate(document, schemaPath string) er
 		return err
 	}
 
-	result, err := jschema.Validate(jschema.NewReferenceLoader(source), jschema.NewStringLoader(document))
-	if err != nil {
-		return err
+	registry := jsv.registry
+	if registry == nil {
+		registry = NewRegistry(jsv.schemasDir, jsv.formats)
 	}
 
-	if !result.Valid() {
-		errSum := ""
-		for _, err := range result.Errors() {
-			errSum += err.String()
-		}
+	return registry.Validate(document, source)
+}
 
-		return errors.New(errSum)
+// MapURL makes schemas under remote URL prefix load from local file or directory, see Registry.MapURL.
+func (jsv JSONSchemaReferenceValidator) MapURL(prefix, localPath string) error {
+	if jsv.registry == nil {
+		return errors.New("schema registry is not initialized, use validator constructor")
 	}
 
-	return nil
+	return jsv.registry.MapURL(prefix, localPath)
+}
+
+// RegisterFormat registers checker of custom format, for example: iban. Format is shared by validators of the same registry.
+func (jsv JSONSchemaReferenceValidator) RegisterFormat(name string, checker FormatChecker) error {
+	return jsv.formats.Register(name, checker)
 }
 
 // getSource accepts rawSource, validate it and returns valid source
@@ -95,19 +126,51 @@ func (jsv JSONSchemaReferenceValidator) getSource(rawSource string) (string, err
 
 // Validate validates document against jsonSchema
 func (J JSONSchemaRawValidator) Validate(document, jsonSchema string) error {
-	result, err := jschema.Validate(jschema.NewStringLoader(jsonSchema), jschema.NewStringLoader(document))
+	return validate(document, []byte(jsonSchema), J.formats)
+}
+
+// RegisterFormat registers checker of custom format, for example: iban.
+func (J JSONSchemaRawValidator) RegisterFormat(name string, checker FormatChecker) error {
+	return J.formats.Register(name, checker)
+}
+
+// validate validates document against schema passed as string.
+func validate(document string, schema []byte, formats *FormatRegistry) error {
+	schema, err := withDraft(schema)
 	if err != nil {
 		return err
 	}
 
-	if !result.Valid() {
-		errSum := ""
-		for _, err := range result.Errors() {
-			errSum += err.String()
-		}
+	compiler := newCompiler(formats)
+	if err = compiler.AddResource(rawSchemaURL, bytes.NewReader(schema)); err != nil {
+		return fmt.Errorf("invalid JSON schema, err: %w", err)
+	}
+
+	compiled, err := compiler.Compile(rawSchemaURL)
+	if err != nil {
+		return fmt.Errorf("could not compile JSON schema, err: %w", err)
+	}
+
+	docs := documents{}
+	docs.add(rawSchemaURL, schema)
+
+	return validateDocument(compiled, document, docs.keyword)
+}
+
+// validateDocument validates JSON document against compiled schema. keyword returns value of keyword
+// at its absolute location, it is used to describe violations of schema.
+func validateDocument(compiled *jsonschema.Schema, document string, keyword func(location string) (interface{}, bool)) error {
+	decoder := json.NewDecoder(strings.NewReader(document))
+	decoder.UseNumber()
+
+	var instance interface{}
+	if err := decoder.Decode(&instance); err != nil {
+		return fmt.Errorf("invalid JSON document, err: %w", err)
+	}
 
-		return errors.New(errSum)
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return errors.New("invalid JSON document, err: unexpected data after top-level value")
 	}
 
-	return nil
+	return newValidationError(compiled.Validate(instance), instance, keyword)
 }
@@ -160,6 +160,18 @@ func TestJSONSchemaRawValidator_Validate(t *testing.T) {
 			document:   "",
 			jsonSchema: jsonSchema,
 		}, wantErr: true},
+		{name: "trailing data", args: args{
+			document:   document + " trailing",
+			jsonSchema: jsonSchema,
+		}, wantErr: true},
+		{name: "second document", args: args{
+			document:   document + document,
+			jsonSchema: jsonSchema,
+		}, wantErr: true},
+		{name: "trailing whitespace", args: args{
+			document:   document + "\n\t ",
+			jsonSchema: jsonSchema,
+		}, wantErr: false},
 		{name: "no json schema", args: args{
 			document:   document,
 			jsonSchema: ``,
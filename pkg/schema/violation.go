@@ -0,0 +1,241 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// excerptLength is maximal length of invalid node excerpt attached to violation.
+const excerptLength = 500
+
+// keywordNames maps names of compiled schema fields, which validator uses as last segment of some keyword locations,
+// to names of keywords.
+var keywordNames = map[string]string{
+	"UnevaluatedProperties": "unevaluatedProperties",
+	"UnevaluatedItems":      "unevaluatedItems",
+}
+
+// Violation describes single violation of JSON schema.
+type Violation struct {
+	// InstancePath is JSON pointer to invalid node, empty for document root, for example: /items/0/price.
+	InstancePath string
+
+	// KeywordPath is JSON pointer to failed keyword, followed through $ref, for example: /properties/items/items/properties/price/minimum.
+	KeywordPath string
+
+	// SchemaLocation is absolute location of failed keyword, for example: file:///schemas/order.json#/$defs/price/minimum.
+	SchemaLocation string
+
+	// Expected is failed constraint: value of failed keyword in JSON format, for example: 0.
+	// It is empty string when keyword could not be found.
+	Expected string
+
+	// Actual is invalid value in JSON format, objects and arrays are described by their type.
+	Actual string
+
+	// Message describes violation.
+	Message string
+
+	// Excerpt is invalid node in indented JSON format, truncated to 500 characters.
+	Excerpt string
+}
+
+// String returns violation in few lines, for example:
+//
+//	#/items/0/price: must be >= 0 but found -1
+//		keyword: #/properties/items/items/properties/price/minimum
+//		expected: 0
+//		actual: -1
+func (v Violation) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("#%s: %s\n\tkeyword: #%s", v.InstancePath, v.Message, v.KeywordPath))
+	if v.Expected != "" {
+		sb.WriteString("\n\texpected: " + v.Expected)
+	}
+
+	sb.WriteString("\n\tactual: " + v.Actual)
+
+	return sb.String()
+}
+
+// ValidationError is returned when document does not match JSON schema.
+type ValidationError struct {
+	// Violations are violations of schema sorted by InstancePath.
+	Violations []Violation
+}
+
+// Error returns report of every violation.
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		lines = append(lines, v.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// newValidationError converts error of schema validation into *ValidationError. Other errors are returned as they are.
+// keyword returns value of keyword at its absolute location.
+func newValidationError(err error, instance interface{}, keyword func(location string) (interface{}, bool)) error {
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) {
+		return err
+	}
+
+	var leaves []*jsonschema.ValidationError
+	var collect func(ve *jsonschema.ValidationError)
+	collect = func(ve *jsonschema.ValidationError) {
+		if len(ve.Causes) == 0 {
+			leaves = append(leaves, ve)
+			return
+		}
+
+		for _, cause := range ve.Causes {
+			collect(cause)
+		}
+	}
+	collect(ve)
+	sort.SliceStable(leaves, func(i, j int) bool {
+		return leaves[i].InstanceLocation < leaves[j].InstanceLocation
+	})
+
+	violations := make([]Violation, 0, len(leaves))
+	for _, leaf := range leaves {
+		v := Violation{
+			InstancePath:   leaf.InstanceLocation,
+			KeywordPath:    keywordPath(leaf.KeywordLocation),
+			SchemaLocation: leaf.AbsoluteKeywordLocation,
+			Message:        leaf.Message,
+		}
+
+		if expected, ok := keyword(leaf.AbsoluteKeywordLocation); ok {
+			v.Expected = compactJSON(expected)
+		}
+
+		if node, ok := resolvePointer(instance, leaf.InstanceLocation); ok {
+			v.Actual = describe(node)
+			v.Excerpt = excerpt(node)
+		}
+
+		violations = append(violations, v)
+	}
+
+	return &ValidationError{Violations: violations}
+}
+
+// keywordPath returns keyword location with its last segment named after keyword instead of field of compiled schema.
+// Other segments, for example names of properties, are left unchanged.
+func keywordPath(location string) string {
+	i := strings.LastIndexByte(location, '/')
+	if name, ok := keywordNames[location[i+1:]]; ok {
+		return location[:i+1] + name
+	}
+
+	return location
+}
+
+// documents holds decoded JSON schemas by their URLs and $ids, to find values of failed keywords.
+type documents map[string]interface{}
+
+func (d documents) add(url string, schema []byte) {
+	decoder := json.NewDecoder(bytes.NewReader(schema))
+	decoder.UseNumber()
+
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return
+	}
+
+	d[url] = doc
+	if id := schemaID(schema); id != "" {
+		d[id] = doc
+	}
+}
+
+// keyword returns value of keyword at absolute location, for example: file:///schemas/user.json#/properties/age/minimum.
+func (d documents) keyword(location string) (interface{}, bool) {
+	url, pointer := location, ""
+	if i := strings.IndexByte(location, '#'); i >= 0 {
+		url, pointer = location[:i], location[i+1:]
+	}
+
+	doc, ok := d[url]
+	if !ok {
+		return nil, false
+	}
+
+	return resolvePointer(doc, pointer)
+}
+
+// resolvePointer returns value at JSON pointer, for example: /items/0/price.
+func resolvePointer(doc interface{}, pointer string) (interface{}, bool) {
+	if pointer == "" {
+		return doc, true
+	}
+
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch node := doc.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, false
+			}
+
+			doc = value
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+
+			doc = node[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return doc, true
+}
+
+// describe returns scalar in JSON format or type of object and array.
+func describe(value interface{}) string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return fmt.Sprintf("array of length %d", len(v))
+	}
+
+	return compactJSON(value)
+}
+
+func compactJSON(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return string(data)
+}
+
+// excerpt returns value in indented JSON format, truncated to excerptLength characters.
+func excerpt(value interface{}) string {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	if runes := []rune(string(data)); len(runes) > excerptLength {
+		return string(runes[:excerptLength]) + "..."
+	}
+
+	return string(data)
+}
@@ -0,0 +1,73 @@
+package schema
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/pawelWritesCode/gdutils/pkg/osutils"
+	v "github.com/pawelWritesCode/gdutils/pkg/validator"
+	"github.com/pawelWritesCode/gdutils/pkg/xsd"
+)
+
+// XSDReferenceValidator is entity that has ability to validate XML document against XML Schema passed as reference.
+type XSDReferenceValidator struct {
+	fileValidator v.Validator
+
+	// schemasDir represents absolute path to XML Schemas directory.
+	schemasDir string
+}
+
+// XSDRawValidator is entity that has ability to validate XML document against XML Schema passed as string.
+type XSDRawValidator struct {
+	// schemasDir represents absolute path to directory, against which included and imported schemas are resolved.
+	schemasDir string
+}
+
+func NewDefaultXSDReferenceValidator(schemasDir string) XSDReferenceValidator {
+	return NewXSDReferenceValidator(schemasDir, osutils.NewFileValidator())
+}
+
+func NewXSDReferenceValidator(schemasDir string, fileValidator v.Validator) XSDReferenceValidator {
+	return XSDReferenceValidator{
+		fileValidator: fileValidator,
+		schemasDir:    schemasDir,
+	}
+}
+
+func NewXSDRawValidator(schemasDir string) XSDRawValidator {
+	return XSDRawValidator{schemasDir: schemasDir}
+}
+
+// Validate validates XML document against XML Schema located in schemaPath.
+// schemaPath may be relative to schemas directory or full path to XML Schema on user OS.
+func (xv XSDReferenceValidator) Validate(document, schemaPath string) error {
+	if schemaPath == "" {
+		return fmt.Errorf("provided schemaPath should not be empty string")
+	}
+
+	pth := schemaPath
+	if !path.IsAbs(schemaPath) {
+		pth = path.Clean(path.Join(xv.schemasDir, schemaPath))
+	}
+
+	if err := xv.fileValidator.Validate(pth); err != nil {
+		return fmt.Errorf("%s isn't valid path to any resource on your OS", schemaPath)
+	}
+
+	schema, err := xsd.Load(pth)
+	if err != nil {
+		return err
+	}
+
+	return schema.Validate([]byte(document))
+}
+
+// Validate validates XML document against XML Schema
+func (xv XSDRawValidator) Validate(document, schema string) error {
+	s, err := xsd.Parse([]byte(schema), xv.schemasDir)
+	if err != nil {
+		return err
+	}
+
+	return s.Validate([]byte(document))
+}
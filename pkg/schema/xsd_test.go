@@ -0,0 +1,68 @@
+package schema
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pawelWritesCode/gdutils/pkg/osutils"
+)
+
+func TestXSDValidators_Validate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	types := `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+	<xs:simpleType name="name"><xs:restriction base="xs:string"><xs:minLength value="2"/></xs:restriction></xs:simpleType>
+</xs:schema>`
+	user := `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+	<xs:include schemaLocation="types.xsd"/>
+	<xs:element name="user"><xs:complexType><xs:attribute name="name" type="name" use="required"/></xs:complexType></xs:element>
+</xs:schema>`
+	for name, content := range map[string]string{"types.xsd": types, "user.xsd": user} {
+		if err = ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reference := NewXSDReferenceValidator(dir, osutils.NewFileValidator())
+	raw := NewXSDRawValidator(dir)
+
+	tests := []struct {
+		name     string
+		document string
+		validate func(document string) error
+		wantErr  bool
+	}{
+		{name: "relative reference", document: `<user name="ivo"/>`, validate: func(document string) error {
+			return reference.Validate(document, "user.xsd")
+		}},
+		{name: "full path reference", document: `<user/>`, validate: func(document string) error {
+			return reference.Validate(document, filepath.Join(dir, "user.xsd"))
+		}, wantErr: true},
+		{name: "not existing reference", document: `<user name="ivo"/>`, validate: func(document string) error {
+			return reference.Validate(document, "order.xsd")
+		}, wantErr: true},
+		{name: "empty reference", document: `<user name="ivo"/>`, validate: func(document string) error {
+			return reference.Validate(document, "")
+		}, wantErr: true},
+		{name: "raw schema with include", document: `<user name="i"/>`, validate: func(document string) error {
+			return raw.Validate(document, user)
+		}, wantErr: true},
+		{name: "invalid raw schema", document: `<user name="ivo"/>`, validate: func(document string) error {
+			return raw.Validate(document, `<schema/>`)
+		}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.validate(tt.document); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+// Package snapshot holds utilities for comparing data with snapshots (golden files) stored on disk.
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/pawelWritesCode/gdutils/pkg/compare"
+	"github.com/pawelWritesCode/gdutils/pkg/diff"
+	"github.com/pawelWritesCode/gdutils/pkg/format"
+)
+
+// UpdateEnvironmentVariable is name of environment variable, which set to true makes Store rewrite snapshots instead of comparing them.
+const UpdateEnvironmentVariable = "GDUTILS_UPDATE_SNAPSHOTS"
+
+// RedactedValue replaces values of redacted nodes.
+const RedactedValue = "<redacted>"
+
+// ErrMismatch occurs when data does not match stored snapshot.
+var ErrMismatch = errors.New("snapshot mismatch")
+
+// Status describes result of matching data with snapshot.
+type Status string
+
+const (
+	// StatusMatched means that data matched stored snapshot.
+	StatusMatched Status = "matched"
+
+	// StatusCreated means that snapshot did not exist and was created.
+	StatusCreated Status = "created"
+
+	// StatusUpdated means that snapshot was rewritten with new data.
+	StatusUpdated Status = "updated"
+)
+
+// Store is entity that has ability to read, compare and write snapshots in directory.
+type Store struct {
+	// Dir is directory with snapshots.
+	Dir string
+
+	// Update tells whether snapshots should be rewritten instead of compared.
+	Update bool
+
+	// Differ describes differences between snapshot and data in mismatch errors.
+	Differ diff.Differ
+}
+
+var unsafeFileNameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// NewStore returns Store working in dir. Update mode is enabled when UpdateEnvironmentVariable is set to true.
+func NewStore(dir string) Store {
+	update, _ := strconv.ParseBool(os.Getenv(UpdateEnvironmentVariable))
+
+	return Store{Dir: dir, Update: update, Differ: diff.New(true)}
+}
+
+// FileName returns snapshot file path based on feature file, scenario name, example of scenario, index of snapshot
+// within scenario and data format. Snapshots of every feature file are kept in separate directory, for example:
+// "features_users/create_new_user.json" for first and "features_users/create_new_user_2.json" for second snapshot
+// of scenario "Create new user" from features/users.feature. example distinguishes examples of Scenario Outline
+// and scenarios of the same name, it may be empty string, otherwise it follows scenario name after dot,
+// for example: "features_users/create_new_user.admin.json".
+func FileName(feature, scenario, example string, index int, dataFormat format.DataFormat) string {
+	name := sanitizeFileName(scenario)
+	if name == "" {
+		name = "snapshot"
+	}
+
+	if example = sanitizeFileName(example); example != "" {
+		name += "." + example
+	}
+
+	if index > 1 {
+		name = fmt.Sprintf("%s_%d", name, index)
+	}
+
+	name += "." + strings.ToLower(string(dataFormat))
+
+	if feature = sanitizeFileName(strings.TrimSuffix(feature, filepath.Ext(feature))); feature != "" {
+		name = feature + "/" + name
+	}
+
+	return name
+}
+
+// sanitizeFileName returns lower cased name with characters unsafe for file names replaced by underscore.
+func sanitizeFileName(name string) string {
+	return strings.Trim(unsafeFileNameChars.ReplaceAllString(strings.ToLower(name), "_"), "_.")
+}
+
+// Match compares data with snapshot stored in file of given name. Missing snapshot is created.
+// In update mode, snapshot is rewritten when it differs from data. Mismatch is reported as ErrMismatch.
+func (s Store) Match(fileName string, data []byte) (Status, error) {
+	pth := filepath.Join(s.Dir, fileName)
+	stored, err := ioutil.ReadFile(pth)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("could not read snapshot %s, err: %w", pth, err)
+		}
+
+		if err = s.write(pth, data); err != nil {
+			return "", err
+		}
+
+		return StatusCreated, nil
+	}
+
+	if bytes.Equal(bytes.TrimSpace(stored), bytes.TrimSpace(data)) {
+		return StatusMatched, nil
+	}
+
+	if s.Update {
+		if err = s.write(pth, data); err != nil {
+			return "", err
+		}
+
+		return StatusUpdated, nil
+	}
+
+	return "", fmt.Errorf("%w: %s\n%s\nset environment variable %s=true to update snapshots",
+		ErrMismatch, pth, s.Differ.Strings(strings.TrimSpace(string(stored)), strings.TrimSpace(string(data))), UpdateEnvironmentVariable)
+}
+
+// Normalize returns pretty-printed data in given format with stable keys order. Values of nodes pointed
+// by redactedPaths are replaced with RedactedValue. Paths may contain wildcards, for example: $.users[*].id.
+// Redaction is not supported for XML.
+func Normalize(dataFormat format.DataFormat, data []byte, redactedPaths []string) ([]byte, error) {
+	switch dataFormat {
+	case format.JSON:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+
+		var v interface{}
+		if err := decoder.Decode(&v); err != nil {
+			return nil, fmt.Errorf("could not deserialize JSON data, err: %w", err)
+		}
+
+		buf := &bytes.Buffer{}
+		encoder := json.NewEncoder(buf)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(redact(compare.Root, v, redactedPaths)); err != nil {
+			return nil, fmt.Errorf("could not serialize JSON data, err: %w", err)
+		}
+
+		return buf.Bytes(), nil
+	case format.YAML:
+		var v interface{}
+		if err := yaml.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("could not deserialize YAML data, err: %w", err)
+		}
+
+		normalized, err := yaml.Marshal(redact(compare.Root, v, redactedPaths))
+		if err != nil {
+			return nil, fmt.Errorf("could not serialize YAML data, err: %w", err)
+		}
+
+		return normalized, nil
+	case format.XML:
+		if len(redactedPaths) > 0 {
+			return nil, fmt.Errorf("redaction is not supported for format: %s", format.XML)
+		}
+
+		return indentXML(data)
+	default:
+		return nil, fmt.Errorf("provided unknown format: %s, format should be one of : %s, %s, %s",
+			dataFormat, format.JSON, format.YAML, format.XML)
+	}
+}
+
+func (s Store) write(pth string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+		return fmt.Errorf("could not create snapshot directory, err: %w", err)
+	}
+
+	if err := ioutil.WriteFile(pth, data, 0644); err != nil {
+		return fmt.Errorf("could not write snapshot %s, err: %w", pth, err)
+	}
+
+	return nil
+}
+
+// redact replaces values of nodes matching any of redactedPaths.
+func redact(path string, value interface{}, redactedPaths []string) interface{} {
+	for _, redacted := range redactedPaths {
+		if compare.MatchPath(strings.TrimSpace(redacted), path) {
+			return RedactedValue
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, val := range v {
+			v[key] = redact(path+"."+key, val, redactedPaths)
+		}
+	case map[interface{}]interface{}:
+		for key, val := range v {
+			v[key] = redact(fmt.Sprintf("%s.%v", path, key), val, redactedPaths)
+		}
+	case []interface{}:
+		for i, val := range v {
+			v[i] = redact(fmt.Sprintf("%s[%d]", path, i), val, redactedPaths)
+		}
+	}
+
+	return value
+}
+
+// indentXML re-encodes XML document with indentation, dropping whitespace between elements.
+// Names are read raw, so namespace prefixes and declarations are kept as they are in document.
+func indentXML(data []byte) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	buf := &bytes.Buffer{}
+	encoder := xml.NewEncoder(buf)
+	encoder.Indent("", "  ")
+
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("could not deserialize XML data, err: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) == 0 {
+				continue
+			}
+		case xml.StartElement:
+			attrs := make([]xml.Attr, 0, len(t.Attr))
+			for _, attr := range t.Attr {
+				attrs = append(attrs, xml.Attr{Name: prefixedName(attr.Name), Value: attr.Value})
+			}
+
+			token = xml.StartElement{Name: prefixedName(t.Name), Attr: attrs}
+		case xml.EndElement:
+			token = xml.EndElement{Name: prefixedName(t.Name)}
+		}
+
+		if err = encoder.EncodeToken(xml.CopyToken(token)); err != nil {
+			return nil, fmt.Errorf("could not serialize XML data, err: %w", err)
+		}
+	}
+
+	if err := encoder.Flush(); err != nil {
+		return nil, fmt.Errorf("could not serialize XML data, err: %w", err)
+	}
+
+	return append(buf.Bytes(), '\n'), nil
+}
+
+// prefixedName returns raw name, with namespace prefix written in local part, so encoder does not translate it.
+func prefixedName(name xml.Name) xml.Name {
+	if name.Space == "" {
+		return name
+	}
+
+	return xml.Name{Local: name.Space + ":" + name.Local}
+}
@@ -0,0 +1,113 @@
+package snapshot
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pawelWritesCode/gdutils/pkg/format"
+)
+
+func TestFileName(t *testing.T) {
+	tests := []struct {
+		feature  string
+		scenario string
+		example  string
+		index    int
+		df       format.DataFormat
+		want     string
+	}{
+		{scenario: "Create new user", index: 1, df: format.JSON, want: "create_new_user.json"},
+		{scenario: "Create new user", index: 2, df: format.YAML, want: "create_new_user_2.yaml"},
+		{scenario: "  GET /users?page=1 ", index: 1, df: format.XML, want: "get_users_page_1.xml"},
+		{scenario: "", index: 1, df: format.JSON, want: "snapshot.json"},
+		{feature: "features/users.feature", scenario: "Create new user", index: 1, df: format.JSON, want: "features_users/create_new_user.json"},
+		{feature: "../users.feature", scenario: "Create new user", example: "7", index: 2, df: format.JSON, want: "users/create_new_user.7_2.json"},
+		{feature: "features/orders.feature", scenario: "Create new user", example: "Admin", index: 1, df: format.JSON, want: "features_orders/create_new_user.admin.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := FileName(tt.feature, tt.scenario, tt.example, tt.index, tt.df); got != tt.want {
+				t.Errorf("FileName() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		df       format.DataFormat
+		data     string
+		redacted []string
+		want     string
+		wantErr  bool
+	}{
+		{name: "json", df: format.JSON, data: `{"b": 12345678901234567890, "a": [{"id": 1, "x": true}, {"id": 2}]}`, redacted: []string{"$.a[*].id"},
+			want: "{\n  \"a\": [\n    {\n      \"id\": \"<redacted>\",\n      \"x\": true\n    },\n    {\n      \"id\": \"<redacted>\"\n    }\n  ],\n  \"b\": 12345678901234567890\n}\n"},
+		{name: "yaml", df: format.YAML, data: "b: 1\na:\n  token: abc\n", redacted: []string{"$.a.token"},
+			want: "a:\n  token: <redacted>\nb: 1\n"},
+		{name: "xml", df: format.XML, data: "<a>\n<b x=\"1\">text</b>   <c/></a>",
+			want: "<a>\n  <b x=\"1\">text</b>\n  <c></c>\n</a>\n"},
+		{name: "xml namespaces", df: format.XML,
+			data: `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns="urn:shop"><soap:Body> <order xml:lang="en" soap:mustUnderstand="1">a</order></soap:Body></soap:Envelope>`,
+			want: "<soap:Envelope xmlns:soap=\"http://schemas.xmlsoap.org/soap/envelope/\" xmlns=\"urn:shop\">\n  <soap:Body>\n    <order xml:lang=\"en\" soap:mustUnderstand=\"1\">a</order>\n  </soap:Body>\n</soap:Envelope>\n"},
+		{name: "xml redaction", df: format.XML, data: "<a></a>", redacted: []string{"$.a"}, wantErr: true},
+		{name: "invalid json", df: format.JSON, data: "{", wantErr: true},
+		{name: "unknown format", df: "abc", data: "{}", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.df, []byte(tt.data), tt.redacted)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Normalize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("Normalize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStore_Match(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "snapshots")
+	s := Store{Dir: dir}
+
+	status, err := s.Match("a.json", []byte("{\n  \"a\": 1\n}\n"))
+	if err != nil || status != StatusCreated {
+		t.Fatalf("Match() = %s, err: %v, want %s", status, err, StatusCreated)
+	}
+
+	status, err = s.Match("a.json", []byte("{\n  \"a\": 1\n}"))
+	if err != nil || status != StatusMatched {
+		t.Fatalf("Match() = %s, err: %v, want %s", status, err, StatusMatched)
+	}
+
+	_, err = s.Match("a.json", []byte("{\n  \"a\": 2\n}"))
+	if !errors.Is(err, ErrMismatch) {
+		t.Fatalf("Match() expected ErrMismatch, got %v", err)
+	}
+
+	s.Update = true
+	status, err = s.Match("a.json", []byte("{\n  \"a\": 2\n}"))
+	if err != nil || status != StatusUpdated {
+		t.Fatalf("Match() = %s, err: %v, want %s", status, err, StatusUpdated)
+	}
+
+	stored, _ := ioutil.ReadFile(filepath.Join(dir, "a.json"))
+	if string(stored) != "{\n  \"a\": 2\n}" {
+		t.Errorf("unexpected updated snapshot: %s", stored)
+	}
+}
+
+func TestNewStore(t *testing.T) {
+	os.Setenv(UpdateEnvironmentVariable, "true")
+	defer os.Unsetenv(UpdateEnvironmentVariable)
+
+	if s := NewStore("dir"); !s.Update || s.Dir != "dir" {
+		t.Errorf("NewStore() = %+v, expected update mode", s)
+	}
+}
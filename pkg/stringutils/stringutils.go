@@ -144,3 +144,13 @@ func RunesFromCharset(length int, charset []rune) []rune {
 
 	return output
 }
+
+// Truncate returns at most length first runes of s. Ellipsis is appended when s was truncated.
+func Truncate(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+
+	return string(runes[:length]) + "..."
+}
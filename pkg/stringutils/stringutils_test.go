@@ -20,3 +20,13 @@ func TestStringWithCharset(t *testing.T) {
 		}
 	}
 }
+
+func TestTruncate(t *testing.T) {
+	if got := Truncate("zażółć", 3); got != "zaż..." {
+		t.Errorf("Truncate() = %s, want zaż...", got)
+	}
+
+	if got := Truncate("abc", 3); got != "abc" {
+		t.Errorf("Truncate() = %s, want abc", got)
+	}
+}
@@ -0,0 +1,70 @@
+package timeutils
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/pawelWritesCode/gdutils/pkg/mathutils"
+)
+
+const (
+	// LayoutUnix describes Unix epoch timestamp in seconds, which may have fractional part.
+	LayoutUnix = "unix"
+
+	// LayoutUnixMilli describes Unix epoch timestamp in milliseconds.
+	LayoutUnixMilli = "unixmilli"
+)
+
+// namedLayouts maps names of well-known layouts to Go layouts.
+var namedLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"DateTime":    "2006-01-02 15:04:05",
+	"DateOnly":    "2006-01-02",
+	"TimeOnly":    "15:04:05",
+}
+
+// ParseTime parses value according to layout. Layout may be Go layout, for example: 2006-01-02,
+// name of well-known layout, for example: RFC3339, RFC1123, DateOnly, or one of LayoutUnix, LayoutUnixMilli.
+// Unix timestamps may be provided as numbers or strings.
+func ParseTime(value interface{}, layout string) (time.Time, error) {
+	switch strings.ToLower(layout) {
+	case LayoutUnix, LayoutUnixMilli:
+		timestamp, err := mathutils.ToFloat64(value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("value %v is not valid %s timestamp, err: %w", value, layout, err)
+		}
+
+		if strings.ToLower(layout) == LayoutUnixMilli {
+			timestamp /= 1000
+		}
+
+		sec, frac := math.Modf(timestamp)
+
+		return time.Unix(int64(sec), int64(math.Round(frac*1e9))), nil
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("value %v of type %T is not string", value, value)
+	}
+
+	if named, ok := namedLayouts[layout]; ok {
+		layout = named
+	}
+
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("value %s is not valid time in layout %s, err: %w", s, layout, err)
+	}
+
+	return t, nil
+}
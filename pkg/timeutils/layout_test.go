@@ -0,0 +1,38 @@
+package timeutils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		layout  string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "RFC3339", value: "2021-05-01T10:00:00Z", layout: "RFC3339", want: time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)},
+		{name: "Go layout", value: "01.05.2021", layout: "02.01.2006", want: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "DateOnly", value: "2021-05-01", layout: "DateOnly", want: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "unix number", value: float64(1619863200), layout: "unix", want: time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)},
+		{name: "unix string with fraction", value: "1619863200.5", layout: LayoutUnix, want: time.Date(2021, 5, 1, 10, 0, 0, 5e8, time.UTC)},
+		{name: "unix milliseconds", value: uint64(1619863200250), layout: "unixMilli", want: time.Date(2021, 5, 1, 10, 0, 0, 25e7, time.UTC)},
+		{name: "invalid unix", value: "abc", layout: "unix", wantErr: true},
+		{name: "not string", value: 10.0, layout: "RFC3339", wantErr: true},
+		{name: "invalid date", value: "2021-13-01", layout: "DateOnly", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTime(tt.value, tt.layout)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTime() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
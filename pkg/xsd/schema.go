@@ -0,0 +1,1116 @@
+// Package xsd holds pure Go validator of XML documents against XML Schema (XSD) 1.0.
+//
+// Supported are global and local element and attribute declarations, named and anonymous complex and simple types,
+// sequence, choice, all, group and any particles, simple and complex content derivation, substitution groups,
+// xsi:type, xsi:nil, built-in types with facets, include and import. Identity constraints (key, keyref, unique)
+// and redefine are not supported.
+package xsd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// unbounded is maxOccurs of particle without upper limit.
+const unbounded = -1
+
+// Schema is compiled set of XML Schema documents.
+type Schema struct {
+	elements        map[xml.Name]*element
+	types           map[xml.Name]interface{}
+	groups          map[xml.Name]*particle
+	attributeGroups map[xml.Name]*attributeGroup
+	attributes      map[xml.Name]*attribute
+
+	// substitutes maps heads of substitution groups to their members.
+	substitutes map[xml.Name][]*element
+
+	// raw holds top-level schema components before compilation.
+	raw map[componentKey]*component
+}
+
+// kind of top-level schema component.
+type kind string
+
+const (
+	kindElement        kind = "element"
+	kindType           kind = "type"
+	kindGroup          kind = "group"
+	kindAttributeGroup kind = "attributeGroup"
+	kindAttribute      kind = "attribute"
+)
+
+type componentKey struct {
+	kind kind
+	name xml.Name
+}
+
+// component is top-level schema component with document it is declared in.
+type component struct {
+	n   *node
+	doc *document
+}
+
+// document is single schema document.
+type document struct {
+	location             string
+	targetNamespace      string
+	elementFormDefault   string
+	attributeFormDefault string
+
+	// chameleon tells whether document without target namespace was included into schema with one.
+	chameleon bool
+}
+
+type element struct {
+	name     xml.Name
+	typ      interface{}
+	nillable bool
+	abstract bool
+	fixed    *string
+	head     *xml.Name
+}
+
+// particleKind is kind of content model particle.
+type particleKind int
+
+const (
+	elementParticle particleKind = iota
+	sequenceParticle
+	choiceParticle
+	allParticle
+	anyParticle
+)
+
+type particle struct {
+	kind     particleKind
+	min, max int
+	element  *element
+	children []*particle
+	wildcard *wildcard
+}
+
+// wildcard describes any and anyAttribute.
+type wildcard struct {
+	// namespaces allowed by wildcard, nil means any namespace.
+	namespaces []string
+
+	// other tells whether namespaces other than target namespace and no namespace are allowed.
+	other           bool
+	targetNamespace string
+	processContents string
+}
+
+type complexType struct {
+	name    xml.Name
+	mixed   bool
+	content *particle
+
+	// simple is type of content of complex type with simple content.
+	simple       *simpleType
+	attributes   []*attributeUse
+	anyAttribute *wildcard
+}
+
+type attribute struct {
+	name  xml.Name
+	typ   *simpleType
+	fixed *string
+}
+
+type attributeUse struct {
+	attribute  *attribute
+	required   bool
+	prohibited bool
+	fixed      *string
+}
+
+type attributeGroup struct {
+	uses         []*attributeUse
+	anyAttribute *wildcard
+}
+
+// Load reads XML Schema from file. Included and imported schemas are resolved relative to including schema.
+func Load(location string) (*Schema, error) {
+	s := newSchema()
+	if err := s.loadFile(location, nil, map[string]bool{}); err != nil {
+		return nil, err
+	}
+
+	return s, s.compile()
+}
+
+// Parse reads XML Schema from data. Included and imported schemas are resolved relative to baseDir.
+func Parse(data []byte, baseDir string) (*Schema, error) {
+	s := newSchema()
+	if err := s.load(data, filepath.Join(baseDir, "schema.xsd"), nil, map[string]bool{}); err != nil {
+		return nil, err
+	}
+
+	return s, s.compile()
+}
+
+func newSchema() *Schema {
+	return &Schema{
+		elements:        map[xml.Name]*element{},
+		types:           map[xml.Name]interface{}{},
+		groups:          map[xml.Name]*particle{},
+		attributeGroups: map[xml.Name]*attributeGroup{},
+		attributes:      map[xml.Name]*attribute{},
+		substitutes:     map[xml.Name][]*element{},
+		raw:             map[componentKey]*component{},
+	}
+}
+
+// loadFile reads schema document from file. targetNamespace is namespace of including schema, nil for import or root.
+func (s *Schema) loadFile(location string, targetNamespace *string, visited map[string]bool) error {
+	location, err := filepath.Abs(location)
+	if err != nil {
+		return err
+	}
+
+	key := location
+	if targetNamespace != nil {
+		key += "#" + *targetNamespace
+	}
+
+	if visited[key] {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(location)
+	if err != nil {
+		return fmt.Errorf("could not read schema %s, err: %w", location, err)
+	}
+
+	return s.load(data, location, targetNamespace, visited)
+}
+
+func (s *Schema) load(data []byte, location string, targetNamespace *string, visited map[string]bool) error {
+	root, err := parseTree(data)
+	if err != nil {
+		return fmt.Errorf("schema %s: %w", location, err)
+	}
+
+	if root.name.Space != Namespace || root.name.Local != "schema" {
+		return fmt.Errorf("schema %s: root element should be schema from %s namespace", location, Namespace)
+	}
+
+	doc := &document{
+		location:             location,
+		targetNamespace:      root.get("targetNamespace"),
+		elementFormDefault:   root.get("elementFormDefault"),
+		attributeFormDefault: root.get("attributeFormDefault"),
+	}
+
+	if targetNamespace != nil {
+		switch {
+		case doc.targetNamespace == "" && *targetNamespace != "":
+			doc.targetNamespace = *targetNamespace
+			doc.chameleon = true
+		case doc.targetNamespace != *targetNamespace:
+			return fmt.Errorf("schema %s: included schema has target namespace '%s', expected '%s'",
+				location, doc.targetNamespace, *targetNamespace)
+		}
+	}
+
+	visited[location] = true
+	visited[location+"#"+doc.targetNamespace] = true
+
+	for _, n := range root.xsdChildren() {
+		switch n.name.Local {
+		case "include", "import":
+			schemaLocation := n.get("schemaLocation")
+			if schemaLocation == "" {
+				continue
+			}
+
+			if strings.Contains(schemaLocation, "://") {
+				return fmt.Errorf("schema %s: only local schema locations are supported, got %s", location, schemaLocation)
+			}
+
+			if !filepath.IsAbs(schemaLocation) {
+				schemaLocation = filepath.Join(filepath.Dir(location), schemaLocation)
+			}
+
+			var ns *string
+			if n.name.Local == "include" {
+				ns = &doc.targetNamespace
+			}
+
+			if err = s.loadFile(schemaLocation, ns, visited); err != nil {
+				return err
+			}
+		case "redefine":
+			return fmt.Errorf("schema %s: redefine is not supported", location)
+		case "element":
+			err = s.register(kindElement, n, doc)
+		case "complexType", "simpleType":
+			err = s.register(kindType, n, doc)
+		case "group":
+			err = s.register(kindGroup, n, doc)
+		case "attributeGroup":
+			err = s.register(kindAttributeGroup, n, doc)
+		case "attribute":
+			err = s.register(kindAttribute, n, doc)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) register(k kind, n *node, doc *document) error {
+	name := xml.Name{Space: doc.targetNamespace, Local: n.get("name")}
+	key := componentKey{kind: k, name: name}
+	if _, ok := s.raw[key]; ok {
+		return fmt.Errorf("schema %s: %s %s is declared more than once", doc.location, k, formatName(name))
+	}
+
+	s.raw[key] = &component{n: n, doc: doc}
+
+	return nil
+}
+
+// compile compiles every top-level component, so errors in schema are reported before validation.
+func (s *Schema) compile() error {
+	for key := range s.raw {
+		var err error
+		switch key.kind {
+		case kindElement:
+			_, err = s.globalElement(key.name)
+		case kindType:
+			_, err = s.typeDefinition(key.name)
+		case kindGroup:
+			_, err = s.group(key.name)
+		case kindAttributeGroup:
+			_, err = s.attributeGroup(key.name)
+		case kindAttribute:
+			_, err = s.globalAttribute(key.name)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, el := range s.elements {
+		if el.head != nil {
+			s.substitutes[*el.head] = append(s.substitutes[*el.head], el)
+		}
+	}
+
+	return nil
+}
+
+// resolve resolves QName from attribute of schema element.
+func (doc *document) resolve(n *node, value string) (xml.Name, error) {
+	name, err := n.qname(value)
+	if err != nil {
+		return name, fmt.Errorf("schema %s: %w", doc.location, err)
+	}
+
+	if name.Space == "" && doc.chameleon {
+		name.Space = doc.targetNamespace
+	}
+
+	return name, nil
+}
+
+func (doc *document) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("schema %s: %s", doc.location, fmt.Sprintf(format, args...))
+}
+
+func (s *Schema) lookup(k kind, name xml.Name) (*component, error) {
+	c, ok := s.raw[componentKey{kind: k, name: name}]
+	if !ok {
+		return nil, fmt.Errorf("%s %s is not declared", k, formatName(name))
+	}
+
+	return c, nil
+}
+
+func (s *Schema) globalElement(name xml.Name) (*element, error) {
+	if el, ok := s.elements[name]; ok {
+		return el, nil
+	}
+
+	c, err := s.lookup(kindElement, name)
+	if err != nil {
+		return nil, err
+	}
+
+	el := &element{name: name}
+	s.elements[name] = el
+
+	if head := c.n.get("substitutionGroup"); head != "" {
+		headName, err := c.doc.resolve(c.n, head)
+		if err != nil {
+			return nil, err
+		}
+
+		el.head = &headName
+	}
+
+	return el, s.fillElement(el, c.n, c.doc)
+}
+
+func (s *Schema) localElement(n *node, doc *document) (*element, error) {
+	if ref := n.get("ref"); ref != "" {
+		name, err := doc.resolve(n, ref)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.globalElement(name)
+	}
+
+	form := n.get("form")
+	if form == "" {
+		form = doc.elementFormDefault
+	}
+
+	el := &element{name: xml.Name{Local: n.get("name")}}
+	if form == "qualified" {
+		el.name.Space = doc.targetNamespace
+	}
+
+	return el, s.fillElement(el, n, doc)
+}
+
+func (s *Schema) fillElement(el *element, n *node, doc *document) error {
+	el.nillable = n.get("nillable") == "true"
+	el.abstract = n.get("abstract") == "true"
+	if fixed, ok := n.attr("", "fixed"); ok {
+		el.fixed = &fixed
+	}
+
+	var err error
+	switch {
+	case n.get("type") != "":
+		var name xml.Name
+		if name, err = doc.resolve(n, n.get("type")); err != nil {
+			return err
+		}
+
+		el.typ, err = s.typeDefinition(name)
+	case n.child("complexType") != nil:
+		el.typ, err = s.complexType(n.child("complexType"), doc, xml.Name{})
+	case n.child("simpleType") != nil:
+		el.typ, err = s.simpleType(n.child("simpleType"), doc, xml.Name{})
+	case el.head != nil:
+		var head *element
+		if head, err = s.globalElement(*el.head); err == nil {
+			if head.typ == nil {
+				return doc.errorf("substitution group of element %s is circular", formatName(el.name))
+			}
+
+			el.typ = head.typ
+		}
+	default:
+		el.typ, err = s.typeDefinition(xml.Name{Space: Namespace, Local: "anyType"})
+	}
+
+	if err != nil {
+		return fmt.Errorf("element %s: %w", formatName(el.name), err)
+	}
+
+	return nil
+}
+
+// typeDefinition returns *complexType or *simpleType with given name.
+func (s *Schema) typeDefinition(name xml.Name) (interface{}, error) {
+	if t, ok := s.types[name]; ok {
+		return t, nil
+	}
+
+	if name.Space == Namespace {
+		return s.builtinType(name.Local)
+	}
+
+	c, err := s.lookup(kindType, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.n.name.Local == "complexType" {
+		return s.complexType(c.n, c.doc, name)
+	}
+
+	return s.simpleType(c.n, c.doc, name)
+}
+
+func (s *Schema) simpleTypeDefinition(name xml.Name) (*simpleType, error) {
+	t, err := s.typeDefinition(name)
+	if err != nil {
+		return nil, err
+	}
+
+	st, ok := t.(*simpleType)
+	if !ok {
+		return nil, fmt.Errorf("type %s is not simple type", formatName(name))
+	}
+
+	return st, nil
+}
+
+func (s *Schema) builtinType(local string) (interface{}, error) {
+	name := xml.Name{Space: Namespace, Local: local}
+
+	if local == "anyType" {
+		lax := &wildcard{processContents: "lax"}
+		t := &complexType{
+			name:         name,
+			mixed:        true,
+			content:      &particle{kind: anyParticle, min: 0, max: unbounded, wildcard: lax},
+			anyAttribute: lax,
+		}
+		s.types[name] = t
+
+		return t, nil
+	}
+
+	if item, ok := builtinLists[local]; ok {
+		itemType, err := s.simpleTypeDefinition(xml.Name{Space: Namespace, Local: item})
+		if err != nil {
+			return nil, err
+		}
+
+		t := &simpleType{name: name, variety: list, item: itemType}
+		s.types[name] = t
+
+		return t, nil
+	}
+
+	base, ok := builtinTypes[local]
+	if !ok {
+		return nil, fmt.Errorf("built-in type %s is not supported", local)
+	}
+
+	t := &simpleType{name: name, builtin: local}
+	if base != "" {
+		baseType, err := s.simpleTypeDefinition(xml.Name{Space: Namespace, Local: base})
+		if err != nil {
+			return nil, err
+		}
+
+		t.base = baseType
+	}
+
+	s.types[name] = t
+
+	return t, nil
+}
+
+func (s *Schema) complexType(n *node, doc *document, name xml.Name) (*complexType, error) {
+	t := &complexType{name: name, mixed: n.get("mixed") == "true"}
+	if name.Local != "" {
+		s.types[name] = t
+	}
+
+	var err error
+	if content := n.child("simpleContent"); content != nil {
+		err = s.simpleContent(t, content, doc)
+	} else if content = n.child("complexContent"); content != nil {
+		err = s.complexContent(t, content, doc)
+	} else {
+		err = s.contentModel(t, n, doc)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("complex type %s: %w", t.describe(), err)
+	}
+
+	return t, nil
+}
+
+func (t *complexType) describe() string {
+	if t.name.Local != "" {
+		return formatName(t.name)
+	}
+
+	return "(anonymous)"
+}
+
+// contentModel reads particle and attributes of complex type or its derivation.
+func (s *Schema) contentModel(t *complexType, n *node, doc *document) error {
+	for _, c := range n.xsdChildren() {
+		switch c.name.Local {
+		case "sequence", "choice", "all", "group":
+			p, err := s.particle(c, doc)
+			if err != nil {
+				return err
+			}
+
+			t.content = p
+		}
+	}
+
+	return s.attributeUses(t, n, doc)
+}
+
+func (s *Schema) attributeUses(t *complexType, n *node, doc *document) error {
+	uses, anyAttribute, err := s.attributeDeclarations(n, doc)
+	if err != nil {
+		return err
+	}
+
+	for _, use := range uses {
+		t.setAttribute(use)
+	}
+
+	if anyAttribute != nil {
+		t.anyAttribute = anyAttribute
+	}
+
+	return nil
+}
+
+// setAttribute adds attribute use or replaces use of attribute with the same name.
+func (t *complexType) setAttribute(use *attributeUse) {
+	for i, existing := range t.attributes {
+		if existing.attribute.name == use.attribute.name {
+			t.attributes[i] = use
+			return
+		}
+	}
+
+	t.attributes = append(t.attributes, use)
+}
+
+func (s *Schema) baseComplexType(n *node, doc *document) (interface{}, error) {
+	baseName, err := doc.resolve(n, n.get("base"))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.typeDefinition(baseName)
+}
+
+func (s *Schema) simpleContent(t *complexType, content *node, doc *document) error {
+	derivation := content.child("extension")
+	if derivation == nil {
+		derivation = content.child("restriction")
+	}
+
+	if derivation == nil {
+		return doc.errorf("simpleContent should have extension or restriction")
+	}
+
+	base, err := s.baseComplexType(derivation, doc)
+	if err != nil {
+		return err
+	}
+
+	switch b := base.(type) {
+	case *simpleType:
+		t.simple = b
+	case *complexType:
+		if b.simple == nil {
+			return doc.errorf("base type %s of simple content should have simple content", b.describe())
+		}
+
+		t.simple = b.simple
+		t.attributes = append(t.attributes, b.attributes...)
+		t.anyAttribute = b.anyAttribute
+	}
+
+	if derivation.name.Local == "restriction" {
+		restricted := &simpleType{base: t.simple, variety: t.simple.variety}
+		if inline := derivation.child("simpleType"); inline != nil {
+			if restricted.base, err = s.simpleType(inline, doc, xml.Name{}); err != nil {
+				return err
+			}
+		}
+
+		if err = s.facets(restricted, derivation, doc); err != nil {
+			return err
+		}
+
+		t.simple = restricted
+	}
+
+	return s.attributeUses(t, derivation, doc)
+}
+
+func (s *Schema) complexContent(t *complexType, content *node, doc *document) error {
+	if mixed, ok := content.attr("", "mixed"); ok {
+		t.mixed = mixed == "true"
+	}
+
+	derivation := content.child("extension")
+	if derivation == nil {
+		derivation = content.child("restriction")
+	}
+
+	if derivation == nil {
+		return doc.errorf("complexContent should have extension or restriction")
+	}
+
+	base, err := s.baseComplexType(derivation, doc)
+	if err != nil {
+		return err
+	}
+
+	b, ok := base.(*complexType)
+	if !ok {
+		return doc.errorf("base type of complex content should be complex type")
+	}
+
+	t.attributes = append(t.attributes, b.attributes...)
+	t.anyAttribute = b.anyAttribute
+
+	if err = s.contentModel(t, derivation, doc); err != nil {
+		return err
+	}
+
+	if derivation.name.Local == "extension" && b.content != nil {
+		if t.content == nil {
+			t.content = b.content
+		} else {
+			t.content = &particle{kind: sequenceParticle, min: 1, max: 1, children: []*particle{b.content, t.content}}
+		}
+	}
+
+	return nil
+}
+
+func occurs(n *node, doc *document) (int, int, error) {
+	min, max := 1, 1
+	if v, ok := n.attr("", "minOccurs"); ok {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			return 0, 0, doc.errorf("invalid minOccurs '%s'", v)
+		}
+
+		min = parsed
+	}
+
+	if v, ok := n.attr("", "maxOccurs"); ok {
+		if v == "unbounded" {
+			max = unbounded
+		} else {
+			parsed, err := strconv.Atoi(v)
+			if err != nil || parsed < 0 {
+				return 0, 0, doc.errorf("invalid maxOccurs '%s'", v)
+			}
+
+			max = parsed
+		}
+	}
+
+	if max != unbounded && max < min {
+		return 0, 0, doc.errorf("maxOccurs %d is less than minOccurs %d", max, min)
+	}
+
+	return min, max, nil
+}
+
+func (s *Schema) particle(n *node, doc *document) (*particle, error) {
+	min, max, err := occurs(n, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &particle{min: min, max: max}
+	switch n.name.Local {
+	case "element":
+		p.kind = elementParticle
+		if p.element, err = s.localElement(n, doc); err != nil {
+			return nil, err
+		}
+	case "any":
+		p.kind = anyParticle
+		p.wildcard = newWildcard(n, doc)
+	case "group":
+		name, err := doc.resolve(n, n.get("ref"))
+		if err != nil {
+			return nil, err
+		}
+
+		group, err := s.group(name)
+		if err != nil {
+			return nil, err
+		}
+
+		*p = *group
+		p.min, p.max = min, max
+	case "sequence", "choice", "all":
+		p.kind = map[string]particleKind{"sequence": sequenceParticle, "choice": choiceParticle, "all": allParticle}[n.name.Local]
+		for _, c := range n.xsdChildren() {
+			child, err := s.particle(c, doc)
+			if err != nil {
+				return nil, err
+			}
+
+			p.children = append(p.children, child)
+		}
+	default:
+		return nil, doc.errorf("unexpected %s in content model", n.name.Local)
+	}
+
+	return p, nil
+}
+
+func (s *Schema) group(name xml.Name) (*particle, error) {
+	if g, ok := s.groups[name]; ok {
+		if g == nil {
+			return nil, fmt.Errorf("group %s is circular", formatName(name))
+		}
+
+		return g, nil
+	}
+
+	c, err := s.lookup(kindGroup, name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.groups[name] = nil
+	for _, model := range c.n.xsdChildren() {
+		p, err := s.particle(model, c.doc)
+		if err != nil {
+			return nil, err
+		}
+
+		s.groups[name] = p
+
+		return p, nil
+	}
+
+	return nil, c.doc.errorf("group %s should have sequence, choice or all", formatName(name))
+}
+
+func newWildcard(n *node, doc *document) *wildcard {
+	w := &wildcard{processContents: n.get("processContents"), targetNamespace: doc.targetNamespace}
+	if w.processContents == "" {
+		w.processContents = "strict"
+	}
+
+	namespace := n.get("namespace")
+	switch namespace {
+	case "", "##any":
+	case "##other":
+		w.other = true
+	default:
+		w.namespaces = []string{}
+		for _, ns := range strings.Fields(namespace) {
+			switch ns {
+			case "##targetNamespace":
+				ns = doc.targetNamespace
+			case "##local":
+				ns = ""
+			}
+
+			w.namespaces = append(w.namespaces, ns)
+		}
+	}
+
+	return w
+}
+
+// allows tells whether wildcard allows name.
+func (w *wildcard) allows(name xml.Name) bool {
+	if w.other {
+		return name.Space != "" && name.Space != w.targetNamespace
+	}
+
+	if w.namespaces == nil {
+		return true
+	}
+
+	return containsString(w.namespaces, name.Space)
+}
+
+// attributeDeclarations reads attribute, attributeGroup and anyAttribute children of n.
+func (s *Schema) attributeDeclarations(n *node, doc *document) ([]*attributeUse, *wildcard, error) {
+	var uses []*attributeUse
+	var anyAttribute *wildcard
+	for _, c := range n.xsdChildren() {
+		switch c.name.Local {
+		case "attribute":
+			use, err := s.attributeUse(c, doc)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			uses = append(uses, use)
+		case "attributeGroup":
+			name, err := doc.resolve(c, c.get("ref"))
+			if err != nil {
+				return nil, nil, err
+			}
+
+			group, err := s.attributeGroup(name)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			uses = append(uses, group.uses...)
+			if group.anyAttribute != nil {
+				anyAttribute = group.anyAttribute
+			}
+		case "anyAttribute":
+			anyAttribute = newWildcard(c, doc)
+		}
+	}
+
+	return uses, anyAttribute, nil
+}
+
+func (s *Schema) attributeGroup(name xml.Name) (*attributeGroup, error) {
+	if g, ok := s.attributeGroups[name]; ok {
+		if g == nil {
+			return nil, fmt.Errorf("attribute group %s is circular", formatName(name))
+		}
+
+		return g, nil
+	}
+
+	c, err := s.lookup(kindAttributeGroup, name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.attributeGroups[name] = nil
+	uses, anyAttribute, err := s.attributeDeclarations(c.n, c.doc)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &attributeGroup{uses: uses, anyAttribute: anyAttribute}
+	s.attributeGroups[name] = g
+
+	return g, nil
+}
+
+func (s *Schema) attributeUse(n *node, doc *document) (*attributeUse, error) {
+	use := &attributeUse{required: n.get("use") == "required", prohibited: n.get("use") == "prohibited"}
+	if fixed, ok := n.attr("", "fixed"); ok {
+		use.fixed = &fixed
+	}
+
+	if ref := n.get("ref"); ref != "" {
+		name, err := doc.resolve(n, ref)
+		if err != nil {
+			return nil, err
+		}
+
+		if use.attribute, err = s.globalAttribute(name); err != nil {
+			return nil, err
+		}
+
+		return use, nil
+	}
+
+	form := n.get("form")
+	if form == "" {
+		form = doc.attributeFormDefault
+	}
+
+	a := &attribute{name: xml.Name{Local: n.get("name")}}
+	if form == "qualified" {
+		a.name.Space = doc.targetNamespace
+	}
+
+	use.attribute = a
+
+	return use, s.fillAttribute(a, n, doc)
+}
+
+func (s *Schema) globalAttribute(name xml.Name) (*attribute, error) {
+	if a, ok := s.attributes[name]; ok {
+		return a, nil
+	}
+
+	if name.Space == "http://www.w3.org/XML/1998/namespace" {
+		a := &attribute{name: name, typ: &simpleType{name: name, builtin: "anySimpleType"}}
+		s.attributes[name] = a
+
+		return a, nil
+	}
+
+	c, err := s.lookup(kindAttribute, name)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &attribute{name: name}
+	s.attributes[name] = a
+
+	return a, s.fillAttribute(a, c.n, c.doc)
+}
+
+func (s *Schema) fillAttribute(a *attribute, n *node, doc *document) error {
+	if fixed, ok := n.attr("", "fixed"); ok {
+		a.fixed = &fixed
+	}
+
+	var err error
+	switch {
+	case n.get("type") != "":
+		var name xml.Name
+		if name, err = doc.resolve(n, n.get("type")); err == nil {
+			a.typ, err = s.simpleTypeDefinition(name)
+		}
+	case n.child("simpleType") != nil:
+		a.typ, err = s.simpleType(n.child("simpleType"), doc, xml.Name{})
+	default:
+		a.typ, err = s.simpleTypeDefinition(xml.Name{Space: Namespace, Local: "anySimpleType"})
+	}
+
+	if err != nil {
+		return fmt.Errorf("attribute %s: %w", formatName(a.name), err)
+	}
+
+	return nil
+}
+
+func (s *Schema) simpleType(n *node, doc *document, name xml.Name) (*simpleType, error) {
+	t := &simpleType{name: name}
+	if name.Local != "" {
+		s.types[name] = t
+	}
+
+	var err error
+	switch {
+	case n.child("restriction") != nil:
+		restriction := n.child("restriction")
+		if base := restriction.get("base"); base != "" {
+			var baseName xml.Name
+			if baseName, err = doc.resolve(restriction, base); err == nil {
+				t.base, err = s.simpleTypeDefinition(baseName)
+			}
+		} else if inline := restriction.child("simpleType"); inline != nil {
+			t.base, err = s.simpleType(inline, doc, xml.Name{})
+		} else {
+			err = doc.errorf("restriction should have base type")
+		}
+
+		if err == nil {
+			if t.base == nil {
+				err = doc.errorf("simple type %s is circular", formatName(name))
+			} else {
+				t.variety = t.base.variety
+				err = s.facets(t, restriction, doc)
+			}
+		}
+	case n.child("list") != nil:
+		l := n.child("list")
+		t.variety = list
+		if itemType := l.get("itemType"); itemType != "" {
+			var itemName xml.Name
+			if itemName, err = doc.resolve(l, itemType); err == nil {
+				t.item, err = s.simpleTypeDefinition(itemName)
+			}
+		} else if inline := l.child("simpleType"); inline != nil {
+			t.item, err = s.simpleType(inline, doc, xml.Name{})
+		} else {
+			err = doc.errorf("list should have item type")
+		}
+	case n.child("union") != nil:
+		u := n.child("union")
+		t.variety = union
+		for _, member := range strings.Fields(u.get("memberTypes")) {
+			var memberName xml.Name
+			if memberName, err = doc.resolve(u, member); err != nil {
+				break
+			}
+
+			var memberType *simpleType
+			if memberType, err = s.simpleTypeDefinition(memberName); err != nil {
+				break
+			}
+
+			t.members = append(t.members, memberType)
+		}
+
+		for _, inline := range u.xsdChildren() {
+			if err != nil {
+				break
+			}
+
+			var memberType *simpleType
+			if memberType, err = s.simpleType(inline, doc, xml.Name{}); err == nil {
+				t.members = append(t.members, memberType)
+			}
+		}
+	default:
+		err = doc.errorf("simple type should have restriction, list or union")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("simple type %s: %w", t.describe(), err)
+	}
+
+	return t, nil
+}
+
+// facets reads constraining facets of restriction.
+func (s *Schema) facets(t *simpleType, restriction *node, doc *document) error {
+	intFacet := func(c *node) (*int, error) {
+		v, err := strconv.Atoi(strings.TrimSpace(c.get("value")))
+		if err != nil || v < 0 {
+			return nil, doc.errorf("facet %s has invalid value '%s'", c.name.Local, c.get("value"))
+		}
+
+		return &v, nil
+	}
+
+	for _, c := range restriction.xsdChildren() {
+		value := c.get("value")
+
+		var err error
+		switch c.name.Local {
+		case "enumeration":
+			t.facets.enumeration = append(t.facets.enumeration, value)
+		case "pattern":
+			pattern, patternErr := compilePattern(value)
+			if patternErr != nil {
+				return doc.errorf("%v", patternErr)
+			}
+
+			t.facets.patterns = append(t.facets.patterns, pattern)
+		case "length":
+			t.facets.length, err = intFacet(c)
+		case "minLength":
+			t.facets.minLength, err = intFacet(c)
+		case "maxLength":
+			t.facets.maxLength, err = intFacet(c)
+		case "totalDigits":
+			t.facets.totalDigits, err = intFacet(c)
+		case "fractionDigits":
+			t.facets.fractionDigits, err = intFacet(c)
+		case "minInclusive":
+			t.facets.minInclusive = &value
+		case "maxInclusive":
+			t.facets.maxInclusive = &value
+		case "minExclusive":
+			t.facets.minExclusive = &value
+		case "maxExclusive":
+			t.facets.maxExclusive = &value
+		case "whiteSpace":
+			if !containsString([]string{preserve, replace, collapse}, value) {
+				return doc.errorf("facet whiteSpace has invalid value '%s'", value)
+			}
+
+			t.facets.whiteSpace = value
+		case "simpleType", "attribute", "attributeGroup", "anyAttribute":
+		default:
+			return doc.errorf("facet %s is not supported", c.name.Local)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
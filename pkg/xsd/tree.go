@@ -0,0 +1,162 @@
+package xsd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// Namespace is namespace of XML Schema definition language.
+	Namespace = "http://www.w3.org/2001/XMLSchema"
+
+	// InstanceNamespace is namespace of XML Schema instance attributes, like xsi:type or xsi:nil.
+	InstanceNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+	xmlnsPrefix = "xmlns"
+)
+
+// node is XML element with namespaces in scope, used both for schema and instance documents.
+type node struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	children []*node
+	text     string
+
+	// hasText tells whether element contains non-whitespace character data.
+	hasText bool
+
+	// namespaces maps prefixes in scope to namespaces, default namespace is under empty prefix.
+	namespaces map[string]string
+}
+
+// parseTree parses XML document into tree of elements.
+func parseTree(data []byte) (*node, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var root *node
+	stack := []*node{}
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid XML document, err: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			n := &node{name: t.Name, namespaces: map[string]string{"xml": "http://www.w3.org/XML/1998/namespace"}}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+				for prefix, ns := range parent.namespaces {
+					n.namespaces[prefix] = ns
+				}
+			}
+
+			for _, attr := range t.Attr {
+				switch {
+				case attr.Name.Space == xmlnsPrefix:
+					n.namespaces[attr.Name.Local] = attr.Value
+				case attr.Name.Space == "" && attr.Name.Local == xmlnsPrefix:
+					n.namespaces[""] = attr.Value
+				default:
+					n.attrs = append(n.attrs, attr)
+				}
+			}
+
+			if root == nil {
+				root = n
+			}
+
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				n := stack[len(stack)-1]
+				n.text += string(t)
+				if strings.TrimSpace(string(t)) != "" {
+					n.hasText = true
+				}
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, errors.New("invalid XML document, root element not found")
+	}
+
+	return root, nil
+}
+
+// attr returns value of attribute and whether it exists. Attribute without prefix is expected when space is empty.
+func (n *node) attr(space, local string) (string, bool) {
+	for _, a := range n.attrs {
+		if a.Name.Space == space && a.Name.Local == local {
+			return a.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// get returns value of attribute without prefix or empty string.
+func (n *node) get(local string) string {
+	v, _ := n.attr("", local)
+
+	return v
+}
+
+// qname resolves prefixed value, for example: "tns:order" with namespaces in scope of element.
+func (n *node) qname(value string) (xml.Name, error) {
+	value = strings.TrimSpace(value)
+	prefix, local := "", value
+	if i := strings.IndexByte(value, ':'); i >= 0 {
+		prefix, local = value[:i], value[i+1:]
+	}
+
+	ns, ok := n.namespaces[prefix]
+	if !ok && prefix != "" {
+		return xml.Name{}, fmt.Errorf("prefix '%s' of '%s' is not bound to any namespace", prefix, value)
+	}
+
+	return xml.Name{Space: ns, Local: local}, nil
+}
+
+// xsdChildren returns children from XML Schema namespace, skipping annotations.
+func (n *node) xsdChildren() []*node {
+	children := make([]*node, 0, len(n.children))
+	for _, child := range n.children {
+		if child.name.Space == Namespace && child.name.Local != "annotation" {
+			children = append(children, child)
+		}
+	}
+
+	return children
+}
+
+// child returns first child from XML Schema namespace with given local name.
+func (n *node) child(local string) *node {
+	for _, c := range n.xsdChildren() {
+		if c.name.Local == local {
+			return c
+		}
+	}
+
+	return nil
+}
+
+func formatName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+
+	return "{" + name.Space + "}" + name.Local
+}
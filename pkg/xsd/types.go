@@ -0,0 +1,627 @@
+package xsd
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// variety describes kind of simple type.
+type variety int
+
+const (
+	atomic variety = iota
+	list
+	union
+)
+
+// whiteSpace values, as defined by whiteSpace facet.
+const (
+	preserve = "preserve"
+	replace  = "replace"
+	collapse = "collapse"
+)
+
+// simpleType is type of attributes and elements without children.
+type simpleType struct {
+	name    xml.Name
+	variety variety
+
+	// builtin is local name of built-in type, set only for built-in types.
+	builtin string
+
+	// base is type restricted by this type, nil only for anySimpleType.
+	base *simpleType
+
+	item    *simpleType
+	members []*simpleType
+	facets  facets
+}
+
+// facets are constraining facets of restriction.
+type facets struct {
+	enumeration []string
+	patterns    []*regexp.Regexp
+	length      *int
+	minLength   *int
+	maxLength   *int
+
+	minInclusive *string
+	maxInclusive *string
+	minExclusive *string
+	maxExclusive *string
+
+	totalDigits    *int
+	fractionDigits *int
+	whiteSpace     string
+}
+
+// primitive returns name of built-in type from which type is derived, for example: decimal for xs:int.
+func (t *simpleType) primitive() string {
+	for c := t; c != nil; c = c.base {
+		if c.builtin != "" {
+			return c.builtin
+		}
+	}
+
+	return "anySimpleType"
+}
+
+// whiteSpace returns whiteSpace facet value in force for type.
+func (t *simpleType) whiteSpace() string {
+	if t.variety != atomic {
+		return collapse
+	}
+
+	for c := t; c != nil; c = c.base {
+		if c.facets.whiteSpace != "" {
+			return c.facets.whiteSpace
+		}
+
+		switch c.builtin {
+		case "string", "anySimpleType":
+			return preserve
+		case "normalizedString":
+			return replace
+		case "":
+		default:
+			return collapse
+		}
+	}
+
+	return preserve
+}
+
+// validate checks whether value belongs to value space of type.
+func (t *simpleType) validate(value string) error {
+	value = normalizeWhiteSpace(value, t.whiteSpace())
+
+	switch t.variety {
+	case list:
+		if t.base != nil && t.base.variety == list {
+			if err := t.base.validate(value); err != nil {
+				return err
+			}
+		} else {
+			for _, item := range strings.Fields(value) {
+				if err := t.item.validate(item); err != nil {
+					return fmt.Errorf("list item %s", err)
+				}
+			}
+		}
+	case union:
+		if t.base != nil && t.base.variety == union {
+			if err := t.base.validate(value); err != nil {
+				return err
+			}
+		} else if !t.matchesMember(value) {
+			return fmt.Errorf("value '%s' does not match any member type of union %s", value, t.describe())
+		}
+	default:
+		if t.builtin != "" {
+			if err := checkBuiltin(t.builtin, value); err != nil {
+				return err
+			}
+		}
+
+		if t.base != nil {
+			if err := t.base.validate(value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return t.checkFacets(value)
+}
+
+func (t *simpleType) matchesMember(value string) bool {
+	for _, member := range t.members {
+		if member.validate(value) == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// describe returns name of type or description of anonymous type.
+func (t *simpleType) describe() string {
+	if t.name.Local != "" {
+		return t.name.Local
+	}
+
+	return "anonymous type"
+}
+
+// checkFacets checks value against facets of type, without facets of its base types.
+func (t *simpleType) checkFacets(value string) error {
+	f := t.facets
+
+	if len(f.enumeration) > 0 && !containsString(f.enumeration, value) {
+		return fmt.Errorf("value '%s' is not one of enumerated values: %s", value, strings.Join(f.enumeration, ", "))
+	}
+
+	if len(f.patterns) > 0 {
+		matched := false
+		for _, pattern := range f.patterns {
+			if pattern.MatchString(value) {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return fmt.Errorf("value '%s' does not match pattern %s", value, describePatterns(f.patterns))
+		}
+	}
+
+	if f.length != nil || f.minLength != nil || f.maxLength != nil {
+		length := t.valueLength(value)
+		if f.length != nil && length != *f.length {
+			return fmt.Errorf("value '%s' has length %d, expected %d", value, length, *f.length)
+		}
+
+		if f.minLength != nil && length < *f.minLength {
+			return fmt.Errorf("value '%s' has length %d, expected at least %d", value, length, *f.minLength)
+		}
+
+		if f.maxLength != nil && length > *f.maxLength {
+			return fmt.Errorf("value '%s' has length %d, expected at most %d", value, length, *f.maxLength)
+		}
+	}
+
+	bounds := []struct {
+		limit *string
+		ok    func(cmp int) bool
+		desc  string
+	}{
+		{f.minInclusive, func(cmp int) bool { return cmp >= 0 }, "greater than or equal to"},
+		{f.maxInclusive, func(cmp int) bool { return cmp <= 0 }, "less than or equal to"},
+		{f.minExclusive, func(cmp int) bool { return cmp > 0 }, "greater than"},
+		{f.maxExclusive, func(cmp int) bool { return cmp < 0 }, "less than"},
+	}
+	for _, bound := range bounds {
+		if bound.limit == nil {
+			continue
+		}
+
+		cmp, err := compareValues(t.primitive(), value, *bound.limit)
+		if err != nil {
+			return err
+		}
+
+		if !bound.ok(cmp) {
+			return fmt.Errorf("value '%s' should be %s %s", value, bound.desc, *bound.limit)
+		}
+	}
+
+	if f.totalDigits != nil || f.fractionDigits != nil {
+		total, fraction := digits(value)
+		if f.totalDigits != nil && total > *f.totalDigits {
+			return fmt.Errorf("value '%s' has %d digits, expected at most %d", value, total, *f.totalDigits)
+		}
+
+		if f.fractionDigits != nil && fraction > *f.fractionDigits {
+			return fmt.Errorf("value '%s' has %d fraction digits, expected at most %d", value, fraction, *f.fractionDigits)
+		}
+	}
+
+	return nil
+}
+
+// valueLength returns length of value in units defined by its type: list items, octets or characters.
+func (t *simpleType) valueLength(value string) int {
+	if t.variety == list {
+		return len(strings.Fields(value))
+	}
+
+	switch t.primitive() {
+	case "hexBinary":
+		return len(value) / 2
+	case "base64Binary":
+		decoded, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(value), ""))
+		if err != nil {
+			return 0
+		}
+
+		return len(decoded)
+	default:
+		return utf8.RuneCountInString(value)
+	}
+}
+
+func normalizeWhiteSpace(value, mode string) string {
+	switch mode {
+	case replace:
+		return strings.NewReplacer("\t", " ", "\n", " ", "\r", " ").Replace(value)
+	case collapse:
+		return strings.Join(strings.Fields(value), " ")
+	default:
+		return value
+	}
+}
+
+func describePatterns(patterns []*regexp.Regexp) string {
+	descriptions := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		p := strings.TrimSuffix(strings.TrimPrefix(pattern.String(), "^(?:"), ")$")
+		descriptions = append(descriptions, "'"+p+"'")
+	}
+
+	return strings.Join(descriptions, " or ")
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// digits returns number of total and fraction digits of decimal value.
+func digits(value string) (int, int) {
+	value = strings.TrimLeft(value, "+-")
+	integer, fraction := value, ""
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		integer, fraction = value[:i], value[i+1:]
+	}
+
+	integer = strings.TrimLeft(integer, "0")
+	fraction = strings.TrimRight(fraction, "0")
+
+	return len(integer) + len(fraction), len(fraction)
+}
+
+// builtinTypes maps names of supported built-in types to names of their base types.
+var builtinTypes = map[string]string{
+	"anySimpleType":      "",
+	"string":             "anySimpleType",
+	"boolean":            "anySimpleType",
+	"decimal":            "anySimpleType",
+	"float":              "anySimpleType",
+	"double":             "anySimpleType",
+	"duration":           "anySimpleType",
+	"dateTime":           "anySimpleType",
+	"time":               "anySimpleType",
+	"date":               "anySimpleType",
+	"gYearMonth":         "anySimpleType",
+	"gYear":              "anySimpleType",
+	"gMonthDay":          "anySimpleType",
+	"gDay":               "anySimpleType",
+	"gMonth":             "anySimpleType",
+	"hexBinary":          "anySimpleType",
+	"base64Binary":       "anySimpleType",
+	"anyURI":             "anySimpleType",
+	"QName":              "anySimpleType",
+	"NOTATION":           "anySimpleType",
+	"normalizedString":   "string",
+	"token":              "normalizedString",
+	"language":           "token",
+	"NMTOKEN":            "token",
+	"Name":               "token",
+	"NCName":             "Name",
+	"ID":                 "NCName",
+	"IDREF":              "NCName",
+	"ENTITY":             "NCName",
+	"integer":            "decimal",
+	"nonPositiveInteger": "integer",
+	"negativeInteger":    "nonPositiveInteger",
+	"long":               "integer",
+	"int":                "long",
+	"short":              "int",
+	"byte":               "short",
+	"nonNegativeInteger": "integer",
+	"unsignedLong":       "nonNegativeInteger",
+	"unsignedInt":        "unsignedLong",
+	"unsignedShort":      "unsignedInt",
+	"unsignedByte":       "unsignedShort",
+	"positiveInteger":    "nonNegativeInteger",
+}
+
+// builtinLists maps names of built-in list types to names of their item types.
+var builtinLists = map[string]string{
+	"NMTOKENS": "NMTOKEN",
+	"IDREFS":   "IDREF",
+	"ENTITIES": "ENTITY",
+}
+
+// integerRanges holds inclusive ranges of built-in integer types, empty bound means no limit.
+var integerRanges = map[string][2]string{
+	"nonPositiveInteger": {"", "0"},
+	"negativeInteger":    {"", "-1"},
+	"long":               {"-9223372036854775808", "9223372036854775807"},
+	"int":                {"-2147483648", "2147483647"},
+	"short":              {"-32768", "32767"},
+	"byte":               {"-128", "127"},
+	"nonNegativeInteger": {"0", ""},
+	"unsignedLong":       {"0", "18446744073709551615"},
+	"unsignedInt":        {"0", "4294967295"},
+	"unsignedShort":      {"0", "65535"},
+	"unsignedByte":       {"0", "255"},
+	"positiveInteger":    {"1", ""},
+}
+
+var (
+	decimalRegExp  = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)$`)
+	integerRegExp  = regexp.MustCompile(`^[+-]?\d+$`)
+	durationRegExp = regexp.MustCompile(`^-?P(\d+Y)?(\d+M)?(\d+D)?(T(\d+H)?(\d+M)?(\d+(\.\d+)?S)?)?$`)
+	timezone       = `(Z|[+-]\d{2}:\d{2})?`
+	dateRegExps    = map[string]*regexp.Regexp{
+		"gYearMonth": regexp.MustCompile(`^-?\d{4,}-\d{2}` + timezone + `$`),
+		"gYear":      regexp.MustCompile(`^-?\d{4,}` + timezone + `$`),
+		"gMonthDay":  regexp.MustCompile(`^--\d{2}-\d{2}` + timezone + `$`),
+		"gDay":       regexp.MustCompile(`^---\d{2}` + timezone + `$`),
+		"gMonth":     regexp.MustCompile(`^--\d{2}` + timezone + `$`),
+	}
+	endOfDayRegExp = regexp.MustCompile(`^(.*T)?24:00:00(\.0+)?` + timezone + `$`)
+	languageRegExp = regexp.MustCompile(`^[a-zA-Z]{1,8}(-[a-zA-Z0-9]{1,8})*$`)
+	nmTokenRegExp  = regexp.MustCompile(`^[\pL\pN._:\-\x{B7}]+$`)
+	nameRegExp     = regexp.MustCompile(`^[\pL_:][\pL\pN._:\-\x{B7}]*$`)
+	ncNameRegExp   = regexp.MustCompile(`^[\pL_][\pL\pN._\-\x{B7}]*$`)
+)
+
+// timeLayouts are layouts of date and time types, without timezone.
+var timeLayouts = map[string]string{
+	"dateTime": "2006-01-02T15:04:05",
+	"date":     "2006-01-02",
+	"time":     "15:04:05",
+}
+
+// checkBuiltin checks whether value belongs to lexical space of built-in type.
+func checkBuiltin(builtin, value string) error {
+	invalid := func() error {
+		return fmt.Errorf("value '%s' is not valid %s", value, builtin)
+	}
+
+	switch builtin {
+	case "boolean":
+		if !containsString([]string{"true", "false", "1", "0"}, value) {
+			return invalid()
+		}
+	case "decimal":
+		if !decimalRegExp.MatchString(value) {
+			return invalid()
+		}
+	case "float", "double":
+		if !containsString([]string{"INF", "-INF", "+INF", "NaN"}, value) {
+			if _, err := strconv.ParseFloat(value, 64); err != nil || strings.ContainsAny(value, "xXpP_") {
+				return invalid()
+			}
+		}
+	case "duration":
+		if !durationRegExp.MatchString(value) || strings.HasSuffix(value, "P") || strings.HasSuffix(value, "T") {
+			return invalid()
+		}
+	case "dateTime", "date", "time":
+		if _, err := parseTime(builtin, value); err != nil {
+			return invalid()
+		}
+	case "gYearMonth", "gYear", "gMonthDay", "gDay", "gMonth":
+		if !dateRegExps[builtin].MatchString(value) {
+			return invalid()
+		}
+	case "hexBinary":
+		if _, err := hex.DecodeString(value); err != nil {
+			return invalid()
+		}
+	case "base64Binary":
+		if _, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(value), "")); err != nil {
+			return invalid()
+		}
+	case "anyURI":
+		if _, err := url.Parse(value); err != nil {
+			return invalid()
+		}
+	case "QName", "NOTATION":
+		parts := strings.Split(value, ":")
+		if len(parts) > 2 {
+			return invalid()
+		}
+
+		for _, part := range parts {
+			if !ncNameRegExp.MatchString(part) {
+				return invalid()
+			}
+		}
+	case "normalizedString":
+		if strings.ContainsAny(value, "\t\n\r") {
+			return invalid()
+		}
+	case "language":
+		if !languageRegExp.MatchString(value) {
+			return invalid()
+		}
+	case "NMTOKEN":
+		if !nmTokenRegExp.MatchString(value) {
+			return invalid()
+		}
+	case "Name":
+		if !nameRegExp.MatchString(value) {
+			return invalid()
+		}
+	case "NCName", "ID", "IDREF", "ENTITY":
+		if !ncNameRegExp.MatchString(value) {
+			return invalid()
+		}
+	case "integer":
+		if !integerRegExp.MatchString(value) {
+			return invalid()
+		}
+	default:
+		if r, ok := integerRanges[builtin]; ok {
+			if !integerRegExp.MatchString(value) {
+				return invalid()
+			}
+
+			n, _ := new(big.Int).SetString(strings.TrimPrefix(value, "+"), 10)
+			if r[0] != "" {
+				if min, _ := new(big.Int).SetString(r[0], 10); n.Cmp(min) < 0 {
+					return fmt.Errorf("value '%s' is out of range of %s", value, builtin)
+				}
+			}
+
+			if r[1] != "" {
+				if max, _ := new(big.Int).SetString(r[1], 10); n.Cmp(max) > 0 {
+					return fmt.Errorf("value '%s' is out of range of %s", value, builtin)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseTime parses value of dateTime, date or time type, timezone is optional.
+// Hour 24 is allowed only as 24:00:00 and means midnight at the end of the day.
+func parseTime(builtin, value string) (time.Time, error) {
+	if builtin != "date" {
+		if matches := endOfDayRegExp.FindStringSubmatch(value); matches != nil && (builtin == "dateTime") == (matches[1] != "") {
+			t, err := parseTime(builtin, matches[1]+"00:00:00"+matches[3])
+			if err != nil || builtin == "time" {
+				return t, err
+			}
+
+			return t.AddDate(0, 0, 1), nil
+		}
+	}
+
+	layout := timeLayouts[builtin]
+	for _, suffix := range []string{"", "Z07:00"} {
+		for _, fraction := range []string{"", ".999999999"} {
+			if builtin == "date" && fraction != "" {
+				continue
+			}
+
+			if t, err := time.Parse(layout+fraction+suffix, value); err == nil {
+				return t, nil
+			}
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("value '%s' is not valid %s", value, builtin)
+}
+
+// compareValues compares values of primitive type, returns -1, 0 or 1 like strings.Compare.
+func compareValues(primitive, a, b string) (int, error) {
+	switch primitive {
+	case "decimal", "float", "double":
+		x, okX := new(big.Float).SetString(a)
+		y, okY := new(big.Float).SetString(b)
+		if !okX || !okY {
+			return 0, fmt.Errorf("could not compare '%s' and '%s' as numbers", a, b)
+		}
+
+		return x.Cmp(y), nil
+	case "dateTime", "date", "time":
+		x, errX := parseTime(primitive, a)
+		y, errY := parseTime(primitive, b)
+		if errX != nil || errY != nil {
+			return 0, fmt.Errorf("could not compare '%s' and '%s' as %s", a, b, primitive)
+		}
+
+		switch {
+		case x.Before(y):
+			return -1, nil
+		case x.After(y):
+			return 1, nil
+		default:
+			return 0, nil
+		}
+	default:
+		return strings.Compare(a, b), nil
+	}
+}
+
+// compilePattern translates XML Schema regular expression to anchored Go regular expression.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			escaped := pattern[i]
+			var class string
+			switch escaped {
+			case 'i':
+				class = `\pL_:`
+			case 'c':
+				class = `\pL\pN._:\-\x{B7}`
+			case 'I', 'C':
+				if inClass {
+					return nil, fmt.Errorf("pattern '%s': negated \\%c in character class is not supported", pattern, escaped)
+				}
+
+				lower := map[byte]string{'I': `\pL_:`, 'C': `\pL\pN._:\-\x{B7}`}
+				sb.WriteString("[^" + lower[escaped] + "]")
+				continue
+			default:
+				sb.WriteByte('\\')
+				sb.WriteByte(escaped)
+				continue
+			}
+
+			if inClass {
+				sb.WriteString(class)
+			} else {
+				sb.WriteString("[" + class + "]")
+			}
+		case c == '[':
+			if inClass {
+				return nil, fmt.Errorf("pattern '%s': character class subtraction is not supported", pattern)
+			}
+
+			inClass = true
+			sb.WriteByte(c)
+		case c == ']':
+			inClass = false
+			sb.WriteByte(c)
+		case c == '-' && inClass && i+1 < len(pattern) && pattern[i+1] == '[':
+			return nil, fmt.Errorf("pattern '%s': character class subtraction is not supported", pattern)
+		case (c == '^' || c == '$') && !inClass:
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	re, err := regexp.Compile("^(?:" + sb.String() + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern '%s', err: %w", pattern, err)
+	}
+
+	return re, nil
+}
@@ -0,0 +1,418 @@
+package xsd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// Error describes single violation of schema found in document.
+type Error struct {
+	// Path points at element or attribute, for example: /order/item[2]/@id.
+	Path string
+
+	// Message describes violation.
+	Message string
+}
+
+// Error returns path followed by description of violation.
+func (e Error) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// Errors are violations of schema found in document, in document order.
+type Errors []Error
+
+// Error returns violations, one per line.
+func (e Errors) Error() string {
+	lines := make([]string, 0, len(e))
+	for _, err := range e {
+		lines = append(lines, err.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Validate validates XML document against schema. Root element of document should be declared as global element.
+// Violations of schema are returned as Errors.
+func (s *Schema) Validate(document []byte) error {
+	root, err := parseTree(document)
+	if err != nil {
+		return err
+	}
+
+	v := validation{s: s}
+	path := "/" + root.name.Local
+	if el, ok := s.elements[root.name]; ok {
+		v.element(root, el, path)
+	} else {
+		v.errorf(path, "element %s is not declared in schema", formatName(root.name))
+	}
+
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+
+	return nil
+}
+
+// validation collects violations of schema found in single document.
+type validation struct {
+	s    *Schema
+	errs Errors
+}
+
+func (v *validation) errorf(path, format string, args ...interface{}) {
+	v.errs = append(v.errs, Error{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validation) element(n *node, el *element, path string) {
+	if el.abstract {
+		v.errorf(path, "element %s is abstract and should be substituted", formatName(el.name))
+		return
+	}
+
+	typ := el.typ
+	if xsiType, ok := n.attr(InstanceNamespace, "type"); ok {
+		name, err := n.qname(xsiType)
+		if err == nil {
+			typ, err = v.s.typeDefinition(name)
+		}
+
+		if err != nil {
+			v.errorf(path, "xsi:type '%s' is invalid, err: %v", xsiType, err)
+			return
+		}
+	}
+
+	isNil := false
+	if xsiNil, ok := n.attr(InstanceNamespace, "nil"); ok && (xsiNil == "true" || xsiNil == "1") {
+		if !el.nillable {
+			v.errorf(path, "element %s is not nillable", formatName(el.name))
+			return
+		}
+
+		if len(n.children) > 0 || n.hasText {
+			v.errorf(path, "element with xsi:nil should be empty")
+		}
+
+		isNil = true
+	}
+
+	switch t := typ.(type) {
+	case *simpleType:
+		for _, attr := range n.attrs {
+			if attr.Name.Space != InstanceNamespace {
+				v.errorf(path+"/@"+attr.Name.Local, "attribute %s is not allowed", formatName(attr.Name))
+			}
+		}
+
+		if len(n.children) > 0 {
+			v.errorf(path, "element of simple type %s should not have child elements", t.describe())
+		} else if !isNil {
+			v.value(path, t, n.text, el.fixed)
+		}
+	case *complexType:
+		v.attributes(n, t, path)
+		if isNil {
+			return
+		}
+
+		if t.simple != nil {
+			if len(n.children) > 0 {
+				v.errorf(path, "element with simple content should not have child elements")
+			} else {
+				v.value(path, t.simple, n.text, el.fixed)
+			}
+
+			return
+		}
+
+		if !t.mixed && n.hasText {
+			v.errorf(path, "text content is not allowed, found '%s'", strings.TrimSpace(n.text))
+		}
+
+		v.content(n, t, path)
+	}
+}
+
+func (v *validation) value(path string, t *simpleType, value string, fixed *string) {
+	if err := t.validate(value); err != nil {
+		v.errorf(path, "%v", err)
+		return
+	}
+
+	if fixed != nil {
+		ws := t.whiteSpace()
+		if normalizeWhiteSpace(value, ws) != normalizeWhiteSpace(*fixed, ws) {
+			v.errorf(path, "value '%s' should be equal to fixed value '%s'", value, *fixed)
+		}
+	}
+}
+
+func (v *validation) attributes(n *node, t *complexType, path string) {
+	present := map[xml.Name]bool{}
+	for _, attr := range n.attrs {
+		if attr.Name.Space == InstanceNamespace {
+			continue
+		}
+
+		present[attr.Name] = true
+		attrPath := path + "/@" + attr.Name.Local
+
+		use := t.attributeUse(attr.Name)
+		if use == nil || use.prohibited {
+			if t.anyAttribute == nil || !t.anyAttribute.allows(attr.Name) || (use != nil && use.prohibited) {
+				v.errorf(attrPath, "attribute %s is not allowed", formatName(attr.Name))
+				continue
+			}
+
+			global, declared := v.s.attributes[attr.Name]
+			switch {
+			case declared && t.anyAttribute.processContents != "skip":
+				v.value(attrPath, global.typ, attr.Value, global.fixed)
+			case !declared && t.anyAttribute.processContents == "strict":
+				v.errorf(attrPath, "attribute %s is not declared in schema", formatName(attr.Name))
+			}
+
+			continue
+		}
+
+		fixed := use.fixed
+		if fixed == nil {
+			fixed = use.attribute.fixed
+		}
+
+		v.value(attrPath, use.attribute.typ, attr.Value, fixed)
+	}
+
+	for _, use := range t.attributes {
+		if use.required && !present[use.attribute.name] {
+			v.errorf(path, "required attribute %s is missing", formatName(use.attribute.name))
+		}
+	}
+}
+
+func (t *complexType) attributeUse(name xml.Name) *attributeUse {
+	for _, use := range t.attributes {
+		if use.attribute.name == name {
+			return use
+		}
+	}
+
+	return nil
+}
+
+func (v *validation) content(n *node, t *complexType, path string) {
+	m := matcher{s: v.s, children: n.children, assigned: make([]assignment, len(n.children))}
+
+	end, ok := 0, true
+	if t.content != nil {
+		end, ok = m.particle(t.content, 0)
+	}
+
+	if !ok || end < len(n.children) {
+		at := m.furthest
+		if at < end {
+			at = end
+		}
+
+		expected := ""
+		if len(m.expected) > 0 {
+			expected = ", expected: " + strings.Join(m.expected, ", ")
+		}
+
+		if at < len(n.children) {
+			v.errorf(childPath(n, at, path), "unexpected element %s%s", formatName(n.children[at].name), expected)
+		} else {
+			v.errorf(path, "missing child element%s", expected)
+		}
+
+		return
+	}
+
+	for i, child := range n.children {
+		a := m.assigned[i]
+		p := childPath(n, i, path)
+		if a.element != nil {
+			v.element(child, a.element, p)
+			continue
+		}
+
+		global, declared := v.s.elements[child.name]
+		switch {
+		case declared && a.wildcard.processContents != "skip":
+			v.element(child, global, p)
+		case !declared && a.wildcard.processContents == "strict":
+			v.errorf(p, "element %s is not declared in schema", formatName(child.name))
+		}
+	}
+}
+
+// childPath returns path of i-th child, with position among siblings of the same name when there are more of them.
+func childPath(parent *node, i int, path string) string {
+	name := parent.children[i].name
+	position, count := 0, 0
+	for j, sibling := range parent.children {
+		if sibling.name == name {
+			count++
+			if j <= i {
+				position++
+			}
+		}
+	}
+
+	if count > 1 {
+		return fmt.Sprintf("%s/%s[%d]", path, name.Local, position)
+	}
+
+	return path + "/" + name.Local
+}
+
+// assignment is declaration or wildcard matched with child element.
+type assignment struct {
+	element  *element
+	wildcard *wildcard
+}
+
+// matcher matches child elements with content model. Particles are matched greedily, which is sufficient
+// for schemas satisfying Unique Particle Attribution constraint.
+type matcher struct {
+	s        *Schema
+	children []*node
+	assigned []assignment
+
+	// furthest is furthest position at which matching failed and expected are particles tried at it.
+	furthest int
+	expected []string
+}
+
+func (m *matcher) expect(pos int, description string) {
+	if pos > m.furthest {
+		m.furthest = pos
+		m.expected = nil
+	}
+
+	if pos == m.furthest && !containsString(m.expected, description) {
+		m.expected = append(m.expected, description)
+	}
+}
+
+// particle matches particle with its occurrence constraints at pos, returns position after matched children.
+func (m *matcher) particle(p *particle, pos int) (int, bool) {
+	count := 0
+	for p.max == unbounded || count < p.max {
+		next, ok := m.term(p, pos)
+		if !ok {
+			break
+		}
+
+		if next == pos {
+			count = p.min
+			break
+		}
+
+		pos = next
+		count++
+	}
+
+	return pos, count >= p.min
+}
+
+// term matches single occurrence of particle at pos.
+func (m *matcher) term(p *particle, pos int) (int, bool) {
+	switch p.kind {
+	case elementParticle:
+		if pos < len(m.children) {
+			if el := m.substitute(p.element, m.children[pos].name); el != nil {
+				m.assigned[pos] = assignment{element: el}
+				return pos + 1, true
+			}
+		}
+
+		m.expect(pos, formatName(p.element.name))
+	case anyParticle:
+		if pos < len(m.children) && p.wildcard.allows(m.children[pos].name) {
+			m.assigned[pos] = assignment{wildcard: p.wildcard}
+			return pos + 1, true
+		}
+
+		m.expect(pos, "any element")
+	case sequenceParticle:
+		next := pos
+		for _, child := range p.children {
+			var ok bool
+			if next, ok = m.particle(child, next); !ok {
+				return pos, false
+			}
+		}
+
+		return next, true
+	case choiceParticle:
+		matchedEmpty := false
+		for _, child := range p.children {
+			next, ok := m.particle(child, pos)
+			if ok && next > pos {
+				return next, true
+			}
+
+			matchedEmpty = matchedEmpty || ok
+		}
+
+		return pos, matchedEmpty
+	case allParticle:
+		return m.all(p, pos)
+	}
+
+	return pos, false
+}
+
+// all matches children of all group in any order.
+func (m *matcher) all(p *particle, pos int) (int, bool) {
+	used := make([]bool, len(p.children))
+	for pos < len(m.children) {
+		found := false
+		for i, child := range p.children {
+			if used[i] {
+				continue
+			}
+
+			if next, ok := m.term(child, pos); ok && next > pos {
+				used[i], found, pos = true, true, next
+				break
+			}
+		}
+
+		if !found {
+			break
+		}
+	}
+
+	for i, child := range p.children {
+		if !used[i] && child.min > 0 {
+			m.expect(pos, formatName(child.element.name))
+			return pos, false
+		}
+	}
+
+	return pos, true
+}
+
+// substitute returns declaration of element with given name, which may be el or member of its substitution group.
+func (m *matcher) substitute(el *element, name xml.Name) *element {
+	if el.name == name {
+		return el
+	}
+
+	if m.s.elements[el.name] != el {
+		return nil
+	}
+
+	for _, member := range m.s.substitutes[el.name] {
+		if found := m.substitute(member, name); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package xsd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const validOrder = `<?xml version="1.0" encoding="UTF-8"?>
+<order xmlns="urn:shop" xmlns:a="urn:address" id="ORD-0001" currency="EUR">
+	<customer>Jane</customer>
+	<a:address><a:city>Berlin</a:city><a:street>Main 1</a:street></a:address>
+	<item>
+		<sku> AB-1 </sku>
+		<quantity>2</quantity>
+		<price currency="USD">10.50</price>
+		<tags>new sale</tags>
+		<discount xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"/>
+	</item>
+	<note>leave at the door</note>
+	<tracking xmlns="urn:tracking">123</tracking>
+</order>`
+
+func TestSchema_Validate(t *testing.T) {
+	s, err := Load("testdata/order.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		document string
+		wantErrs []string
+	}{
+		{name: "valid document", document: validOrder},
+		{name: "substitution group member", document: strings.Replace(validOrder,
+			`<a:address><a:city>Berlin</a:city><a:street>Main 1</a:street></a:address>`,
+			`<a:postalAddress zip="10115"><a:street>Main 1</a:street><a:city>Berlin</a:city></a:postalAddress>`, 1)},
+		{name: "undeclared root", document: `<invoice xmlns="urn:shop"/>`,
+			wantErrs: []string{"/invoice: element {urn:shop}invoice is not declared in schema"}},
+		{name: "invalid simple values", document: strings.NewReplacer(
+			`id="ORD-0001"`, `id="1"`,
+			`<quantity>2</quantity>`, `<quantity>0</quantity>`,
+			`10.50`, `10.505`,
+			`new sale`, `new 1sale`,
+		).Replace(validOrder), wantErrs: []string{
+			"/order/@id: value '1' does not match pattern 'ORD-\\d{4}'",
+			"/order/item/quantity: value '0' is out of range of positiveInteger",
+			"/order/item/price: value '10.505' has 3 fraction digits, expected at most 2",
+			"/order/item/tags: list item value '1sale' is not valid NCName",
+		}},
+		{name: "attributes", document: strings.NewReplacer(
+			`id="ORD-0001" currency="EUR"`, `currency="PLN" extra="1"`,
+			`<price currency="USD">`, `<price currency="GBP">`,
+		).Replace(validOrder), wantErrs: []string{
+			"/order/@currency: value 'PLN' should be equal to fixed value 'EUR'",
+			"/order/@extra: attribute extra is not allowed",
+			"/order: required attribute id is missing",
+			"/order/item/price/@currency: value 'GBP' is not one of enumerated values: EUR, USD",
+		}},
+		{name: "unexpected element", document: strings.Replace(validOrder, `<note>leave at the door</note>`,
+			`<note>a</note><gift>true</gift>`, 1), wantErrs: []string{
+			"/order/gift: unexpected element {urn:shop}gift, expected: any element",
+		}},
+		{name: "missing element", document: strings.Replace(validOrder, `<price currency="USD">10.50</price>`, ``, 1),
+			wantErrs: []string{"/order/item/tags: unexpected element {urn:shop}tags, expected: {urn:shop}price"}},
+		{name: "missing element of all group", document: strings.Replace(validOrder, `<a:street>Main 1</a:street>`, ``, 1),
+			wantErrs: []string{"/order/address: missing child element, expected: {urn:address}street"}},
+		{name: "unqualified element", document: strings.Replace(validOrder, `<customer>Jane</customer>`,
+			`<customer xmlns="">Jane</customer>`, 1), wantErrs: []string{
+			"/order/customer: unexpected element customer, expected: {urn:shop}customer",
+		}},
+		{name: "not nillable", document: strings.Replace(validOrder, `<quantity>2</quantity>`,
+			`<quantity xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"/>`, 1), wantErrs: []string{
+			"/order/item/quantity: element {urn:shop}quantity is not nillable",
+		}},
+		{name: "repeated elements", document: strings.Replace(validOrder, `</item>`,
+			`</item><item><sku>A</sku><quantity>1</quantity><price currency="EUR">1</price></item>`, 1), wantErrs: []string{
+			"/order/item[2]/sku: value 'A' has length 1, expected at least 3",
+		}},
+		{name: "text in element only content", document: strings.Replace(validOrder, `<customer>`, `text<customer>`, 1),
+			wantErrs: []string{"/order: text content is not allowed, found 'text'"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Validate([]byte(tt.document))
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+
+				return
+			}
+
+			var errs Errors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Validate() error = %v, want Errors", err)
+			}
+
+			if got := strings.Split(errs.Error(), "\n"); strings.Join(got, "\n") != strings.Join(tt.wantErrs, "\n") {
+				t.Errorf("Validate() errors =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.wantErrs, "\n"))
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	schema := `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:a="urn:address">
+		<xs:import namespace="urn:address" schemaLocation="common/address.xsd"/>
+		<xs:element name="user">
+			<xs:complexType>
+				<xs:sequence>
+					<xs:element ref="a:address" minOccurs="0"/>
+				</xs:sequence>
+				<xs:attribute name="born" type="xs:date"/>
+				<xs:attribute name="score">
+					<xs:simpleType>
+						<xs:union memberTypes="xs:integer">
+							<xs:simpleType><xs:restriction base="xs:string"><xs:enumeration value="none"/></xs:restriction></xs:simpleType>
+						</xs:union>
+					</xs:simpleType>
+				</xs:attribute>
+			</xs:complexType>
+		</xs:element>
+	</xs:schema>`
+
+	s, err := Parse([]byte(schema), "testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = s.Validate([]byte(`<user born="2020-01-31" score="none"/>`)); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	if err = s.Validate([]byte(`<user born="2020-02-31" score="some"/>`)); err == nil {
+		t.Errorf("Validate() should return error for invalid date and union member")
+	}
+
+	invalidSchemas := map[string]string{
+		"unknown type":     `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"><xs:element name="a" type="b"/></xs:schema>`,
+		"invalid pattern":  `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"><xs:simpleType name="a"><xs:restriction base="xs:string"><xs:pattern value="[a-z-[aeiou]]"/></xs:restriction></xs:simpleType></xs:schema>`,
+		"missing include":  `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"><xs:include schemaLocation="missing.xsd"/></xs:schema>`,
+		"not a schema":     `<schema/>`,
+		"invalid occurs":   `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"><xs:group name="g"><xs:sequence minOccurs="2" maxOccurs="1"/></xs:group></xs:schema>`,
+		"duplicated types": `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"><xs:simpleType name="a"><xs:list itemType="xs:int"/></xs:simpleType><xs:complexType name="a"/></xs:schema>`,
+	}
+	for name, schema := range invalidSchemas {
+		if _, err = Parse([]byte(schema), "testdata"); err == nil {
+			t.Errorf("Parse() should return error for schema with %s", name)
+		}
+	}
+}
+
+func TestCheckBuiltin(t *testing.T) {
+	tests := []struct {
+		builtin string
+		valid   []string
+		invalid []string
+	}{
+		{builtin: "boolean", valid: []string{"true", "0"}, invalid: []string{"yes"}},
+		{builtin: "decimal", valid: []string{"-1.5", ".5", "+3"}, invalid: []string{"1e3", "."}},
+		{builtin: "double", valid: []string{"1e3", "INF", "NaN"}, invalid: []string{"0x1p3", "abc"}},
+		{builtin: "byte", valid: []string{"-128", "127"}, invalid: []string{"128", "1.0"}},
+		{builtin: "unsignedLong", valid: []string{"18446744073709551615"}, invalid: []string{"-1"}},
+		{builtin: "dateTime", valid: []string{"2021-05-01T10:00:00", "2021-05-01T10:00:00.5+02:00", "2020-01-01T24:00:00", "2020-01-01T24:00:00.0Z"}, invalid: []string{"2021-05-01", "2020-01-01T24:00:01", "2020-01-01T24:30:00"}},
+		{builtin: "time", valid: []string{"10:00:00", "24:00:00+02:00"}, invalid: []string{"24:00:00.5", "2020-01-01T24:00:00"}},
+		{builtin: "duration", valid: []string{"P1Y2M", "PT1.5S", "-P1D"}, invalid: []string{"P", "P1DT", "1D"}},
+		{builtin: "gYearMonth", valid: []string{"2021-05", "2021-05Z"}, invalid: []string{"2021"}},
+		{builtin: "hexBinary", valid: []string{"0FB7"}, invalid: []string{"0FB"}},
+		{builtin: "base64Binary", valid: []string{"YWJj"}, invalid: []string{"YWJ"}},
+		{builtin: "QName", valid: []string{"xs:string", "a"}, invalid: []string{"a:b:c", "1a"}},
+		{builtin: "language", valid: []string{"en-US"}, invalid: []string{"en_US"}},
+	}
+
+	for _, tt := range tests {
+		for _, value := range tt.valid {
+			if err := checkBuiltin(tt.builtin, value); err != nil {
+				t.Errorf("checkBuiltin(%s, %s) error = %v", tt.builtin, value, err)
+			}
+		}
+
+		for _, value := range tt.invalid {
+			if err := checkBuiltin(tt.builtin, value); err == nil {
+				t.Errorf("checkBuiltin(%s, %s) should return error", tt.builtin, value)
+			}
+		}
+	}
+}
@@ -2,6 +2,8 @@ package gdutils
 
 import (
 	"bytes"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
@@ -12,10 +14,12 @@ import (
 	"math/rand"
 	"mime/multipart"
 	"net/http"
+	"net/http/httptrace"
 	"os"
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -23,14 +27,25 @@ import (
 	"github.com/goccy/go-yaml"
 	"github.com/moul/http2curl"
 
+	"github.com/pawelWritesCode/gdutils/pkg/collection"
+	"github.com/pawelWritesCode/gdutils/pkg/compare"
+	"github.com/pawelWritesCode/gdutils/pkg/curl"
+	"github.com/pawelWritesCode/gdutils/pkg/expression"
 	"github.com/pawelWritesCode/gdutils/pkg/format"
 	"github.com/pawelWritesCode/gdutils/pkg/httpcache"
+	"github.com/pawelWritesCode/gdutils/pkg/httpctx"
+	"github.com/pawelWritesCode/gdutils/pkg/httpfile"
 	"github.com/pawelWritesCode/gdutils/pkg/mathutils"
 	"github.com/pawelWritesCode/gdutils/pkg/osutils"
+	"github.com/pawelWritesCode/gdutils/pkg/pagination"
+	"github.com/pawelWritesCode/gdutils/pkg/pathfinder"
 	"github.com/pawelWritesCode/gdutils/pkg/reflectutils"
+	"github.com/pawelWritesCode/gdutils/pkg/schema"
+	"github.com/pawelWritesCode/gdutils/pkg/snapshot"
 	"github.com/pawelWritesCode/gdutils/pkg/stringutils"
 	"github.com/pawelWritesCode/gdutils/pkg/timeutils"
 	"github.com/pawelWritesCode/gdutils/pkg/validator"
+	"github.com/pawelWritesCode/gdutils/pkg/xsd"
 )
 
 // BodyHeaders is entity that holds information about request body and request headers.
@@ -51,15 +66,17 @@ type BodyHeaders struct {
 	Argument "bodyTemplate" should contain data (may include template values)
 	in JSON or YAML format with keys "body" and "headers".
 */
-func (apiCtx *APIContext) ISendRequestToWithBodyAndHeaders(method, urlTemplate string, bodyTemplate string) error {
+func (apiCtx *APIContext) ISendRequestToWithBodyAndHeaders(method, urlTemplate string, bodyTemplate string) (err error) {
+	defer apiCtx.stepAnnotation()(&err)
+
 	input, err := apiCtx.TemplateEngine.Replace(bodyTemplate, apiCtx.Cache.All())
 	if err != nil {
-		return fmt.Errorf("template engine has problem with 'headers and body' template, err: %w", err)
+		return &TemplateError{Name: "headers and body", Err: err}
 	}
 
 	url, err := apiCtx.TemplateEngine.Replace(urlTemplate, apiCtx.Cache.All())
 	if err != nil {
-		return fmt.Errorf("template engine has problem with 'url' template, err: %w", err)
+		return &TemplateError{Name: "url", Err: err}
 	}
 
 	var bodyAndHeaders BodyHeaders
@@ -110,39 +127,87 @@ func (apiCtx *APIContext) ISendRequestToWithBodyAndHeaders(method, urlTemplate s
 		req.Header.Set(headerName, headerValue)
 	}
 
-	if apiCtx.Debugger.IsOn() {
-		command, _ := http2curl.GetCurlCommand(req)
-		apiCtx.Debugger.Print(command.String())
+	return apiCtx.sendRequest(req)
+}
+
+// IPrepareNewRequestToAndSaveItAs prepares new request and saves it in cache under cacheKey
+func (apiCtx *APIContext) IPrepareNewRequestToAndSaveItAs(method, urlTemplate, cacheKey string) (err error) {
+	defer apiCtx.stepAnnotation()(&err)
+
+	url, err := apiCtx.TemplateEngine.Replace(urlTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "url", Err: err}
 	}
 
-	apiCtx.Cache.Save(httpcache.LastHTTPRequestTimestamp, time.Now())
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return fmt.Errorf("can't create request due to err: %w", err)
+	}
+
+	apiCtx.Cache.Save(cacheKey, req)
+
+	return nil
+}
 
-	resp, err := apiCtx.RequestDoer.Do(req)
+// IPrepareNewRequestFromCurlCommandAndSaveItAs parses curl command and saves prepared request in cache under cacheKey.
+// curlTemplate may include template values. Supported curl options are: -X, -H, -d, --data-raw, --data-binary,
+// --data-urlencode, -F, -u, -b/--cookie, -G, -I and -k. Request prepared with -k is sent without TLS certificate
+// verification, which requires RequestDoer to be *http.Client.
+func (apiCtx *APIContext) IPrepareNewRequestFromCurlCommandAndSaveItAs(curlTemplate, cacheKey string) (err error) {
+	defer apiCtx.stepAnnotation()(&err)
+
+	command, err := apiCtx.TemplateEngine.Replace(curlTemplate, apiCtx.Cache.All())
 	if err != nil {
-		return fmt.Errorf("failed to send request %s %s, reason: %w", req.Method, req.URL.String(), err)
+		return &TemplateError{Name: "curl", Err: err}
 	}
 
-	apiCtx.Cache.Save(httpcache.LastHTTPResponseTimestamp, time.Now())
-	apiCtx.Cache.Save(httpcache.LastHTTPResponseCacheKey, resp)
+	cmd, err := curl.Parse(command)
+	if err != nil {
+		return fmt.Errorf("could not parse curl command, err: %w", err)
+	}
 
-	if apiCtx.Debugger.IsOn() {
-		respBody, _ := apiCtx.GetLastResponseBody()
-		apiCtx.Debugger.Print(fmt.Sprintf("%s %s response body (status code: %d):\n\n%s\n", req.Method, req.URL.String(), resp.StatusCode, respBody))
+	req, err := cmd.Request()
+	if err != nil {
+		return err
 	}
 
+	apiCtx.Cache.Save(cacheKey, req)
+
 	return nil
 }
 
-// IPrepareNewRequestToAndSaveItAs prepares new request and saves it in cache under cacheKey
-func (apiCtx *APIContext) IPrepareNewRequestToAndSaveItAs(method, urlTemplate, cacheKey string) error {
-	url, err := apiCtx.TemplateEngine.Replace(urlTemplate, apiCtx.Cache.All())
+// ILoadHTTPClientEnvironmentFromFile loads environment of given name from environment file in JetBrains HTTP Client
+// format (for example http-client.env.json) and saves all its variables in cache, so they may be used by requests
+// from .http files as {{variable}} and by other steps as template values.
+func (apiCtx *APIContext) ILoadHTTPClientEnvironmentFromFile(environment, pathTemplate string) (err error) {
+	defer apiCtx.stepAnnotation()(&err)
+
+	pth, err := apiCtx.TemplateEngine.Replace(pathTemplate, apiCtx.Cache.All())
 	if err != nil {
-		return fmt.Errorf("template engine has problem with 'url' template, err: %w", err)
+		return &TemplateError{Name: "path", Err: err}
 	}
 
-	req, err := http.NewRequest(method, url, nil)
+	variables, err := httpfile.LoadEnvironment(pth, environment)
 	if err != nil {
-		return fmt.Errorf("can't create request due to err: %w", err)
+		return err
+	}
+
+	for name, value := range variables {
+		apiCtx.Cache.Save(name, value)
+	}
+
+	return nil
+}
+
+// IPrepareNewRequestFromHTTPFileAndSaveItAs prepares request of given name from .http file (JetBrains HTTP Client
+// or VS Code REST Client format) and saves it in cache under cacheKey.
+// Placeholders {{variable}} are resolved from cache first and then from file variables defined as "@variable = value".
+func (apiCtx *APIContext) IPrepareNewRequestFromHTTPFileAndSaveItAs(requestName, pathTemplate, cacheKey string) (err error) {
+	defer apiCtx.stepAnnotation()(&err)
+
+	req, err := apiCtx.requestFromHTTPFile(requestName, pathTemplate)
+	if err != nil {
+		return err
 	}
 
 	apiCtx.Cache.Save(cacheKey, req)
@@ -150,12 +215,27 @@ func (apiCtx *APIContext) IPrepareNewRequestToAndSaveItAs(method, urlTemplate, c
 	return nil
 }
 
+// ISendRequestFromHTTPFile sends request of given name from .http file (JetBrains HTTP Client
+// or VS Code REST Client format). Placeholders are resolved as in IPrepareNewRequestFromHTTPFileAndSaveItAs.
+func (apiCtx *APIContext) ISendRequestFromHTTPFile(requestName, pathTemplate string) (err error) {
+	defer apiCtx.stepAnnotation()(&err)
+
+	req, err := apiCtx.requestFromHTTPFile(requestName, pathTemplate)
+	if err != nil {
+		return err
+	}
+
+	return apiCtx.sendRequest(req)
+}
+
 // ISetFollowingHeadersForPreparedRequest sets provided headers for previously prepared request.
 // incoming data should be in JSON or YAML format
-func (apiCtx *APIContext) ISetFollowingHeadersForPreparedRequest(cacheKey, headersTemplate string) error {
+func (apiCtx *APIContext) ISetFollowingHeadersForPreparedRequest(cacheKey, headersTemplate string) (err error) {
+	defer apiCtx.stepAnnotation()(&err)
+
 	headers, err := apiCtx.TemplateEngine.Replace(headersTemplate, apiCtx.Cache.All())
 	if err != nil {
-		return fmt.Errorf("template engine has problem with 'headers' template, err: %w", err)
+		return &TemplateError{Name: "headers", Err: err}
 	}
 
 	var headersMap map[string]string
@@ -190,10 +270,12 @@ func (apiCtx *APIContext) ISetFollowingHeadersForPreparedRequest(cacheKey, heade
 
 // ISetFollowingBodyForPreparedRequest sets body for previously prepared request
 // bodyTemplate may be in any format and accepts template values
-func (apiCtx *APIContext) ISetFollowingBodyForPreparedRequest(cacheKey, bodyTemplate string) error {
+func (apiCtx *APIContext) ISetFollowingBodyForPreparedRequest(cacheKey, bodyTemplate string) (err error) {
+	defer apiCtx.stepAnnotation()(&err)
+
 	body, err := apiCtx.TemplateEngine.Replace(bodyTemplate, apiCtx.Cache.All())
 	if err != nil {
-		return fmt.Errorf("template engine has problem with 'body' template, err: %w", err)
+		return &TemplateError{Name: "body", Err: err}
 	}
 
 	req, err := apiCtx.GetPreparedRequest(cacheKey)
@@ -209,12 +291,14 @@ func (apiCtx *APIContext) ISetFollowingBodyForPreparedRequest(cacheKey, bodyTemp
 
 // ISetFollowingCookiesForPreparedRequest sets cookies for previously prepared request.
 // cookiesTemplate should be YAML or JSON deserializable on []http.Cookie.
-func (apiCtx *APIContext) ISetFollowingCookiesForPreparedRequest(cacheKey, cookiesTemplate string) error {
+func (apiCtx *APIContext) ISetFollowingCookiesForPreparedRequest(cacheKey, cookiesTemplate string) (err error) {
+	defer apiCtx.stepAnnotation()(&err)
+
 	var cookies []http.Cookie
 
 	userCookies, err := apiCtx.TemplateEngine.Replace(cookiesTemplate, apiCtx.Cache.All())
 	if err != nil {
-		return fmt.Errorf("template engine has problem with 'cookies' template, err: %w", err)
+		return &TemplateError{Name: "cookies", Err: err}
 	}
 
 	req, err := apiCtx.GetPreparedRequest(cacheKey)
@@ -251,10 +335,12 @@ func (apiCtx *APIContext) ISetFollowingCookiesForPreparedRequest(cacheKey, cooki
 	Internally method sets proper Content-Type: multipart/form-data header.
 	formTemplate should be YAML or JSON deserializable on map[string]string.
 */
-func (apiCtx *APIContext) ISetFollowingFormForPreparedRequest(cacheKey, formTemplate string) error {
+func (apiCtx *APIContext) ISetFollowingFormForPreparedRequest(cacheKey, formTemplate string) (err error) {
+	defer apiCtx.stepAnnotation()(&err)
+
 	form, err := apiCtx.TemplateEngine.Replace(formTemplate, apiCtx.Cache.All())
 	if err != nil {
-		return fmt.Errorf("template engine has problem with 'form' template, err: %w", err)
+		return &TemplateError{Name: "form", Err: err}
 	}
 
 	req, err := apiCtx.GetPreparedRequest(cacheKey)
@@ -332,86 +418,309 @@ func (apiCtx *APIContext) ISetFollowingFormForPreparedRequest(cacheKey, formTemp
 }
 
 // ISendRequest sends previously prepared HTTP(s) request.
-func (apiCtx *APIContext) ISendRequest(cacheKey string) error {
+func (apiCtx *APIContext) ISendRequest(cacheKey string) (err error) {
+	defer apiCtx.stepAnnotation()(&err)
+
 	req, err := apiCtx.GetPreparedRequest(cacheKey)
 	if err != nil {
 		return fmt.Errorf("could not obtain prepared request, err: %w", err)
 	}
 
-	if apiCtx.Debugger.IsOn() {
-		command, _ := http2curl.GetCurlCommand(req)
-		apiCtx.Debugger.Print(command.String())
+	return apiCtx.sendRequest(req)
+}
+
+// IFollowPaginationOfPreparedRequestAndSaveNodesAs sends previously prepared request and follows pagination until the last page,
+// but no more than pageLimit pages. Nodes found on every page by exprTemplate, which should point at slice,
+// are collected into one slice and saved in cache under resultCacheKey.
+// paginationTemplate should be JSON or YAML deserializable on pagination.Config, for example:
+//
+//	{"strategy": "cursor", "cursor": "meta.next_cursor", "param": "cursor"}
+//
+// Afterwards, last HTTP(s) response is response of the last visited page. Reaching pageLimit before the last page
+// returns error, nodes collected so far are not saved.
+func (apiCtx *APIContext) IFollowPaginationOfPreparedRequestAndSaveNodesAs(cacheKey, paginationTemplate string, dataFormat format.DataFormat, exprTemplate string, pageLimit int, resultCacheKey string) (err error) {
+	defer apiCtx.stepAnnotation()(&err)
+
+	if pageLimit < 1 {
+		return fmt.Errorf("page limit should be greater than 0, got: %d", pageLimit)
 	}
 
-	apiCtx.Cache.Save(httpcache.LastHTTPRequestTimestamp, time.Now())
+	paginationConfig, err := apiCtx.TemplateEngine.Replace(paginationTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "pagination", Err: err}
+	}
 
-	resp, err := apiCtx.RequestDoer.Do(req)
+	expr, err := apiCtx.TemplateEngine.Replace(exprTemplate, apiCtx.Cache.All())
 	if err != nil {
-		return fmt.Errorf("failed to send request %s %s, reason: %w", req.Method, req.URL.String(), err)
+		return &TemplateError{Name: "expression", Err: err}
 	}
 
-	apiCtx.Cache.Save(httpcache.LastHTTPResponseTimestamp, time.Now())
-	apiCtx.Cache.Save(httpcache.LastHTTPResponseCacheKey, resp)
+	var config pagination.Config
+	configBytes := []byte(paginationConfig)
+	if format.IsJSON(configBytes) {
+		err = apiCtx.Formatters.JSON.Deserialize(configBytes, &config)
+	} else if format.IsYAML(configBytes) {
+		err = apiCtx.Formatters.YAML.Deserialize(configBytes, &config)
+	} else if format.IsXML(configBytes) {
+		return fmt.Errorf("this method does not support data in format: %s", format.XML)
+	} else {
+		return fmt.Errorf("could not recognize data format. Check your data, maybe you have typo somewhere or syntax error. Supported formats are: %s, %s", format.JSON, format.YAML)
+	}
 
-	if apiCtx.Debugger.IsOn() {
-		respBody, _ := apiCtx.GetLastResponseBody()
-		apiCtx.Debugger.Print(fmt.Sprintf("%s %s response body (status code: %d):\n\n%s\n", req.Method, req.URL.String(), resp.StatusCode, respBody))
+	if err != nil {
+		return fmt.Errorf("could not deserialize provided pagination config, err: %w", err)
+	}
+
+	if err = config.Validate(); err != nil {
+		return err
+	}
+
+	switch dataFormat {
+	case format.JSON, format.YAML:
+	case format.XML:
+		return fmt.Errorf("this method does not support data in format: %s", format.XML)
+	default:
+		return fmt.Errorf("provided unknown format: %s, format should be one of : %s, %s",
+			dataFormat, format.JSON, format.YAML)
+	}
+
+	req, err := apiCtx.GetPreparedRequest(cacheKey)
+	if err != nil {
+		return fmt.Errorf("could not obtain prepared request, err: %w", err)
+	}
+
+	var reqBody []byte
+	if req.Body != nil {
+		reqBody, err = ioutil.ReadAll(req.Body)
+		if err != nil {
+			return fmt.Errorf("could not read prepared request body, err: %w", err)
+		}
+
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	nextURL := req.URL
+	var position int
+	if config.Strategy == pagination.StrategyPage || config.Strategy == pagination.StrategyOffset {
+		if position, err = config.FirstPosition(req.URL); err != nil {
+			return err
+		}
+
+		nextURL = pagination.WithQueryParam(req.URL, config.Param, strconv.Itoa(position))
+	}
+
+	items := make([]interface{}, 0)
+	pageNumber := 1
+	for ; pageNumber <= pageLimit; pageNumber++ {
+		pageReq := req.Clone(req.Context())
+		pageReq.URL = nextURL
+		pageReq.Host = nextURL.Host
+		if reqBody != nil {
+			pageReq.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+		}
+
+		if err = apiCtx.sendRequest(pageReq); err != nil {
+			return fmt.Errorf("page %d, err: %w", pageNumber, err)
+		}
+
+		lastResp, errResp := apiCtx.GetLastResponse()
+		if errResp != nil {
+			return &MissingResponseError{Err: errResp}
+		}
+
+		body, errBody := apiCtx.GetLastResponseBody()
+		if errBody != nil {
+			return errBody
+		}
+
+		node, errNode := apiCtx.findNode(dataFormat, expr, body)
+		if errNode != nil {
+			if apiCtx.Debugger.IsOn() {
+				apiCtx.Debugger.Print(fmt.Sprintf("page %d response body:\n\n%s", pageNumber, body))
+			}
+
+			return fmt.Errorf("page %d, node '%s', err: %w", pageNumber, expr, errNode)
+		}
+
+		// null node is treated as empty page
+		if node == nil {
+			node = []interface{}{}
+		}
+
+		vNode := reflect.ValueOf(node)
+		if vNode.Kind() != reflect.Slice {
+			return fmt.Errorf("page %d, node '%s' does not point at slice(array)", pageNumber, expr)
+		}
+
+		for i := 0; i < vNode.Len(); i++ {
+			items = append(items, vNode.Index(i).Interface())
+		}
+
+		var hasNext bool
+		switch config.Strategy {
+		case pagination.StrategyLink:
+			nextURL, hasNext = pagination.NextLink(pageReq.URL, lastResp.Header)
+		case pagination.StrategyCursor:
+			cursor, errCursor := apiCtx.findNode(dataFormat, config.Cursor, body)
+			if errCursor != nil || cursor == nil || pagination.FormatCursor(cursor) == "" {
+				break
+			}
+
+			hasNext = true
+			if config.Param != "" {
+				nextURL = pagination.WithQueryParam(pageReq.URL, config.Param, pagination.FormatCursor(cursor))
+				break
+			}
+
+			nextURL, err = pageReq.URL.Parse(pagination.FormatCursor(cursor))
+			if err != nil {
+				return fmt.Errorf("page %d, cursor '%v' is not valid URL, err: %w", pageNumber, cursor, err)
+			}
+		case pagination.StrategyPage, pagination.StrategyOffset:
+			if vNode.Len() == 0 {
+				break
+			}
+
+			hasNext = true
+			if config.Strategy == pagination.StrategyPage {
+				position++
+			} else if config.Size > 0 {
+				position += config.Size
+			} else {
+				position += vNode.Len()
+			}
+
+			nextURL = pagination.WithQueryParam(pageReq.URL, config.Param, strconv.Itoa(position))
+		}
+
+		if !hasNext {
+			break
+		}
+	}
+
+	if pageNumber > pageLimit {
+		return fmt.Errorf("pagination did not reach the last page within page limit: %d", pageLimit)
 	}
 
+	apiCtx.Cache.Save(resultCacheKey, items)
+
 	return nil
 }
 
 // TheResponseStatusCodeShouldBe compare last response status code with given in argument.
-func (apiCtx *APIContext) TheResponseStatusCodeShouldBe(code int) error {
+func (apiCtx *APIContext) TheResponseStatusCodeShouldBe(code int) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
 	lastResponse, err := apiCtx.GetLastResponse()
 	if err != nil {
-		return fmt.Errorf("could not obtain last HTTP(s) response, err: %w", err)
+		return &MissingResponseError{Err: err}
 	}
 
 	if lastResponse.StatusCode != code {
-		return fmt.Errorf("expected status code %d, but got %d", code, lastResponse.StatusCode)
+		return apiCtx.unexpectedStatusCodeError(lastResponse, strconv.Itoa(code))
 	}
 
 	return nil
 }
 
-// TheResponseBodyShouldHaveFormat checks whether last response body has given data format.
-// Available data formats are listed in format package.
-func (apiCtx *APIContext) TheResponseBodyShouldHaveFormat(dataFormat format.DataFormat) error {
-	body, err := apiCtx.GetLastResponseBody()
+// TheResponseStatusCodeShouldBeOfClass checks whether last response status code belongs to given class,
+// for example: 2xx, 4xx or 5xx.
+func (apiCtx *APIContext) TheResponseStatusCodeShouldBeOfClass(class string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	normalized := strings.ToLower(strings.TrimSpace(class))
+	if len(normalized) != 3 || normalized[0] < '1' || normalized[0] > '5' || normalized[1:] != "xx" {
+		return fmt.Errorf("invalid status code class: %s, class should be one of: 1xx, 2xx, 3xx, 4xx, 5xx", class)
+	}
+
+	lastResponse, err := apiCtx.GetLastResponse()
 	if err != nil {
-		return fmt.Errorf("could not obtain last HTTP(s) response body, err: %w", err)
+		return &MissingResponseError{Err: err}
 	}
 
-	switch dataFormat {
-	case format.JSON:
-		isJSON := format.IsJSON(body)
-		if isJSON {
-			return nil
+	if lastResponse.StatusCode/100 != int(normalized[0]-'0') {
+		return apiCtx.unexpectedStatusCodeError(lastResponse, "of class "+normalized)
+	}
+
+	return nil
+}
+
+// TheResponseStatusCodeShouldBeOneOf checks whether last response status code is one of codes separated by comma (,),
+// for example: 200, 204.
+func (apiCtx *APIContext) TheResponseStatusCodeShouldBeOneOf(codes string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	var expected []int
+	for _, code := range strings.Split(codes, ",") {
+		c, err := strconv.Atoi(strings.TrimSpace(code))
+		if err != nil {
+			return fmt.Errorf("invalid status code: '%s', err: %w", strings.TrimSpace(code), err)
 		}
 
-		return fmt.Errorf("response body doesn't have format %s", format.JSON)
-	case format.YAML:
-		isYAML := format.IsYAML(body)
-		if isYAML {
+		expected = append(expected, c)
+	}
+
+	lastResponse, err := apiCtx.GetLastResponse()
+	if err != nil {
+		return &MissingResponseError{Err: err}
+	}
+
+	for _, code := range expected {
+		if lastResponse.StatusCode == code {
 			return nil
 		}
+	}
 
-		return fmt.Errorf("response body doesn't have format %s", format.YAML)
-	case format.XML:
-		isXml := format.IsXML(body)
-		if isXml {
+	return apiCtx.unexpectedStatusCodeError(lastResponse, "one of: "+codes)
+}
+
+// TheResponseStatusCodeShouldBeBetween checks whether last response status code is between from and to, inclusive.
+func (apiCtx *APIContext) TheResponseStatusCodeShouldBeBetween(from, to int) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	if from > to {
+		return fmt.Errorf("invalid status code range: %d is greater than %d", from, to)
+	}
+
+	lastResponse, err := apiCtx.GetLastResponse()
+	if err != nil {
+		return &MissingResponseError{Err: err}
+	}
+
+	if lastResponse.StatusCode < from || lastResponse.StatusCode > to {
+		return apiCtx.unexpectedStatusCodeError(lastResponse, fmt.Sprintf("between %d and %d", from, to))
+	}
+
+	return nil
+}
+
+// TheResponseBodyShouldHaveFormat checks whether last response body has given data format.
+// Available data formats are listed in format package.
+func (apiCtx *APIContext) TheResponseBodyShouldHaveFormat(dataFormat format.DataFormat) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	body, err := apiCtx.GetLastResponseBody()
+	if err != nil {
+		return err
+	}
+
+	actualFormat := recognizeFormat(body)
+	switch dataFormat {
+	case format.JSON, format.YAML, format.XML:
+		if actualFormat == dataFormat {
 			return nil
 		}
 
-		return fmt.Errorf("response body doesn't have format %s", format.XML)
+		return &AssertionError{Path: "body", Expected: dataFormat, Actual: actualFormat,
+			Message: fmt.Sprintf("response body doesn't have format %s", dataFormat)}
 	//This case checks whether last response body is not any of known types - then it assumes it is plain text
 	case format.PlainText:
-		if !(format.IsJSON(body) || format.IsYAML(body) || format.IsXML(body)) {
+		if actualFormat == format.PlainText {
 			return nil
 		}
 
-		return fmt.Errorf("response body is not plain text, it has one of following formats: %s, %s or %s", format.JSON, format.YAML, format.XML)
+		return &AssertionError{Path: "body", Expected: dataFormat, Actual: actualFormat,
+			Message: fmt.Sprintf("response body is not plain text, it has one of following formats: %s, %s or %s", format.JSON, format.YAML, format.XML)}
 	default:
 		return fmt.Errorf("unknown last response body data format, available formats: %s, %s, %s, %s",
 			format.JSON, format.YAML, format.XML, format.PlainText)
@@ -420,7 +729,9 @@ func (apiCtx *APIContext) TheResponseBodyShouldHaveFormat(dataFormat format.Data
 
 // IGenerateARandomIntInTheRangeToAndSaveItAs generates random integer from provided range
 // and preserve it under given cacheKey key.
-func (apiCtx *APIContext) IGenerateARandomIntInTheRangeToAndSaveItAs(from, to int, cacheKey string) error {
+func (apiCtx *APIContext) IGenerateARandomIntInTheRangeToAndSaveItAs(from, to int, cacheKey string) (err error) {
+	defer apiCtx.stepAnnotation()(&err)
+
 	randomInteger, err := mathutils.RandomInt(from, to)
 	if err != nil {
 		return fmt.Errorf("problem during generating pseudo random integer, err: %w", err)
@@ -433,7 +744,9 @@ func (apiCtx *APIContext) IGenerateARandomIntInTheRangeToAndSaveItAs(from, to in
 
 // IGenerateARandomFloatInTheRangeToAndSaveItAs generates random float from provided range
 // and preserve it under given cacheKey key.
-func (apiCtx *APIContext) IGenerateARandomFloatInTheRangeToAndSaveItAs(from, to int, cacheKey string) error {
+func (apiCtx *APIContext) IGenerateARandomFloatInTheRangeToAndSaveItAs(from, to int, cacheKey string) (err error) {
+	defer apiCtx.stepAnnotation()(&err)
+
 	randInt, err := mathutils.RandomInt(from, to)
 	if err != nil {
 		return fmt.Errorf("problem during generating pseudo random float, randomInt err: %w", err)
@@ -454,7 +767,9 @@ func (apiCtx *APIContext) IGenerateARandomFloatInTheRangeToAndSaveItAs(from, to
 // IGenerateARandomRunesInTheRangeToAndSaveItAs creates random runes generator func using provided charset
 // return func creates runes from provided range and preserve it under given cacheKey
 func (apiCtx *APIContext) IGenerateARandomRunesInTheRangeToAndSaveItAs(charset string) func(from, to int, cacheKey string) error {
-	return func(from, to int, cacheKey string) error {
+	return func(from, to int, cacheKey string) (err error) {
+		defer apiCtx.stepAnnotation()(&err)
+
 		randInt, err := mathutils.RandomInt(from, to)
 		if err != nil {
 			return fmt.Errorf("problem during generating random runes, randomInt err: %w", err)
@@ -471,7 +786,9 @@ func (apiCtx *APIContext) IGenerateARandomRunesInTheRangeToAndSaveItAs(charset s
 	each sentence has length from - to as provided in params and is saved in provided cacheKey
 */
 func (apiCtx *APIContext) IGenerateARandomSentenceInTheRangeFromToWordsAndSaveItAs(charset string, wordMinLength, wordMaxLength int) func(from, to int, cacheKey string) error {
-	return func(from, to int, cacheKey string) error {
+	return func(from, to int, cacheKey string) (err error) {
+		defer apiCtx.stepAnnotation()(&err)
+
 		if from > to {
 			return fmt.Errorf("could not generate sentence because of invalid range provided, from '%d' should not be greater than to: '%d'", from, to)
 		}
@@ -508,7 +825,9 @@ func (apiCtx *APIContext) IGenerateARandomSentenceInTheRangeFromToWordsAndSaveIt
 
 // IGetTimeAndTravelByAndSaveItAs accepts time object, move timeDuration in time and
 // save it in cache under given cacheKey.
-func (apiCtx *APIContext) IGetTimeAndTravelByAndSaveItAs(t time.Time, timeDirection timeutils.TimeDirection, timeDuration time.Duration, cacheKey string) error {
+func (apiCtx *APIContext) IGetTimeAndTravelByAndSaveItAs(t time.Time, timeDirection timeutils.TimeDirection, timeDuration time.Duration, cacheKey string) (err error) {
+	defer apiCtx.stepAnnotation()(&err)
+
 	var newTime time.Time
 	switch timeDirection {
 	case timeutils.TimeDirectionBackward:
@@ -526,21 +845,42 @@ func (apiCtx *APIContext) IGetTimeAndTravelByAndSaveItAs(t time.Time, timeDirect
 
 // IGenerateCurrentTimeAndTravelByAndSaveItAs creates current time object, move timeDuration in time and
 // save it in cache under given cacheKey.
-func (apiCtx *APIContext) IGenerateCurrentTimeAndTravelByAndSaveItAs(timeDirection timeutils.TimeDirection, timeDuration time.Duration, cacheKey string) error {
+func (apiCtx *APIContext) IGenerateCurrentTimeAndTravelByAndSaveItAs(timeDirection timeutils.TimeDirection, timeDuration time.Duration, cacheKey string) (err error) {
+	defer apiCtx.stepAnnotation()(&err)
+
 	return apiCtx.IGetTimeAndTravelByAndSaveItAs(time.Now(), timeDirection, timeDuration, cacheKey)
 }
 
+// IParseNodeAsTimeInLayoutAndSaveItAs parses node from last HTTP(s) response body as time in given layout
+// and saves obtained time.Time in cache under given cacheKey.
+// layout may be Go layout, for example: 2006-01-02, name of well-known layout: RFC3339, RFC3339Nano, RFC1123,
+// RFC1123Z, RFC822, RFC850, ANSIC, DateTime, DateOnly, TimeOnly or unix, unixmilli for epoch timestamps.
+func (apiCtx *APIContext) IParseNodeAsTimeInLayoutAndSaveItAs(dataFormat format.DataFormat, exprTemplate, layout, cacheKey string) (err error) {
+	defer apiCtx.stepAnnotation()(&err)
+
+	_, t, err := apiCtx.timeNode(dataFormat, exprTemplate, layout)
+	if err != nil {
+		return err
+	}
+
+	apiCtx.Cache.Save(cacheKey, t)
+
+	return nil
+}
+
 // TheResponseShouldHaveNode checks whether last response body contains given node.
 // expr should be valid according to injected PathFinder for given data format
-func (apiCtx *APIContext) TheResponseShouldHaveNode(dataFormat format.DataFormat, exprTemplate string) error {
+func (apiCtx *APIContext) TheResponseShouldHaveNode(dataFormat format.DataFormat, exprTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
 	body, err := apiCtx.GetLastResponseBody()
 	if err != nil {
-		return fmt.Errorf("could not obtain last HTTP(s) response body, err: %w", err)
+		return err
 	}
 
 	expr, err := apiCtx.TemplateEngine.Replace(exprTemplate, apiCtx.Cache.All())
 	if err != nil {
-		return fmt.Errorf("template engine has problem with 'form' template, err: %w", err)
+		return &TemplateError{Name: "form", Err: err}
 	}
 
 	switch dataFormat {
@@ -560,7 +900,7 @@ func (apiCtx *APIContext) TheResponseShouldHaveNode(dataFormat format.DataFormat
 			apiCtx.Debugger.Print(fmt.Sprintf("last response body:\n\n%s", body))
 		}
 
-		return fmt.Errorf("node '%s', err: %w", expr, err)
+		return &PathFinderError{Expr: expr, Err: err}
 	}
 
 	return nil
@@ -568,17 +908,19 @@ func (apiCtx *APIContext) TheResponseShouldHaveNode(dataFormat format.DataFormat
 
 // TheResponseShouldHaveNodes checks whether last request body has keys defined in string separated by comma
 // nodeExprs should be valid according to injected PathFinder expressions separated by comma (,)
-func (apiCtx *APIContext) TheResponseShouldHaveNodes(dataFormat format.DataFormat, expressionsTemplate string) error {
+func (apiCtx *APIContext) TheResponseShouldHaveNodes(dataFormat format.DataFormat, expressionsTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
 	expressions, err := apiCtx.TemplateEngine.Replace(expressionsTemplate, apiCtx.Cache.All())
 	if err != nil {
-		return fmt.Errorf("template engine has problem with 'form' template, err: %w", err)
+		return &TemplateError{Name: "form", Err: err}
 	}
 
 	keysSlice := strings.Split(expressions, ",")
 
 	body, err := apiCtx.GetLastResponseBody()
 	if err != nil {
-		return fmt.Errorf("could not obtain last HTTP(s) response body, err: %w", err)
+		return err
 	}
 
 	errs := make([]error, 0, len(keysSlice))
@@ -598,7 +940,7 @@ func (apiCtx *APIContext) TheResponseShouldHaveNodes(dataFormat format.DataForma
 		}
 
 		if err != nil {
-			errs = append(errs, fmt.Errorf("node '%s', err: %s", trimmedKey, err.Error()))
+			errs = append(errs, &PathFinderError{Expr: trimmedKey, Err: err})
 		}
 	}
 
@@ -612,56 +954,141 @@ func (apiCtx *APIContext) TheResponseShouldHaveNodes(dataFormat format.DataForma
 			apiCtx.Debugger.Print(fmt.Sprintf("last response body:\n\n%s", body))
 		}
 
-		return errors.New(errString)
+		return &AssertionError{Path: expressions, Message: strings.TrimSuffix(errString, "\n"), Err: errs[0]}
 	}
 
 	return nil
 }
 
-// TheNodeShouldNotBe checks whether node from last response body is not of provided type.
-// goType may be one of: nil, string, int, float, bool, map, slice,
-// expr should be valid according to injected PathFinder for given data format.
-func (apiCtx *APIContext) TheNodeShouldNotBe(dataFormat format.DataFormat, exprTemplate string, goType string) error {
+// TheResponseShouldNotHaveNode checks whether last response body does not contain given node.
+// expr should be valid according to injected PathFinder for given data format. Only missing node, reported by PathFinder
+// with pathfinder.ErrNotFound, satisfies assertion, malformed body or invalid expression are returned as errors.
+// Value of node is not reported in error, so method may be used to check that sensitive data does not leak.
+func (apiCtx *APIContext) TheResponseShouldNotHaveNode(dataFormat format.DataFormat, exprTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
 	body, err := apiCtx.GetLastResponseBody()
 	if err != nil {
-		return fmt.Errorf("could not obtain last HTTP(s) response body, err: %w", err)
+		return err
 	}
 
 	expr, err := apiCtx.TemplateEngine.Replace(exprTemplate, apiCtx.Cache.All())
 	if err != nil {
-		return fmt.Errorf("template engine has problem with 'form' template, err: %w", err)
+		return &TemplateError{Name: "expression", Err: err}
 	}
 
-	var iNodeVal interface{}
 	switch dataFormat {
-	case format.JSON:
-		iNodeVal, err = apiCtx.PathFinders.JSON.Find(expr, body)
-	case format.YAML:
-		iNodeVal, err = apiCtx.PathFinders.YAML.Find(expr, body)
-	case format.XML:
-		return fmt.Errorf("this method does not support data in format: %s", format.XML)
+	case format.JSON, format.YAML, format.XML:
 	default:
 		return fmt.Errorf("provided unknown format: %s, format should be one of : %s, %s, %s",
 			dataFormat, format.JSON, format.YAML, format.XML)
 	}
 
+	_, err = apiCtx.findNode(dataFormat, expr, body)
+	if errors.Is(err, pathfinder.ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return &PathFinderError{Expr: expr, Err: err}
+	}
+
+	return &AssertionError{Path: expr, Message: fmt.Sprintf("node '%s' exists in last HTTP(s) response body, but it should not", expr)}
+}
+
+// TheResponseShouldNotHaveNodes checks whether last response body does not contain any of nodes
+// defined in expressionsTemplate separated by comma (,). Nodes are missing when PathFinder reports pathfinder.ErrNotFound.
+// Values of nodes are not reported in error, so method may be used to check that sensitive data does not leak.
+func (apiCtx *APIContext) TheResponseShouldNotHaveNodes(dataFormat format.DataFormat, expressionsTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	expressions, err := apiCtx.TemplateEngine.Replace(expressionsTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "expressions", Err: err}
+	}
+
+	body, err := apiCtx.GetLastResponseBody()
 	if err != nil {
 		return err
 	}
 
-	vNodeVal := reflect.ValueOf(iNodeVal)
-	errInvalidType := fmt.Errorf("%s value is \"%s\", but expected not to be", expr, goType)
-	switch goType {
-	case "nil":
-		if !vNodeVal.IsValid() || reflectutils.IsValueNil(vNodeVal) {
-			return errInvalidType
+	switch dataFormat {
+	case format.JSON, format.YAML, format.XML:
+	default:
+		return fmt.Errorf("provided unknown format: %s, format should be one of : %s, %s, %s",
+			dataFormat, format.JSON, format.YAML, format.XML)
+	}
+
+	var existing []string
+	for _, key := range strings.Split(expressions, ",") {
+		trimmedKey := strings.TrimSpace(key)
+		_, err = apiCtx.findNode(dataFormat, trimmedKey, body)
+		if errors.Is(err, pathfinder.ErrNotFound) {
+			continue
 		}
-	case "string":
-		if vNodeVal.Kind() == reflect.String {
-			return errInvalidType
+
+		if err != nil {
+			return &PathFinderError{Expr: trimmedKey, Err: err}
 		}
-	case "int":
-		if vNodeVal.Kind() == reflect.Int64 || vNodeVal.Kind() == reflect.Int32 || vNodeVal.Kind() == reflect.Int16 ||
+
+		existing = append(existing, trimmedKey)
+	}
+
+	if len(existing) > 0 {
+		return &AssertionError{Path: strings.Join(existing, ", "),
+			Message: fmt.Sprintf("nodes '%s' exist in last HTTP(s) response body, but they should not", strings.Join(existing, "', '"))}
+	}
+
+	return nil
+}
+
+// TheNodeShouldNotBe checks whether node from last response body is not of provided type.
+// goType may be one of: nil, string, int, float, bool, map, slice,
+// expr should be valid according to injected PathFinder for given data format.
+func (apiCtx *APIContext) TheNodeShouldNotBe(dataFormat format.DataFormat, exprTemplate string, goType string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	body, err := apiCtx.GetLastResponseBody()
+	if err != nil {
+		return err
+	}
+
+	expr, err := apiCtx.TemplateEngine.Replace(exprTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "form", Err: err}
+	}
+
+	var iNodeVal interface{}
+	switch dataFormat {
+	case format.JSON:
+		iNodeVal, err = apiCtx.PathFinders.JSON.Find(expr, body)
+	case format.YAML:
+		iNodeVal, err = apiCtx.PathFinders.YAML.Find(expr, body)
+	case format.XML:
+		return fmt.Errorf("this method does not support data in format: %s", format.XML)
+	default:
+		return fmt.Errorf("provided unknown format: %s, format should be one of : %s, %s, %s",
+			dataFormat, format.JSON, format.YAML, format.XML)
+	}
+
+	if err != nil {
+		return &PathFinderError{Expr: expr, Err: err}
+	}
+
+	vNodeVal := reflect.ValueOf(iNodeVal)
+	errInvalidType := &AssertionError{Path: expr, Expected: "not " + goType, Actual: iNodeVal,
+		Message: fmt.Sprintf("%s value is \"%s\", but expected not to be", expr, goType)}
+	switch goType {
+	case "nil":
+		if !vNodeVal.IsValid() || reflectutils.IsValueNil(vNodeVal) {
+			return errInvalidType
+		}
+	case "string":
+		if vNodeVal.Kind() == reflect.String {
+			return errInvalidType
+		}
+	case "int":
+		if vNodeVal.Kind() == reflect.Int64 || vNodeVal.Kind() == reflect.Int32 || vNodeVal.Kind() == reflect.Int16 ||
 			vNodeVal.Kind() == reflect.Int8 || vNodeVal.Kind() == reflect.Int || vNodeVal.Kind() == reflect.Uint ||
 			vNodeVal.Kind() == reflect.Uint8 || vNodeVal.Kind() == reflect.Uint16 || vNodeVal.Kind() == reflect.Uint32 ||
 			vNodeVal.Kind() == reflect.Uint64 {
@@ -705,11 +1132,18 @@ func (apiCtx *APIContext) TheNodeShouldNotBe(dataFormat format.DataFormat, exprT
 // TheNodeShouldBe checks whether node from last response body is of provided type
 // goType may be one of: nil, string, int, float, bool, map, slice
 // expr should be valid according to injected PathResolver
-func (apiCtx *APIContext) TheNodeShouldBe(dataFormat format.DataFormat, exprTemplate string, goType string) error {
+func (apiCtx *APIContext) TheNodeShouldBe(dataFormat format.DataFormat, exprTemplate string, goType string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
 	switch goType {
 	case "nil", "string", "int", "float", "bool", "map", "slice":
 		if err := apiCtx.TheNodeShouldNotBe(dataFormat, exprTemplate, goType); err == nil {
-			return fmt.Errorf("%s value is not \"%s\", but expected to be", exprTemplate, goType)
+			expr, _ := apiCtx.TemplateEngine.Replace(exprTemplate, apiCtx.Cache.All())
+			body, _ := apiCtx.GetLastResponseBody()
+			node, _ := apiCtx.findNode(dataFormat, expr, body)
+
+			return &AssertionError{Path: expr, Expected: goType, Actual: node,
+				Message: fmt.Sprintf("%s value is not \"%s\", but expected to be", exprTemplate, goType)}
 		}
 
 		return nil
@@ -720,15 +1154,17 @@ func (apiCtx *APIContext) TheNodeShouldBe(dataFormat format.DataFormat, exprTemp
 
 // TheNodeShouldBeSliceOfLength checks whether given key is slice and has given length
 // expr should be valid according to injected PathFinder for provided dataFormat
-func (apiCtx *APIContext) TheNodeShouldBeSliceOfLength(dataFormat format.DataFormat, exprTemplate string, length int) error {
+func (apiCtx *APIContext) TheNodeShouldBeSliceOfLength(dataFormat format.DataFormat, exprTemplate string, length int) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
 	body, err := apiCtx.GetLastResponseBody()
 	if err != nil {
-		return fmt.Errorf("could not obtain last HTTP(s) response body, err: %w", err)
+		return err
 	}
 
 	expr, err := apiCtx.TemplateEngine.Replace(exprTemplate, apiCtx.Cache.All())
 	if err != nil {
-		return fmt.Errorf("template engine has problem with 'expression' template, err: %w", err)
+		return &TemplateError{Name: "expression", Err: err}
 	}
 
 	var iValue interface{}
@@ -749,13 +1185,14 @@ func (apiCtx *APIContext) TheNodeShouldBeSliceOfLength(dataFormat format.DataFor
 			apiCtx.Debugger.Print(fmt.Sprintf("last response body:\n\n%s", body))
 		}
 
-		return fmt.Errorf("node '%s', err: %s", expr, err.Error())
+		return &PathFinderError{Expr: expr, Err: err}
 	}
 
 	v := reflect.ValueOf(iValue)
 	if v.Kind() == reflect.Slice {
 		if v.Len() != length {
-			return fmt.Errorf("%s slice has length: %d, expected: %d", expr, v.Len(), length)
+			return &AssertionError{Path: expr, Expected: length, Actual: v.Len(),
+				Message: fmt.Sprintf("%s slice has length: %d, expected: %d", expr, v.Len(), length)}
 		}
 
 		return nil
@@ -765,154 +1202,214 @@ func (apiCtx *APIContext) TheNodeShouldBeSliceOfLength(dataFormat format.DataFor
 		apiCtx.Debugger.Print(fmt.Sprintf("last response body:\n\n%s", body))
 	}
 
-	return fmt.Errorf("%s does not point at slice(array) in last HTTP(s) response body", expr)
+	return &AssertionError{Path: expr, Expected: "slice", Actual: iValue,
+		Message: fmt.Sprintf("%s does not point at slice(array) in last HTTP(s) response body", expr)}
 }
 
-// TheNodeShouldBeOfValue compares json node value from expression to expected by user dataValue of given by user dataType
-// Available data types are listed in switch section in each case directive.
-// expr should be valid according to injected PathFinder for provided dataFormat.
-func (apiCtx *APIContext) TheNodeShouldBeOfValue(dataFormat format.DataFormat, exprTemplate, dataType, dataValue string) error {
-	nodeValueReplaced, err := apiCtx.TemplateEngine.Replace(dataValue, apiCtx.Cache.All())
+// TheNodeShouldBeSliceOfLengthAtLeast checks whether given node is slice and has at least given length.
+// expr should be valid according to injected PathFinder for provided dataFormat
+func (apiCtx *APIContext) TheNodeShouldBeSliceOfLengthAtLeast(dataFormat format.DataFormat, exprTemplate string, length int) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	expr, slice, err := apiCtx.sliceNode(dataFormat, exprTemplate)
 	if err != nil {
-		return fmt.Errorf("template engine has problem with 'value' template, err: %w", err)
+		return err
 	}
 
-	expr, err := apiCtx.TemplateEngine.Replace(exprTemplate, apiCtx.Cache.All())
+	if len(slice) < length {
+		return &AssertionError{Path: expr, Expected: length, Actual: len(slice),
+			Message: fmt.Sprintf("%s slice has length: %d, expected at least: %d", expr, len(slice), length)}
+	}
+
+	return nil
+}
+
+// TheNodeShouldBeSliceOfLengthAtMost checks whether given node is slice and has at most given length.
+// expr should be valid according to injected PathFinder for provided dataFormat
+func (apiCtx *APIContext) TheNodeShouldBeSliceOfLengthAtMost(dataFormat format.DataFormat, exprTemplate string, length int) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	expr, slice, err := apiCtx.sliceNode(dataFormat, exprTemplate)
 	if err != nil {
-		return fmt.Errorf("template engine has problem with 'expression' template, err: %w", err)
+		return err
 	}
 
-	if apiCtx.Debugger.IsOn() {
-		apiCtx.Debugger.Print(fmt.Sprintf("expected value template '%s' was replace to '%s'\nprovided expression template '%s' was replace to '%s'", dataValue, nodeValueReplaced, exprTemplate, expr))
+	if len(slice) > length {
+		return &AssertionError{Path: expr, Expected: length, Actual: len(slice),
+			Message: fmt.Sprintf("%s slice has length: %d, expected at most: %d", expr, len(slice), length)}
 	}
-	body, err := apiCtx.GetLastResponseBody()
+
+	return nil
+}
+
+// TheNodeShouldContainElement checks whether given node is slice and contains element equal to provided one.
+// elementTemplate should be value in JSON notation, for example: 10, true, "abc" or {"id": 1}, otherwise it is treated as string.
+func (apiCtx *APIContext) TheNodeShouldContainElement(dataFormat format.DataFormat, exprTemplate, elementTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	element, err := apiCtx.TemplateEngine.Replace(elementTemplate, apiCtx.Cache.All())
 	if err != nil {
-		return fmt.Errorf("could not obtain last HTTP(s) response body, err: %w", err)
+		return &TemplateError{Name: "element", Err: err}
 	}
 
-	var iValue interface{}
-	switch dataFormat {
-	case format.JSON:
-		iValue, err = apiCtx.PathFinders.JSON.Find(expr, body)
-	case format.YAML:
-		iValue, err = apiCtx.PathFinders.YAML.Find(expr, body)
-	case format.XML:
-		iValue, err = apiCtx.PathFinders.XML.Find(expr, body)
-	default:
-		return fmt.Errorf("provided unknown format: %s, format should be one of : %s, %s, %s",
-			dataFormat, format.JSON, format.YAML, format.XML)
+	expr, slice, err := apiCtx.sliceNode(dataFormat, exprTemplate)
+	if err != nil {
+		return err
+	}
+
+	expected := collection.ParseValue(element)
+	if !collection.Contains(slice, expected) {
+		return &AssertionError{Path: expr, Expected: expected, Actual: slice,
+			Message: fmt.Sprintf("%s slice does not contain element: %s", expr, element)}
 	}
 
+	return nil
+}
+
+// EveryElementOfTheNodeShouldHaveNodeOfValue checks whether every element of given slice node has node
+// pointed by elementExprTemplate equal to provided value. elementExprTemplate is evaluated against each element,
+// for example: $.status. valueTemplate should be value in JSON notation, otherwise it is treated as string.
+// Empty slice passes assertion. XML is not supported.
+func (apiCtx *APIContext) EveryElementOfTheNodeShouldHaveNodeOfValue(dataFormat format.DataFormat, exprTemplate, elementExprTemplate, valueTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	match, err := apiCtx.matchElements(dataFormat, exprTemplate, elementExprTemplate, valueTemplate)
 	if err != nil {
-		return fmt.Errorf("node '%s', err: %s", expr, err.Error())
+		return err
 	}
 
-	switch dataType {
-	case "string":
-		strVal, ok := iValue.(string)
-		if !ok {
-			return fmt.Errorf("expected %s to be %s, got %v", expr, dataType, iValue)
+	var failed, differences []string
+	var actual []interface{}
+	for i, difference := range match.mismatches {
+		if difference != "" {
+			failed = append(failed, strconv.Itoa(i))
+			differences = append(differences, fmt.Sprintf("[%d]:\n%s", i, difference))
+			actual = append(actual, match.values[i])
 		}
+	}
 
-		if strVal != nodeValueReplaced {
-			return fmt.Errorf("node %s string value: %s is not equal to expected string value: %s", expr, strVal, nodeValueReplaced)
-		}
-	case "int":
-		intNodeValue, err := strconv.Atoi(nodeValueReplaced)
-		if err != nil {
-			return fmt.Errorf("replaced node %s value %s could not be converted to int", expr, nodeValueReplaced)
-		}
+	if len(failed) > 0 {
+		return &AssertionError{Path: match.expr, Expected: match.expected, Actual: actual,
+			Message: fmt.Sprintf("%s slice elements with indexes: %s do not have node %s of expected value:\n%s",
+				match.expr, strings.Join(failed, ", "), match.elementExpr, strings.Join(differences, "\n"))}
+	}
 
-		if strVal, ok := iValue.(string); ok {
-			if intVal, errAtoi := strconv.Atoi(strVal); errAtoi == nil {
-				if intVal != intNodeValue {
-					return fmt.Errorf("node %s int value: %d is not equal to expected int value: %d", expr, intVal, intNodeValue)
-				}
+	return nil
+}
 
-				return nil
-			}
-		}
+// AnyElementOfTheNodeShouldHaveNodeOfValue checks whether at least one element of given slice node has node
+// pointed by elementExprTemplate equal to provided value. elementExprTemplate is evaluated against each element,
+// for example: $.status. valueTemplate should be value in JSON notation, otherwise it is treated as string.
+// Empty slice fails assertion. XML is not supported.
+func (apiCtx *APIContext) AnyElementOfTheNodeShouldHaveNodeOfValue(dataFormat format.DataFormat, exprTemplate, elementExprTemplate, valueTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
 
-		floatVal, ok := iValue.(float64)
-		if !ok {
-			uint64Val, ok := iValue.(uint64)
-			if !ok {
-				return fmt.Errorf("expected %s to be %s, got %v", expr, dataType, iValue)
-			}
+	match, err := apiCtx.matchElements(dataFormat, exprTemplate, elementExprTemplate, valueTemplate)
+	if err != nil {
+		return err
+	}
 
-			floatVal = float64(uint64Val)
-		}
+	if len(match.values) == 0 {
+		return &AssertionError{Path: match.expr, Expected: match.expected, Actual: match.values,
+			Message: fmt.Sprintf("%s slice is empty", match.expr)}
+	}
 
-		intVal := int(floatVal)
-		if intVal != intNodeValue {
-			return fmt.Errorf("node %s int value: %d is not equal to expected int value: %d", expr, intVal, intNodeValue)
-		}
-	case "float":
-		floatNodeValue, err := strconv.ParseFloat(nodeValueReplaced, 64)
-		if err != nil {
-			return fmt.Errorf("replaced node %s value %s could not be converted to float64", expr, nodeValueReplaced)
+	for _, difference := range match.mismatches {
+		if difference == "" {
+			return nil
 		}
+	}
 
-		if strVal, ok := iValue.(string); ok {
-			if floatVal, errParseFloat := strconv.ParseFloat(strVal, 64); errParseFloat == nil {
-				if floatVal != floatNodeValue {
-					return fmt.Errorf("node %s float value: %f is not equal to expected int value: %f", expr, floatVal, floatNodeValue)
-				}
+	return &AssertionError{Path: match.expr, Expected: match.expected, Actual: match.values,
+		Message: fmt.Sprintf("none of %s slice elements has node %s of expected value", match.expr, match.elementExpr)}
+}
 
-				return nil
-			}
-		}
+// TheNodeShouldBeSortedBy checks whether given slice node is sorted in given order by node pointed by elementExprTemplate.
+// elementExprTemplate is evaluated against each element, for example: $.createdAt, empty expression means element itself.
+// Sorted values should be all numbers or all strings, order may be one of: asc, ascending, desc, descending.
+// Sub-nodes are not supported for XML, but XML values holding numbers are compared as numbers.
+func (apiCtx *APIContext) TheNodeShouldBeSortedBy(dataFormat format.DataFormat, exprTemplate, elementExprTemplate string, order collection.Order) (err error) {
+	defer apiCtx.softAssertion()(&err)
 
-		floatVal, ok := iValue.(float64)
-		if !ok {
-			return fmt.Errorf("expected %s to be %s, got %v", expr, dataType, iValue)
-		}
+	order, err = collection.ParseOrder(string(order))
+	if err != nil {
+		return err
+	}
 
-		if floatVal != floatNodeValue {
-			return fmt.Errorf("node %s float value %f is not equal to expected float value %f", expr, floatVal, floatNodeValue)
-		}
-	case "bool":
-		boolVal, ok := iValue.(bool)
-		if !ok {
-			strVal, ok := iValue.(string)
-			if !ok {
-				return fmt.Errorf("expected %s to be %s, got %v", expr, dataType, iValue)
-			}
+	expr, slice, err := apiCtx.sliceNode(dataFormat, exprTemplate)
+	if err != nil {
+		return err
+	}
 
-			boolVal, err = strconv.ParseBool(strVal)
-			if err != nil {
-				return fmt.Errorf("expected %s to be %s, got %v", expr, dataType, iValue)
-			}
-		}
+	elementExpr, values, err := apiCtx.elementsSubNodes(dataFormat, slice, elementExprTemplate)
+	if err != nil {
+		return err
+	}
 
-		boolNodeValue, err := strconv.ParseBool(nodeValueReplaced)
-		if err != nil {
-			return fmt.Errorf("replaced node %s value %s could not be converted to bool", expr, nodeValueReplaced)
-		}
+	idx, err := collection.FirstUnsorted(values, order, dataFormat == format.XML)
+	if err != nil {
+		return fmt.Errorf("%s slice, err: %w", expr, err)
+	}
 
-		if boolVal != boolNodeValue {
-			return fmt.Errorf("node %s bool value %t is not equal to expected bool value %t", expr, boolVal, boolNodeValue)
-		}
+	if idx != -1 {
+		return &AssertionError{Path: expr, Expected: fmt.Sprintf("%s order", order), Actual: values,
+			Message: fmt.Sprintf("%s slice is not sorted in %s order by '%s', element %d: %v breaks order after %v", expr, order, elementExpr, idx, values[idx], values[idx-1])}
 	}
 
 	return nil
 }
 
-// TheNodeShouldMatchRegExp checks whether last response body node matches provided regExp.
-func (apiCtx *APIContext) TheNodeShouldMatchRegExp(dataFormat format.DataFormat, exprTemplate, regExpTemplate string) error {
-	regExpString, err := apiCtx.TemplateEngine.Replace(regExpTemplate, apiCtx.Cache.All())
+// TheNodeShouldBeUniqueBy checks whether elements of given slice node are unique by node pointed by elementExprTemplate.
+// elementExprTemplate is evaluated against each element, for example: $.id, empty expression means element itself.
+// Sub-nodes are not supported for XML.
+func (apiCtx *APIContext) TheNodeShouldBeUniqueBy(dataFormat format.DataFormat, exprTemplate, elementExprTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	expr, slice, err := apiCtx.sliceNode(dataFormat, exprTemplate)
+	if err != nil {
+		return err
+	}
+
+	elementExpr, values, err := apiCtx.elementsSubNodes(dataFormat, slice, elementExprTemplate)
+	if err != nil {
+		return err
+	}
+
+	first, second, err := collection.FirstDuplicate(values)
+	if err != nil {
+		return fmt.Errorf("%s slice, err: %w", expr, err)
+	}
+
+	if first != -1 {
+		return &AssertionError{Path: expr, Expected: "unique values", Actual: values,
+			Message: fmt.Sprintf("%s slice elements %d and %d are not unique by '%s', duplicated value: %v", expr, first, second, elementExpr, values[first])}
+	}
+
+	return nil
+}
+
+// TheNodeShouldBeOfValue compares json node value from expression to expected by user dataValue of given by user dataType
+// Available data types are listed in switch section in each case directive.
+// expr should be valid according to injected PathFinder for provided dataFormat.
+func (apiCtx *APIContext) TheNodeShouldBeOfValue(dataFormat format.DataFormat, exprTemplate, dataType, dataValue string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	nodeValueReplaced, err := apiCtx.TemplateEngine.Replace(dataValue, apiCtx.Cache.All())
 	if err != nil {
-		return fmt.Errorf("template engine has problem with 'regExp' template, err: %w", err)
+		return &TemplateError{Name: "value", Err: err}
 	}
 
 	expr, err := apiCtx.TemplateEngine.Replace(exprTemplate, apiCtx.Cache.All())
 	if err != nil {
-		return fmt.Errorf("template engine has problem with 'expression' template, err: %w", err)
+		return &TemplateError{Name: "expression", Err: err}
 	}
 
+	if apiCtx.Debugger.IsOn() {
+		apiCtx.Debugger.Print(fmt.Sprintf("expected value template '%s' was replace to '%s'\nprovided expression template '%s' was replace to '%s'", dataValue, nodeValueReplaced, exprTemplate, expr))
+	}
 	body, err := apiCtx.GetLastResponseBody()
 	if err != nil {
-		return fmt.Errorf("could not obtain last HTTP(s) response body, err: %w", err)
+		return err
 	}
 
 	var iValue interface{}
@@ -929,461 +1426,2501 @@ func (apiCtx *APIContext) TheNodeShouldMatchRegExp(dataFormat format.DataFormat,
 	}
 
 	if err != nil {
-		return fmt.Errorf("node '%s', err: %s", expr, err.Error())
-	}
-
-	jsonValue, err := apiCtx.Formatters.JSON.Serialize(iValue)
-	if err != nil {
-		return fmt.Errorf("problem during serialization, err: %w", err)
-	}
-
-	if apiCtx.Debugger.IsOn() {
-		apiCtx.Debugger.Print(fmt.Sprintf("matching: %s, with regExp: %s", jsonValue, regExpString))
+		return &PathFinderError{Expr: expr, Err: err}
 	}
 
-	matched, err := regexp.Match(regExpString, jsonValue)
+	mismatch, err := apiCtx.nodeValueMismatch(expr, iValue, dataType, nodeValueReplaced)
 	if err != nil {
-		return fmt.Errorf("problem with regExp matching, err: %w", err)
+		return err
 	}
 
-	if !matched {
-		return fmt.Errorf("%s does not contain any: %s", string(jsonValue), regExpString)
+	if mismatch != nil {
+		return &AssertionError{Path: expr, Expected: nodeValueReplaced, Actual: iValue, Err: mismatch}
 	}
 
 	return nil
 }
 
-// TheResponseShouldHaveHeader checks whether last HTTP response has given header.
-func (apiCtx *APIContext) TheResponseShouldHaveHeader(name string) error {
-	defer func() {
-		if apiCtx.Debugger.IsOn() {
-			lastResp, err := apiCtx.GetLastResponse()
-			if err != nil {
-				apiCtx.Debugger.Print("could not obtain last response headers")
-			}
+// TheNodeShouldNotBeOfValue checks whether node from last response body is not equal to dataValue of given dataType.
+// Available data types are the same as in TheNodeShouldBeOfValue. Node should exist and node of different type
+// is treated as not equal.
+// expr should be valid according to injected PathFinder for provided dataFormat.
+func (apiCtx *APIContext) TheNodeShouldNotBeOfValue(dataFormat format.DataFormat, exprTemplate, dataType, dataValue string) (err error) {
+	defer apiCtx.softAssertion()(&err)
 
-			apiCtx.Debugger.Print(fmt.Sprintf("last HTTP(s) response headers: %#v", lastResp.Header))
-		}
-	}()
+	switch dataType {
+	case "string", "int", "float", "bool":
+	default:
+		return fmt.Errorf("unknown data type: %s, available data types: string, int, float, bool", dataType)
+	}
 
-	lastResp, err := apiCtx.GetLastResponse()
+	value, err := apiCtx.TemplateEngine.Replace(dataValue, apiCtx.Cache.All())
 	if err != nil {
-		return fmt.Errorf("could not obtain last HTTP(s) response, err: %w", err)
+		return &TemplateError{Name: "value", Err: err}
 	}
 
-	header := lastResp.Header.Get(name)
-	if header != "" {
-		return nil
+	expr, err := apiCtx.TemplateEngine.Replace(exprTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "expression", Err: err}
 	}
 
-	if apiCtx.Debugger.IsOn() {
-		apiCtx.Debugger.Print(fmt.Sprintf("last HTTP(s) response headers: %#v", lastResp.Header))
+	body, err := apiCtx.GetLastResponseBody()
+	if err != nil {
+		return err
 	}
 
-	return fmt.Errorf("could not find header %s in last HTTP response", name)
-}
-
-// TheResponseShouldHaveHeaderOfValue checks whether last HTTP response has given header with provided valueTemplate.
-func (apiCtx *APIContext) TheResponseShouldHaveHeaderOfValue(name, valueTemplate string) error {
-	defer func() {
-		if apiCtx.Debugger.IsOn() {
-			lastResp, err := apiCtx.GetLastResponse()
-			if err != nil {
-				apiCtx.Debugger.Print("could not obtain last response headers")
-			}
-
-			apiCtx.Debugger.Print(fmt.Sprintf("last HTTP(s) response headers: %#v", lastResp.Header))
-		}
-	}()
-
-	lastResp, err := apiCtx.GetLastResponse()
+	iValue, err := apiCtx.findNode(dataFormat, expr, body)
 	if err != nil {
-		return fmt.Errorf("could not obtain last HTTP(s) response, err: %w", err)
+		return &PathFinderError{Expr: expr, Err: err}
 	}
 
-	header := lastResp.Header.Get(name)
-	value, err := apiCtx.TemplateEngine.Replace(valueTemplate, apiCtx.Cache.All())
+	mismatch, err := apiCtx.nodeValueMismatch(expr, iValue, dataType, value)
 	if err != nil {
-		return fmt.Errorf("template engine has problem with 'value' template, err: %w", err)
+		return err
+	}
+
+	if mismatch == nil {
+		return &AssertionError{Path: expr, Expected: value, Actual: iValue,
+			Message: fmt.Sprintf("node %s %s value is equal to %s, but it should not", expr, dataType, value)}
+	}
+
+	return nil
+}
+
+// TheNodeShouldBeGreaterThan checks whether numeric node from last HTTP(s) response body is greater than provided value.
+// XML nodes are strings, so they are parsed as numbers. valueTemplate may contain template values.
+func (apiCtx *APIContext) TheNodeShouldBeGreaterThan(dataFormat format.DataFormat, exprTemplate, valueTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	return apiCtx.compareNumericNode(dataFormat, exprTemplate, valueTemplate, "greater than", func(node, value float64) bool {
+		return node > value
+	})
+}
+
+// TheNodeShouldBeGreaterThanOrEqualTo checks whether numeric node from last HTTP(s) response body is greater than or equal to provided value.
+// XML nodes are strings, so they are parsed as numbers. valueTemplate may contain template values.
+func (apiCtx *APIContext) TheNodeShouldBeGreaterThanOrEqualTo(dataFormat format.DataFormat, exprTemplate, valueTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	return apiCtx.compareNumericNode(dataFormat, exprTemplate, valueTemplate, "greater than or equal to", func(node, value float64) bool {
+		return node >= value
+	})
+}
+
+// TheNodeShouldBeLessThan checks whether numeric node from last HTTP(s) response body is less than provided value.
+// XML nodes are strings, so they are parsed as numbers. valueTemplate may contain template values.
+func (apiCtx *APIContext) TheNodeShouldBeLessThan(dataFormat format.DataFormat, exprTemplate, valueTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	return apiCtx.compareNumericNode(dataFormat, exprTemplate, valueTemplate, "less than", func(node, value float64) bool {
+		return node < value
+	})
+}
+
+// TheNodeShouldBeLessThanOrEqualTo checks whether numeric node from last HTTP(s) response body is less than or equal to provided value.
+// XML nodes are strings, so they are parsed as numbers. valueTemplate may contain template values.
+func (apiCtx *APIContext) TheNodeShouldBeLessThanOrEqualTo(dataFormat format.DataFormat, exprTemplate, valueTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	return apiCtx.compareNumericNode(dataFormat, exprTemplate, valueTemplate, "less than or equal to", func(node, value float64) bool {
+		return node <= value
+	})
+}
+
+// TheNodeShouldBeBetween checks whether numeric node from last HTTP(s) response body is within inclusive range from - to.
+// XML nodes are strings, so they are parsed as numbers. fromTemplate and toTemplate may contain template values.
+func (apiCtx *APIContext) TheNodeShouldBeBetween(dataFormat format.DataFormat, exprTemplate, fromTemplate, toTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	from, err := apiCtx.templateNumber(fromTemplate, "from")
+	if err != nil {
+		return err
+	}
+
+	to, err := apiCtx.templateNumber(toTemplate, "to")
+	if err != nil {
+		return err
+	}
+
+	if from > to {
+		return fmt.Errorf("range start %v is greater than range end %v", from, to)
+	}
+
+	expr, node, err := apiCtx.numericNode(dataFormat, exprTemplate)
+	if err != nil {
+		return err
+	}
+
+	if node < from || node > to {
+		return &AssertionError{Path: expr, Expected: fmt.Sprintf("between %v and %v", from, to), Actual: node,
+			Message: fmt.Sprintf("node %s value %v is not between %v and %v", expr, node, from, to)}
+	}
+
+	return nil
+}
+
+// TheNodeShouldBeApproximately checks whether numeric node from last HTTP(s) response body differs
+// from provided value by no more than tolerance. XML nodes are strings, so they are parsed as numbers.
+// valueTemplate and toleranceTemplate may contain template values.
+func (apiCtx *APIContext) TheNodeShouldBeApproximately(dataFormat format.DataFormat, exprTemplate, valueTemplate, toleranceTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	value, err := apiCtx.templateNumber(valueTemplate, "value")
+	if err != nil {
+		return err
+	}
+
+	tolerance, err := apiCtx.templateNumber(toleranceTemplate, "tolerance")
+	if err != nil {
+		return err
+	}
+
+	if tolerance < 0 {
+		return fmt.Errorf("tolerance should not be negative, got: %v", tolerance)
+	}
+
+	expr, node, err := apiCtx.numericNode(dataFormat, exprTemplate)
+	if err != nil {
+		return err
+	}
+
+	// boundary is inclusive, so relative floating point rounding error of subtraction is allowed
+	const epsilon = 1e-9
+	if math.Abs(node-value) > tolerance+epsilon*math.Max(math.Abs(node), math.Abs(value)) {
+		return &AssertionError{Path: expr, Expected: fmt.Sprintf("%v ± %v", value, tolerance), Actual: node,
+			Message: fmt.Sprintf("node %s value %v is not equal to %v within tolerance %v", expr, node, value, tolerance)}
+	}
+
+	return nil
+}
+
+// TheNodeShouldBeDivisibleBy checks whether numeric node from last HTTP(s) response body is divisible by provided divisor.
+// XML nodes are strings, so they are parsed as numbers. divisorTemplate may contain template values.
+func (apiCtx *APIContext) TheNodeShouldBeDivisibleBy(dataFormat format.DataFormat, exprTemplate, divisorTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	divisor, err := apiCtx.templateNumber(divisorTemplate, "divisor")
+	if err != nil {
+		return err
+	}
+
+	expr, node, err := apiCtx.numericNode(dataFormat, exprTemplate)
+	if err != nil {
+		return err
+	}
+
+	divisible, err := mathutils.IsDivisibleBy(node, divisor)
+	if err != nil {
+		return err
+	}
+
+	if !divisible {
+		return &AssertionError{Path: expr, Expected: fmt.Sprintf("divisible by %v", divisor), Actual: node,
+			Message: fmt.Sprintf("node %s value %v is not divisible by %v", expr, node, divisor)}
+	}
+
+	return nil
+}
+
+// TheNodeShouldBeTimeInLayout checks whether node from last HTTP(s) response body is valid time in given layout.
+// Available layouts are described in IParseNodeAsTimeInLayoutAndSaveItAs.
+func (apiCtx *APIContext) TheNodeShouldBeTimeInLayout(dataFormat format.DataFormat, exprTemplate, layout string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	_, _, err = apiCtx.timeNode(dataFormat, exprTemplate, layout)
+
+	return err
+}
+
+// TheNodeShouldBeTimeInLayoutBefore checks whether node from last HTTP(s) response body is time in given layout,
+// which is before time saved in cache under cacheKey.
+func (apiCtx *APIContext) TheNodeShouldBeTimeInLayoutBefore(dataFormat format.DataFormat, exprTemplate, layout, cacheKey string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	expr, t, reference, err := apiCtx.timeNodeAndCachedTime(dataFormat, exprTemplate, layout, cacheKey)
+	if err != nil {
+		return err
+	}
+
+	if !t.Before(reference) {
+		return &AssertionError{Path: expr, Expected: "before " + reference.Format(time.RFC3339Nano), Actual: t.Format(time.RFC3339Nano),
+			Message: fmt.Sprintf("node %s time %s is not before %s", expr, t.Format(time.RFC3339Nano), reference.Format(time.RFC3339Nano))}
+	}
+
+	return nil
+}
+
+// TheNodeShouldBeTimeInLayoutAfter checks whether node from last HTTP(s) response body is time in given layout,
+// which is after time saved in cache under cacheKey.
+func (apiCtx *APIContext) TheNodeShouldBeTimeInLayoutAfter(dataFormat format.DataFormat, exprTemplate, layout, cacheKey string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	expr, t, reference, err := apiCtx.timeNodeAndCachedTime(dataFormat, exprTemplate, layout, cacheKey)
+	if err != nil {
+		return err
+	}
+
+	if !t.After(reference) {
+		return &AssertionError{Path: expr, Expected: "after " + reference.Format(time.RFC3339Nano), Actual: t.Format(time.RFC3339Nano),
+			Message: fmt.Sprintf("node %s time %s is not after %s", expr, t.Format(time.RFC3339Nano), reference.Format(time.RFC3339Nano))}
+	}
+
+	return nil
+}
+
+// TheNodeShouldBeTimeInLayoutWithinOf checks whether node from last HTTP(s) response body is time in given layout,
+// which differs from time saved in cache under cacheKey by no more than timeDuration, in any direction.
+func (apiCtx *APIContext) TheNodeShouldBeTimeInLayoutWithinOf(dataFormat format.DataFormat, exprTemplate, layout string, timeDuration time.Duration, cacheKey string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	expr, t, reference, err := apiCtx.timeNodeAndCachedTime(dataFormat, exprTemplate, layout, cacheKey)
+	if err != nil {
+		return err
+	}
+
+	difference := t.Sub(reference)
+	if difference < 0 {
+		difference = -difference
+	}
+
+	if difference > timeDuration {
+		return &AssertionError{Path: expr, Expected: fmt.Sprintf("within %s of %s", timeDuration, reference.Format(time.RFC3339Nano)), Actual: t.Format(time.RFC3339Nano),
+			Message: fmt.Sprintf("node %s time %s differs from %s by %s, expected at most %s", expr, t.Format(time.RFC3339Nano), reference.Format(time.RFC3339Nano), difference, timeDuration)}
+	}
+
+	return nil
+}
+
+// TheNodeShouldMatchDocument compares node from last HTTP(s) response body with expected document in given mode.
+// Modes are: exact, subset (expected document should be contained in node) and unordered (arrays elements order is not relevant).
+// documentTemplate should be JSON or YAML document or reference to file with it, for example: file://testdata/user.json.
+// ignoredPathsTemplate holds comma separated paths excluded from comparison, for example: $.id, $.users[*].createdAt.
+// Expression "$" points at whole response body. Returned error lists every differing path.
+func (apiCtx *APIContext) TheNodeShouldMatchDocument(dataFormat format.DataFormat, exprTemplate string, mode compare.Mode, documentTemplate, ignoredPathsTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	if dataFormat != format.JSON && dataFormat != format.YAML {
+		return fmt.Errorf("this method does not support data in format: %s", dataFormat)
+	}
+
+	mode, err = compare.ParseMode(string(mode))
+	if err != nil {
+		return err
+	}
+
+	expr, err := apiCtx.TemplateEngine.Replace(exprTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "expression", Err: err}
+	}
+
+	ignoredPaths, err := apiCtx.TemplateEngine.Replace(ignoredPathsTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "ignored paths", Err: err}
+	}
+
+	document, err := apiCtx.TemplateEngine.Replace(documentTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "document", Err: err}
+	}
+
+	reference, foundValidReference := apiCtx.fileRecognizer.Recognize(document)
+	if foundValidReference {
+		content, err := ioutil.ReadFile(reference.Reference.Value)
+		if err != nil {
+			return fmt.Errorf("could not read file with reference %s, err: %w", reference.Reference.Value, err)
+		}
+
+		document, err = apiCtx.TemplateEngine.Replace(string(content), apiCtx.Cache.All())
+		if err != nil {
+			return &TemplateError{Name: "file " + reference.Reference.Value, Err: err}
+		}
+	} else if reference.IsFoundReference() {
+		return fmt.Errorf("file reference %s is not valid", document)
+	}
+
+	var expected interface{}
+	documentBytes := []byte(document)
+	if format.IsJSON(documentBytes) {
+		err = apiCtx.Formatters.JSON.Deserialize(documentBytes, &expected)
+	} else if format.IsYAML(documentBytes) {
+		err = apiCtx.Formatters.YAML.Deserialize(documentBytes, &expected)
+	} else if format.IsXML(documentBytes) {
+		return fmt.Errorf("this method does not support data in format: %s", format.XML)
+	} else {
+		return fmt.Errorf("could not recognize data format. Check your data, maybe you have typo somewhere or syntax error. Supported formats are: %s, %s", format.JSON, format.YAML)
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not deserialize expected document, err: %w", err)
+	}
+
+	body, err := apiCtx.GetLastResponseBody()
+	if err != nil {
+		return err
+	}
+
+	var actual interface{}
+	switch {
+	case expr == compare.Root && dataFormat == format.JSON:
+		err = apiCtx.Formatters.JSON.Deserialize(body, &actual)
+	case expr == compare.Root:
+		err = apiCtx.Formatters.YAML.Deserialize(body, &actual)
+	default:
+		actual, err = apiCtx.findNode(dataFormat, expr, body)
+	}
+
+	if err != nil {
+		return &PathFinderError{Expr: expr, Err: err}
+	}
+
+	var ignored []string
+	if strings.TrimSpace(ignoredPaths) != "" {
+		ignored = strings.Split(ignoredPaths, ",")
+	}
+
+	differences := compare.Compare(expected, actual, mode, ignored)
+	if len(differences) == 0 {
+		return nil
+	}
+
+	if apiCtx.Debugger.IsOn() {
+		apiCtx.Debugger.Print(fmt.Sprintf("last response body:\n\n%s", body))
+	}
+
+	return &AssertionError{Path: expr, Expected: expected, Actual: actual,
+		Message: fmt.Sprintf("node '%s' does not match expected document in mode %s, found %d difference(s):\n%s", expr, mode, len(differences), apiCtx.Differ.Differences(differences))}
+}
+
+// TheResponseBodyShouldMatchSnapshot compares last HTTP(s) response body, normalized and pretty-printed in given format,
+// with snapshot file named after current feature file, scenario and example. Missing snapshot is created on first run.
+// Snapshots are rewritten instead of compared when environment variable GDUTILS_UPDATE_SNAPSHOTS is set to true.
+// redactedPathsTemplate holds comma separated paths of nodes, which values are replaced before comparison,
+// for example: $.id, $.users[*].createdAt. Redaction is not supported for XML.
+// Snapshots should be enabled with EnableSnapshots and scenario set with SetScenario.
+func (apiCtx *APIContext) TheResponseBodyShouldMatchSnapshot(dataFormat format.DataFormat, redactedPathsTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	if apiCtx.Snapshots.Dir == "" {
+		return fmt.Errorf("snapshots are not enabled, use EnableSnapshots first")
+	}
+
+	if apiCtx.scenarioName == "" {
+		return fmt.Errorf("scenario is not set, use SetScenario in scenario hook first")
+	}
+
+	redactedPaths, err := apiCtx.TemplateEngine.Replace(redactedPathsTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "redacted paths", Err: err}
+	}
+
+	body, err := apiCtx.GetLastResponseBody()
+	if err != nil {
+		return err
+	}
+
+	var redacted []string
+	if strings.TrimSpace(redactedPaths) != "" {
+		redacted = strings.Split(redactedPaths, ",")
+	}
+
+	normalized, err := snapshot.Normalize(dataFormat, body, redacted)
+	if err != nil {
+		return fmt.Errorf("could not normalize last HTTP(s) response body, err: %w", err)
+	}
+
+	apiCtx.snapshotIndex++
+	fileName := snapshot.FileName(apiCtx.featureURI, apiCtx.scenarioName, apiCtx.exampleKey, apiCtx.snapshotIndex, dataFormat)
+
+	status, err := apiCtx.Snapshots.Match(fileName, normalized)
+	if err != nil {
+		if apiCtx.Debugger.IsOn() {
+			apiCtx.Debugger.Print(fmt.Sprintf("normalized last response body:\n\n%s", normalized))
+		}
+
+		if errors.Is(err, snapshot.ErrMismatch) {
+			stored, _ := ioutil.ReadFile(filepath.Join(apiCtx.Snapshots.Dir, fileName))
+
+			return &AssertionError{Path: fileName, Expected: string(stored), Actual: string(normalized), Err: err}
+		}
+
+		return err
+	}
+
+	if apiCtx.Debugger.IsOn() {
+		apiCtx.Debugger.Print(fmt.Sprintf("snapshot %s: %s", fileName, status))
+	}
+
+	return nil
+}
+
+// TheNodeShouldMatchRegExp checks whether last response body node matches provided regExp.
+func (apiCtx *APIContext) TheNodeShouldMatchRegExp(dataFormat format.DataFormat, exprTemplate, regExpTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	regExpString, err := apiCtx.TemplateEngine.Replace(regExpTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "regExp", Err: err}
+	}
+
+	expr, err := apiCtx.TemplateEngine.Replace(exprTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "expression", Err: err}
+	}
+
+	body, err := apiCtx.GetLastResponseBody()
+	if err != nil {
+		return err
+	}
+
+	var iValue interface{}
+	switch dataFormat {
+	case format.JSON:
+		iValue, err = apiCtx.PathFinders.JSON.Find(expr, body)
+	case format.YAML:
+		iValue, err = apiCtx.PathFinders.YAML.Find(expr, body)
+	case format.XML:
+		iValue, err = apiCtx.PathFinders.XML.Find(expr, body)
+	default:
+		return fmt.Errorf("provided unknown format: %s, format should be one of : %s, %s, %s",
+			dataFormat, format.JSON, format.YAML, format.XML)
+	}
+
+	if err != nil {
+		return &PathFinderError{Expr: expr, Err: err}
+	}
+
+	jsonValue, err := apiCtx.Formatters.JSON.Serialize(iValue)
+	if err != nil {
+		return fmt.Errorf("problem during serialization, err: %w", err)
+	}
+
+	if apiCtx.Debugger.IsOn() {
+		apiCtx.Debugger.Print(fmt.Sprintf("matching: %s, with regExp: %s", jsonValue, regExpString))
+	}
+
+	matched, err := regexp.Match(regExpString, jsonValue)
+	if err != nil {
+		return fmt.Errorf("problem with regExp matching, err: %w", err)
+	}
+
+	if !matched {
+		return &AssertionError{Path: expr, Expected: regExpString, Actual: string(jsonValue),
+			Message: fmt.Sprintf("%s does not contain any: %s", string(jsonValue), regExpString)}
+	}
+
+	return nil
+}
+
+// TheNodeShouldNotMatchRegExp checks whether last response body node does not match provided regExp.
+// Node should exist, its value is not reported in error.
+func (apiCtx *APIContext) TheNodeShouldNotMatchRegExp(dataFormat format.DataFormat, exprTemplate, regExpTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	regExpString, err := apiCtx.TemplateEngine.Replace(regExpTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "regExp", Err: err}
+	}
+
+	expr, err := apiCtx.TemplateEngine.Replace(exprTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "expression", Err: err}
+	}
+
+	body, err := apiCtx.GetLastResponseBody()
+	if err != nil {
+		return err
+	}
+
+	iValue, err := apiCtx.findNode(dataFormat, expr, body)
+	if err != nil {
+		return &PathFinderError{Expr: expr, Err: err}
+	}
+
+	jsonValue, err := apiCtx.Formatters.JSON.Serialize(iValue)
+	if err != nil {
+		return fmt.Errorf("problem during serialization, err: %w", err)
+	}
+
+	matched, err := regexp.Match(regExpString, jsonValue)
+	if err != nil {
+		return fmt.Errorf("problem with regExp matching, err: %w", err)
+	}
+
+	if matched {
+		return &AssertionError{Path: expr, Expected: "not matching " + regExpString, Actual: string(jsonValue),
+			Message: fmt.Sprintf("node %s matches regExp: %s, but it should not", expr, regExpString)}
+	}
+
+	return nil
+}
+
+// TheResponseShouldSatisfyExpression checks whether last HTTP(s) response satisfies boolean expression written in expr language,
+// for example: all(body.items, {.price > 0}) && status == 200. Expression has access to variables:
+// status, headers (keyed by lowercase names), cookies, body (deserialized JSON or YAML), rawBody and cache.
+func (apiCtx *APIContext) TheResponseShouldSatisfyExpression(expressionTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	expr, err := apiCtx.TemplateEngine.Replace(expressionTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "expression", Err: err}
+	}
+
+	lastResp, err := apiCtx.GetLastResponse()
+	if err != nil {
+		return &MissingResponseError{Err: err}
+	}
+
+	var body []byte
+	if lastResp.Body != nil {
+		if body, err = apiCtx.GetLastResponseBody(); err != nil {
+			return err
+		}
+	}
+
+	env, err := expression.NewResponseEnv(lastResp, body, apiCtx.Cache.All())
+	if err != nil {
+		return err
+	}
+
+	satisfied, err := expression.Evaluate(expr, env)
+	if err != nil {
+		return fmt.Errorf("expression '%s', err: %w", expr, err)
+	}
+
+	if !satisfied {
+		if apiCtx.Debugger.IsOn() {
+			apiCtx.Debugger.Print(fmt.Sprintf("last HTTP(s) response status: %d, body:\n\n%s", lastResp.StatusCode, body))
+		}
+
+		return &AssertionError{Path: "response", Expected: expr, Actual: false,
+			Message: fmt.Sprintf("last HTTP(s) response does not satisfy expression: %s", expr)}
+	}
+
+	return nil
+}
+
+// TheResponseShouldHaveHeader checks whether last HTTP response has given header.
+func (apiCtx *APIContext) TheResponseShouldHaveHeader(name string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	defer func() {
+		if apiCtx.Debugger.IsOn() {
+			lastResp, err := apiCtx.GetLastResponse()
+			if err != nil {
+				apiCtx.Debugger.Print("could not obtain last response headers")
+			}
+
+			apiCtx.Debugger.Print(fmt.Sprintf("last HTTP(s) response headers: %#v", lastResp.Header))
+		}
+	}()
+
+	lastResp, err := apiCtx.GetLastResponse()
+	if err != nil {
+		return &MissingResponseError{Err: err}
+	}
+
+	header := lastResp.Header.Get(name)
+	if header != "" {
+		return nil
+	}
+
+	if apiCtx.Debugger.IsOn() {
+		apiCtx.Debugger.Print(fmt.Sprintf("last HTTP(s) response headers: %#v", lastResp.Header))
+	}
+
+	return &AssertionError{Path: name, Message: fmt.Sprintf("could not find header %s in last HTTP response", name)}
+}
+
+// TheResponseShouldHaveHeaderOfValue checks whether last HTTP response has given header with provided valueTemplate.
+func (apiCtx *APIContext) TheResponseShouldHaveHeaderOfValue(name, valueTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	defer func() {
+		if apiCtx.Debugger.IsOn() {
+			lastResp, err := apiCtx.GetLastResponse()
+			if err != nil {
+				apiCtx.Debugger.Print("could not obtain last response headers")
+			}
+
+			apiCtx.Debugger.Print(fmt.Sprintf("last HTTP(s) response headers: %#v", lastResp.Header))
+		}
+	}()
+
+	lastResp, err := apiCtx.GetLastResponse()
+	if err != nil {
+		return &MissingResponseError{Err: err}
+	}
+
+	header := lastResp.Header.Get(name)
+	value, err := apiCtx.TemplateEngine.Replace(valueTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "value", Err: err}
 	}
 
 	if header == "" || value == "" {
-		return fmt.Errorf("could not find header %s in last HTTP(s) response", name)
+		return &AssertionError{Path: name, Expected: value, Actual: header,
+			Message: fmt.Sprintf("could not find header %s in last HTTP(s) response", name)}
+	}
+
+	if header == value {
+		return nil
+	}
+
+	return &AssertionError{Path: name, Expected: value, Actual: header,
+		Message: fmt.Sprintf("%s header exists but its value is not equal to expected:\n%s", name, apiCtx.Differ.Strings(value, header))}
+}
+
+// TheResponseShouldNotHaveHeader checks whether last HTTP response does not have given header.
+func (apiCtx *APIContext) TheResponseShouldNotHaveHeader(name string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	lastResp, err := apiCtx.GetLastResponse()
+	if err != nil {
+		return &MissingResponseError{Err: err}
+	}
+
+	if len(lastResp.Header.Values(name)) == 0 {
+		return nil
+	}
+
+	if apiCtx.Debugger.IsOn() {
+		apiCtx.Debugger.Print(fmt.Sprintf("last HTTP(s) response headers: %#v", lastResp.Header))
+	}
+
+	return &AssertionError{Path: name, Actual: lastResp.Header.Values(name),
+		Message: fmt.Sprintf("header %s exists in last HTTP(s) response, but it should not", name)}
+}
+
+// TheResponseShouldNotHaveHeaderOfValue checks whether none of values of given header in last HTTP response
+// is equal to provided valueTemplate. Missing header is treated as header of different value.
+func (apiCtx *APIContext) TheResponseShouldNotHaveHeaderOfValue(name, valueTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	lastResp, err := apiCtx.GetLastResponse()
+	if err != nil {
+		return &MissingResponseError{Err: err}
+	}
+
+	value, err := apiCtx.TemplateEngine.Replace(valueTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "value", Err: err}
+	}
+
+	for _, header := range lastResp.Header.Values(name) {
+		if header == value {
+			return &AssertionError{Path: name, Expected: "not " + value, Actual: header,
+				Message: fmt.Sprintf("%s header has value: %s, but it should not", name, value)}
+		}
+	}
+
+	return nil
+}
+
+// TheResponseHeaderShouldMatchRegExp checks whether value of given header of last HTTP(s) response matches regExpTemplate.
+// Values of many header lines are joined with comma before matching.
+func (apiCtx *APIContext) TheResponseHeaderShouldMatchRegExp(name, regExpTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	regExpString, err := apiCtx.TemplateEngine.Replace(regExpTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "regExp", Err: err}
+	}
+
+	lastResp, err := apiCtx.GetLastResponse()
+	if err != nil {
+		return &MissingResponseError{Err: err}
+	}
+
+	values := lastResp.Header.Values(name)
+	if len(values) == 0 {
+		return &AssertionError{Path: name, Expected: regExpString,
+			Message: fmt.Sprintf("could not find header %s in last HTTP(s) response", name)}
+	}
+
+	header := strings.Join(values, ", ")
+	matched, err := regexp.MatchString(regExpString, header)
+	if err != nil {
+		return fmt.Errorf("problem with regExp matching, err: %w", err)
+	}
+
+	if !matched {
+		return &AssertionError{Path: name, Expected: regExpString, Actual: header,
+			Message: fmt.Sprintf("%s header value: %s does not match regExp: %s", name, header, regExpString)}
+	}
+
+	return nil
+}
+
+// TheResponseHeaderShouldContainValue checks whether given header of last HTTP(s) response contains valueTemplate
+// among its values. Header may have many lines and each of them may be list of values separated by comma.
+func (apiCtx *APIContext) TheResponseHeaderShouldContainValue(name, valueTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	value, err := apiCtx.TemplateEngine.Replace(valueTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "value", Err: err}
+	}
+
+	lastResp, err := apiCtx.GetLastResponse()
+	if err != nil {
+		return &MissingResponseError{Err: err}
+	}
+
+	lines := lastResp.Header.Values(name)
+	if len(lines) == 0 {
+		return &AssertionError{Path: name, Expected: value,
+			Message: fmt.Sprintf("could not find header %s in last HTTP(s) response", name)}
+	}
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) == value {
+			return nil
+		}
+	}
+
+	values := httpctx.SplitHeaderValues(lines)
+	for _, v := range values {
+		if v == value {
+			return nil
+		}
+	}
+
+	return &AssertionError{Path: name, Expected: value, Actual: values,
+		Message: fmt.Sprintf("%s header does not contain value: %s, actual values: %s", name, value, strings.Join(values, " | "))}
+}
+
+// TheResponseContentTypeShouldBe checks whether Content-Type header of last HTTP(s) response matches mediaTypeTemplate,
+// for example: application/json; charset=utf-8. Media types are compared semantically, that is types and parameter names
+// are case-insensitive, as well as charset value. Parameters not present in mediaTypeTemplate are not checked.
+func (apiCtx *APIContext) TheResponseContentTypeShouldBe(mediaTypeTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	mediaType, err := apiCtx.TemplateEngine.Replace(mediaTypeTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "media type", Err: err}
+	}
+
+	lastResp, err := apiCtx.GetLastResponse()
+	if err != nil {
+		return &MissingResponseError{Err: err}
+	}
+
+	contentType := lastResp.Header.Get("Content-Type")
+	if contentType == "" {
+		return &AssertionError{Path: "Content-Type", Expected: mediaType,
+			Message: "could not find header Content-Type in last HTTP(s) response"}
+	}
+
+	if err = httpctx.CompareMediaTypes(mediaType, contentType); err != nil {
+		return &AssertionError{Path: "Content-Type", Expected: mediaType, Actual: contentType,
+			Message: fmt.Sprintf("last HTTP(s) response Content-Type header: %s does not match %s, err: %v", contentType, mediaType, err), Err: err}
+	}
+
+	return nil
+}
+
+// TheResponseHeaderShouldHaveDirective checks whether given header of last HTTP(s) response, parsed as RFC 8941 dictionary,
+// has member of given name, for example: Cache-Control header with no-store directive. Cache-Control header,
+// which is not valid dictionary, is parsed leniently as list of directives separated by comma, with case-insensitive names.
+func (apiCtx *APIContext) TheResponseHeaderShouldHaveDirective(name, directive string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	directives, err := apiCtx.lastResponseHeaderDirectives(name)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := httpctx.FindDirective(directives, directive); !ok {
+		names := httpctx.DirectiveNames(directives)
+
+		return &AssertionError{Path: name + ": " + directive, Actual: names,
+			Message: fmt.Sprintf("%s header does not have directive %s, available directives: %s", name, directive, strings.Join(names, ", "))}
+	}
+
+	return nil
+}
+
+// TheResponseHeaderDirectiveShouldBeOfValue checks whether given header of last HTTP(s) response, parsed as RFC 8941 dictionary,
+// has member of given name and value, for example: Cache-Control header with max-age directive of value 3600.
+// Cache-Control header, which is not valid dictionary, is parsed leniently like in TheResponseHeaderShouldHaveDirective.
+// Strings are compared without quotes and directive without value is equal to true.
+func (apiCtx *APIContext) TheResponseHeaderDirectiveShouldBeOfValue(name, directive, valueTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	value, err := apiCtx.TemplateEngine.Replace(valueTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "value", Err: err}
+	}
+
+	directives, err := apiCtx.lastResponseHeaderDirectives(name)
+	if err != nil {
+		return err
+	}
+
+	found, ok := httpctx.FindDirective(directives, directive)
+	if !ok {
+		names := httpctx.DirectiveNames(directives)
+
+		return &AssertionError{Path: name + ": " + directive, Expected: value, Actual: names,
+			Message: fmt.Sprintf("%s header does not have directive %s, available directives: %s", name, directive, strings.Join(names, ", "))}
+	}
+
+	if found.Text() != value {
+		return &AssertionError{Path: name + ": " + directive, Expected: value, Actual: found.Text(),
+			Message: fmt.Sprintf("%s header directive %s has value not equal to expected:\n%s", name, directive, apiCtx.Differ.Strings(value, found.Text()))}
+	}
+
+	return nil
+}
+
+// IValidateLastResponseBodyWithSchemaReference validates last response body against schema as provided in referenceTemplate.
+// referenceTemplate may be: URL or full/relative path. Body in YAML or XML format is converted to JSON before validation,
+// XML according to mapping described in schema.XMLToJSON.
+func (apiCtx *APIContext) IValidateLastResponseBodyWithSchemaReference(referenceTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	reference, err := apiCtx.TemplateEngine.Replace(referenceTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "reference", Err: err}
+	}
+
+	body, err := apiCtx.GetLastResponseBody()
+	if err != nil {
+		return err
+	}
+
+	if apiCtx.Debugger.IsOn() {
+		apiCtx.Debugger.Print(fmt.Sprintf("'%s' was replaced as: '%s'\n", referenceTemplate, reference))
+	}
+
+	return apiCtx.validateBodyWithSchema(body, reference, apiCtx.SchemaValidators.ReferenceValidator)
+}
+
+// IValidateLastResponseBodyWithSchemaString validates last response body against schema.
+// Body in YAML or XML format is converted to JSON before validation, XML according to mapping described in schema.XMLToJSON.
+func (apiCtx *APIContext) IValidateLastResponseBodyWithSchemaString(schema string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	body, err := apiCtx.GetLastResponseBody()
+	if err != nil {
+		return err
+	}
+
+	return apiCtx.validateBodyWithSchema(body, schema, apiCtx.SchemaValidators.StringValidator)
+}
+
+// IValidateNodeWithSchemaString validates last response body JSON node against schema
+func (apiCtx *APIContext) IValidateNodeWithSchemaString(dataFormat format.DataFormat, exprTemplate, schemaTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	return apiCtx.iValidateNodeWithSchemaGeneral(dataFormat, exprTemplate, schemaTemplate, apiCtx.SchemaValidators.StringValidator)
+}
+
+// IValidateNodeWithSchemaReference validates last response body node against schema as provided in referenceTemplate
+func (apiCtx *APIContext) IValidateNodeWithSchemaReference(dataFormat format.DataFormat, exprTemplate, referenceTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	return apiCtx.iValidateNodeWithSchemaGeneral(dataFormat, exprTemplate, referenceTemplate, apiCtx.SchemaValidators.ReferenceValidator)
+}
+
+// IValidateLastResponseBodyWithXSDReference validates last response body in XML format against XML Schema
+// as provided in referenceTemplate. referenceTemplate may be: full path or path relative to schemas directory.
+func (apiCtx *APIContext) IValidateLastResponseBodyWithXSDReference(referenceTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	reference, err := apiCtx.TemplateEngine.Replace(referenceTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "reference", Err: err}
+	}
+
+	body, err := apiCtx.GetLastResponseBody()
+	if err != nil {
+		return err
+	}
+
+	if apiCtx.SchemaValidators.XSDReferenceValidator == nil {
+		return errors.New("XML Schema reference validator is not set")
+	}
+
+	if apiCtx.Debugger.IsOn() {
+		apiCtx.Debugger.Print(fmt.Sprintf("'%s' was replaced as: '%s'\n", referenceTemplate, reference))
+	}
+
+	return schemaMismatch(apiCtx.SchemaValidators.XSDReferenceValidator.Validate(string(body), reference), "body", reference, body, "")
+}
+
+// IValidateLastResponseBodyWithXSDString validates last response body in XML format against XML Schema.
+func (apiCtx *APIContext) IValidateLastResponseBodyWithXSDString(schema string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	body, err := apiCtx.GetLastResponseBody()
+	if err != nil {
+		return err
+	}
+
+	if apiCtx.SchemaValidators.XSDStringValidator == nil {
+		return errors.New("XML Schema string validator is not set")
+	}
+
+	return schemaMismatch(apiCtx.SchemaValidators.XSDStringValidator.Validate(string(body), schema), "body", schema, body, "")
+}
+
+// IValidateXMLNodeWithXSDReference validates last response body XML element found with XPath expression
+// against XML Schema as provided in referenceTemplate. Element should be declared as global element in schema.
+func (apiCtx *APIContext) IValidateXMLNodeWithXSDReference(exprTemplate, referenceTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	return apiCtx.iValidateXMLNodeWithXSDGeneral(exprTemplate, referenceTemplate, apiCtx.SchemaValidators.XSDReferenceValidator)
+}
+
+// IValidateXMLNodeWithXSDString validates last response body XML element found with XPath expression
+// against XML Schema. Element should be declared as global element in schema.
+func (apiCtx *APIContext) IValidateXMLNodeWithXSDString(exprTemplate, schemaTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	return apiCtx.iValidateXMLNodeWithXSDGeneral(exprTemplate, schemaTemplate, apiCtx.SchemaValidators.XSDStringValidator)
+}
+
+// IGenerateJSONSchemaFromLastResponseBodyAndWriteItTo infers JSON schema from last response body in JSON, YAML or XML format
+// and writes it to file under JSON schemas directory. fileNameTemplate should be path relative to that directory,
+// for example: users/user.json. Inferred schema should be reviewed before it is used in assertions.
+func (apiCtx *APIContext) IGenerateJSONSchemaFromLastResponseBodyAndWriteItTo(fileNameTemplate string) (err error) {
+	defer apiCtx.stepAnnotation()(&err)
+
+	body, err := apiCtx.GetLastResponseBody()
+	if err != nil {
+		return err
+	}
+
+	return apiCtx.generateJSONSchema([][]byte{body}, fileNameTemplate)
+}
+
+// IGenerateJSONSchemaFromSavedResponsesAndWriteItTo infers one JSON schema describing every response saved in cache
+// under comma separated cacheKeysTemplate and writes it to file under JSON schemas directory, like
+// IGenerateJSONSchemaFromLastResponseBodyAndWriteItTo. Saved values may be HTTP(s) responses, bodies as string
+// or nodes saved with ISaveFromTheLastResponseNodeAs.
+func (apiCtx *APIContext) IGenerateJSONSchemaFromSavedResponsesAndWriteItTo(cacheKeysTemplate, fileNameTemplate string) (err error) {
+	defer apiCtx.stepAnnotation()(&err)
+
+	cacheKeys, err := apiCtx.TemplateEngine.Replace(cacheKeysTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "cache keys", Err: err}
+	}
+
+	var documents [][]byte
+	for _, cacheKey := range strings.Split(cacheKeys, ",") {
+		saved, err := apiCtx.Cache.GetSaved(strings.TrimSpace(cacheKey))
+		if err != nil {
+			return err
+		}
+
+		var document []byte
+		switch v := saved.(type) {
+		case *http.Response:
+			document, _ = ioutil.ReadAll(v.Body)
+			v.Body.Close()
+
+			// saved response body may be read again
+			v.Body = ioutil.NopCloser(bytes.NewBuffer(document))
+		case []byte:
+			document = v
+		case string:
+			document = []byte(v)
+		default:
+			if document, err = apiCtx.Formatters.JSON.Serialize(v); err != nil {
+				return fmt.Errorf("could not serialize value saved under '%s' to JSON, err: %w", strings.TrimSpace(cacheKey), err)
+			}
+		}
+
+		documents = append(documents, document)
+	}
+
+	return apiCtx.generateJSONSchema(documents, fileNameTemplate)
+}
+
+// TimeBetweenLastHTTPRequestResponseShouldBeLessThanOrEqualTo asserts that last HTTP request-response time
+// is <= than expected timeInterval.
+// timeInterval should be string acceptable by time.ParseDuration func
+func (apiCtx *APIContext) TimeBetweenLastHTTPRequestResponseShouldBeLessThanOrEqualTo(timeInterval time.Duration) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	lastReqTimestampI, err := apiCtx.Cache.GetSaved(httpcache.LastHTTPRequestTimestamp)
+	if err != nil {
+		return fmt.Errorf("problem during obtaining last HTTP request timestamp, err: %w", err)
+	}
+
+	lastReqTimestamp, ok := lastReqTimestampI.(time.Time)
+	if !ok {
+		return fmt.Errorf("last request timestamp: '%+v' should be time.Time", lastReqTimestampI)
+	}
+
+	lastResTimestampI, err := apiCtx.Cache.GetSaved(httpcache.LastHTTPResponseTimestamp)
+	if err != nil {
+		return fmt.Errorf("problem during obtaining last HTTP response timestamp, err: %w", err)
+	}
+
+	lastResTimestamp, ok := lastResTimestampI.(time.Time)
+	if !ok {
+		return fmt.Errorf("last response timestamp: '%+v' should be time.Time", lastReqTimestampI)
+	}
+
+	timeBetweenReqRes := lastResTimestamp.Sub(lastReqTimestamp)
+	if timeBetweenReqRes > timeInterval {
+		return &AssertionError{Path: "total", Expected: timeInterval, Actual: timeBetweenReqRes,
+			Message: fmt.Sprintf("time between last request - response should be less than %+v, but it took %+v", timeInterval, timeBetweenReqRes)}
+	}
+
+	return nil
+}
+
+// TimeOfLastHTTPRequestPhaseShouldBeLessThanOrEqualTo asserts that given phase of last HTTP(s) request-response cycle
+// took <= than expected timeInterval.
+// phase may be one of: dns, connect, tls, ttfb, download, total.
+func (apiCtx *APIContext) TimeOfLastHTTPRequestPhaseShouldBeLessThanOrEqualTo(phase httpctx.TimingPhase, timeInterval time.Duration) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	timings, err := apiCtx.GetLastResponseTimings()
+	if err != nil {
+		return err
+	}
+
+	phaseDuration, err := timings.Phase(phase)
+	if err != nil {
+		return err
+	}
+
+	if apiCtx.Debugger.IsOn() {
+		apiCtx.Debugger.Print(fmt.Sprintf("last HTTP(s) request timings:\n\n%s", timings))
+	}
+
+	if phaseDuration > timeInterval {
+		return &AssertionError{Path: string(phase), Expected: timeInterval, Actual: phaseDuration,
+			Message: fmt.Sprintf("%s phase of last HTTP(s) request should take less than or equal to %+v, but it took %+v", phase, timeInterval, phaseDuration)}
+	}
+
+	return nil
+}
+
+// TheResponseShouldHaveCookie checks whether last HTTP(s) response has cookie of given name.
+func (apiCtx *APIContext) TheResponseShouldHaveCookie(name string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	lastResp, err := apiCtx.GetLastResponse()
+	if err != nil {
+		return &MissingResponseError{Err: err}
+	}
+
+	defer func() {
+		if apiCtx.Debugger.IsOn() {
+
+			apiCtx.Debugger.Print(fmt.Sprintf("last HTTP(s) response cookies: %+v", lastResp.Cookies()))
+		}
+	}()
+
+	for _, cookie := range lastResp.Cookies() {
+		if cookie.Name == name {
+			return nil
+		}
+	}
+
+	return &AssertionError{Path: name, Message: fmt.Sprintf("last HTTP(s) response does not have cookie with name '%s'", name)}
+}
+
+// TheResponseShouldHaveCookieOfValue checks whether last HTTP(s) response has cookie of given name and value.
+func (apiCtx *APIContext) TheResponseShouldHaveCookieOfValue(name, valueTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	lastResp, err := apiCtx.GetLastResponse()
+	if err != nil {
+		return &MissingResponseError{Err: err}
+	}
+
+	defer func() {
+		if apiCtx.Debugger.IsOn() {
+			apiCtx.Debugger.Print(fmt.Sprintf("last HTTP(s) response cookies: %+v", lastResp.Cookies()))
+		}
+	}()
+
+	value, err := apiCtx.TemplateEngine.Replace(valueTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "value", Err: err}
+	}
+
+	var values []string
+	for _, cookie := range lastResp.Cookies() {
+		if cookie.Name != name {
+			continue
+		}
+
+		if cookie.Value == value {
+			return nil
+		}
+
+		values = append(values, cookie.Value)
+	}
+
+	if len(values) == 0 {
+		return &AssertionError{Path: name, Expected: value,
+			Message: fmt.Sprintf("last HTTP(s) response does not have cookie with name '%s'", name)}
+	}
+
+	return &AssertionError{Path: name, Expected: value, Actual: strings.Join(values, ", "),
+		Message: fmt.Sprintf("last HTTP(s) response cookie '%s' has value not equal to expected:\n%s", name, apiCtx.Differ.Strings(value, values[0]))}
+}
+
+// TheResponseShouldNotHaveCookie checks whether last HTTP(s) response does not have cookie of given name.
+func (apiCtx *APIContext) TheResponseShouldNotHaveCookie(name string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	lastResp, err := apiCtx.GetLastResponse()
+	if err != nil {
+		return &MissingResponseError{Err: err}
+	}
+
+	for _, cookie := range lastResp.Cookies() {
+		if cookie.Name == name {
+			return &AssertionError{Path: name, Actual: cookie.Value,
+				Message: fmt.Sprintf("last HTTP(s) response has cookie with name '%s', but it should not", name)}
+		}
+	}
+
+	return nil
+}
+
+// TheResponseShouldNotHaveCookieOfValue checks whether last HTTP(s) response does not have cookie of given name and value.
+// Missing cookie is treated as cookie of different value.
+func (apiCtx *APIContext) TheResponseShouldNotHaveCookieOfValue(name, valueTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	lastResp, err := apiCtx.GetLastResponse()
+	if err != nil {
+		return &MissingResponseError{Err: err}
+	}
+
+	value, err := apiCtx.TemplateEngine.Replace(valueTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "value", Err: err}
+	}
+
+	for _, cookie := range lastResp.Cookies() {
+		if cookie.Name == name && cookie.Value == value {
+			return &AssertionError{Path: name, Expected: "not " + value, Actual: cookie.Value,
+				Message: fmt.Sprintf("last HTTP(s) response has cookie with name '%s' and value: '%s', but it should not", name, value)}
+		}
+	}
+
+	return nil
+}
+
+// TheResponseCookieShouldHaveAttributeOfValue checks whether cookie of given name from last HTTP(s) response
+// has attribute of provided value. attribute may be one of: Secure, HttpOnly (of value true or false),
+// SameSite (of value Strict, Lax or None), Path or Domain.
+func (apiCtx *APIContext) TheResponseCookieShouldHaveAttributeOfValue(name string, attribute httpctx.CookieAttribute, valueTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	attribute, err = httpctx.ParseCookieAttribute(string(attribute))
+	if err != nil {
+		return err
+	}
+
+	value, err := apiCtx.TemplateEngine.Replace(valueTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "value", Err: err}
+	}
+
+	cookie, err := apiCtx.lastResponseCookie(name)
+	if err != nil {
+		return err
+	}
+
+	actual, err := httpctx.CookieAttributeValue(cookie, attribute)
+	if err != nil {
+		return err
+	}
+
+	if !httpctx.CookieAttributeEqual(attribute, value, actual) {
+		return &AssertionError{Path: name + "; " + string(attribute), Expected: value, Actual: actual,
+			Message: fmt.Sprintf("cookie '%s' attribute %s has value: '%s', expected: '%s'", name, attribute, actual, value)}
+	}
+
+	return nil
+}
+
+// TheResponseCookieShouldExpireAfter checks whether cookie of given name from last HTTP(s) response expires
+// not earlier than timeInterval from now. Max-Age attribute has precedence over Expires.
+func (apiCtx *APIContext) TheResponseCookieShouldExpireAfter(name string, timeInterval time.Duration) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	now := time.Now()
+	expiry, err := apiCtx.lastResponseCookieExpiry(name, now)
+	if err != nil {
+		return err
+	}
+
+	if expiry.Before(now.Add(timeInterval)) {
+		return &AssertionError{Path: name, Expected: "at least " + timeInterval.String(), Actual: expiry.Sub(now).Round(time.Second),
+			Message: fmt.Sprintf("cookie '%s' expires in %s, expected at least %s", name, expiry.Sub(now).Round(time.Second), timeInterval)}
+	}
+
+	return nil
+}
+
+// TheResponseCookieShouldExpireBefore checks whether cookie of given name from last HTTP(s) response expires
+// not later than timeInterval from now. Max-Age attribute has precedence over Expires.
+func (apiCtx *APIContext) TheResponseCookieShouldExpireBefore(name string, timeInterval time.Duration) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	now := time.Now()
+	expiry, err := apiCtx.lastResponseCookieExpiry(name, now)
+	if err != nil {
+		return err
+	}
+
+	if expiry.After(now.Add(timeInterval)) {
+		return &AssertionError{Path: name, Expected: "at most " + timeInterval.String(), Actual: expiry.Sub(now).Round(time.Second),
+			Message: fmt.Sprintf("cookie '%s' expires in %s, expected at most %s", name, expiry.Sub(now).Round(time.Second), timeInterval)}
+	}
+
+	return nil
+}
+
+// TheResponseSessionCookieShouldBeHardened checks whether cookie of given name from last HTTP(s) response
+// has Secure and HttpOnly flags and SameSite attribute set to Strict or Lax. Additionally, cookie with __Host- prefix
+// should have Path=/ and no Domain attribute. All found problems are reported at once.
+func (apiCtx *APIContext) TheResponseSessionCookieShouldBeHardened(name string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	cookie, err := apiCtx.lastResponseCookie(name)
+	if err != nil {
+		return err
+	}
+
+	if issues := httpctx.CookieHardeningIssues(cookie); len(issues) > 0 {
+		return &AssertionError{Path: name, Expected: "hardened cookie", Actual: issues,
+			Message: fmt.Sprintf("cookie '%s' is not hardened:\n- %s", name, strings.Join(issues, "\n- "))}
+	}
+
+	return nil
+}
+
+// TheResponseTLSVersionShouldBe checks whether last HTTP(s) response was obtained over connection with given TLS version.
+// version may be for example: "TLS 1.2", "TLSv1.3" or "1.3".
+func (apiCtx *APIContext) TheResponseTLSVersionShouldBe(version string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	state, err := apiCtx.GetLastResponseTLS()
+	if err != nil {
+		return err
+	}
+
+	expected, err := httpctx.ParseTLSVersion(version)
+	if err != nil {
+		return err
+	}
+
+	if state.Version != expected {
+		return &AssertionError{Path: "TLS version", Expected: httpctx.TLSVersionName(expected), Actual: httpctx.TLSVersionName(state.Version),
+			Message: fmt.Sprintf("expected TLS version %s, but got %s", httpctx.TLSVersionName(expected), httpctx.TLSVersionName(state.Version))}
+	}
+
+	return nil
+}
+
+// TheResponseTLSVersionShouldBeAtLeast checks whether last HTTP(s) response was obtained over connection
+// with given or newer TLS version.
+func (apiCtx *APIContext) TheResponseTLSVersionShouldBeAtLeast(version string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	state, err := apiCtx.GetLastResponseTLS()
+	if err != nil {
+		return err
+	}
+
+	expected, err := httpctx.ParseTLSVersion(version)
+	if err != nil {
+		return err
+	}
+
+	if state.Version < expected {
+		return &AssertionError{Path: "TLS version", Expected: "at least " + httpctx.TLSVersionName(expected), Actual: httpctx.TLSVersionName(state.Version),
+			Message: fmt.Sprintf("expected TLS version at least %s, but got %s", httpctx.TLSVersionName(expected), httpctx.TLSVersionName(state.Version))}
+	}
+
+	return nil
+}
+
+// TheResponseTLSCipherSuiteShouldBe checks whether last HTTP(s) response was obtained over connection
+// with given cipher suite. cipherSuite should be name as defined in crypto/tls, for example: TLS_AES_128_GCM_SHA256.
+func (apiCtx *APIContext) TheResponseTLSCipherSuiteShouldBe(cipherSuite string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	state, err := apiCtx.GetLastResponseTLS()
+	if err != nil {
+		return err
+	}
+
+	actual := tls.CipherSuiteName(state.CipherSuite)
+	if !strings.EqualFold(actual, strings.TrimSpace(cipherSuite)) {
+		return &AssertionError{Path: "TLS cipher suite", Expected: cipherSuite, Actual: actual,
+			Message: fmt.Sprintf("expected TLS cipher suite %s, but got %s", cipherSuite, actual)}
+	}
+
+	return nil
+}
+
+// TheResponseCertificateShouldHaveSubject checks whether leaf certificate of last HTTP(s) response has given subject.
+// subjectTemplate may be subject common name or whole distinguished name, for example: "CN=example.com,O=Acme Co".
+func (apiCtx *APIContext) TheResponseCertificateShouldHaveSubject(subjectTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	cert, err := apiCtx.getLastResponseLeafCertificate()
+	if err != nil {
+		return err
+	}
+
+	subject, err := apiCtx.TemplateEngine.Replace(subjectTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "subject", Err: err}
+	}
+
+	if cert.Subject.CommonName == subject || cert.Subject.String() == subject {
+		return nil
+	}
+
+	return &AssertionError{Path: "certificate subject", Expected: subject, Actual: cert.Subject.String(),
+		Message: fmt.Sprintf("expected certificate subject %s, but got %s", subject, cert.Subject.String())}
+}
+
+// TheResponseCertificateShouldBeValidForHost checks whether leaf certificate of last HTTP(s) response
+// is valid for given host name or IP address, according to its subject alternative names.
+func (apiCtx *APIContext) TheResponseCertificateShouldBeValidForHost(hostTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	cert, err := apiCtx.getLastResponseLeafCertificate()
+	if err != nil {
+		return err
+	}
+
+	host, err := apiCtx.TemplateEngine.Replace(hostTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "host", Err: err}
+	}
+
+	if err = cert.VerifyHostname(host); err != nil {
+		return &AssertionError{Path: "certificate host", Expected: host, Actual: fmt.Sprintf("DNS names: %v, IP addresses: %v", cert.DNSNames, cert.IPAddresses),
+			Message: fmt.Sprintf("certificate is not valid for host %s, DNS names: %v, IP addresses: %v", host, cert.DNSNames, cert.IPAddresses), Err: err}
+	}
+
+	return nil
+}
+
+// TheResponseCertificateShouldBeIssuedBy checks whether leaf certificate of last HTTP(s) response was issued by given issuer.
+// issuerTemplate may be issuer common name, organization or whole distinguished name.
+func (apiCtx *APIContext) TheResponseCertificateShouldBeIssuedBy(issuerTemplate string) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	cert, err := apiCtx.getLastResponseLeafCertificate()
+	if err != nil {
+		return err
+	}
+
+	issuer, err := apiCtx.TemplateEngine.Replace(issuerTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "issuer", Err: err}
+	}
+
+	if cert.Issuer.CommonName == issuer || cert.Issuer.String() == issuer {
+		return nil
+	}
+
+	for _, organization := range cert.Issuer.Organization {
+		if organization == issuer {
+			return nil
+		}
+	}
+
+	return &AssertionError{Path: "certificate issuer", Expected: issuer, Actual: cert.Issuer.String(),
+		Message: fmt.Sprintf("expected certificate issuer %s, but got %s", issuer, cert.Issuer.String())}
+}
+
+// TheResponseCertificateShouldBeValidForAtLeastDays checks whether leaf certificate of last HTTP(s) response
+// does not expire within given number of days.
+func (apiCtx *APIContext) TheResponseCertificateShouldBeValidForAtLeastDays(days int) (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	cert, err := apiCtx.getLastResponseLeafCertificate()
+	if err != nil {
+		return err
+	}
+
+	daysLeft := int(time.Until(cert.NotAfter).Hours() / 24)
+	if daysLeft < days {
+		return &AssertionError{Path: "certificate expiry", Expected: days, Actual: daysLeft,
+			Message: fmt.Sprintf("certificate expires in %d days (%s), expected at least %d days", daysLeft, cert.NotAfter.Format(time.RFC3339), days)}
+	}
+
+	return nil
+}
+
+// TheResponseShouldHaveStapledOCSPResponse checks whether server stapled OCSP response during TLS handshake.
+func (apiCtx *APIContext) TheResponseShouldHaveStapledOCSPResponse() (err error) {
+	defer apiCtx.softAssertion()(&err)
+
+	state, err := apiCtx.GetLastResponseTLS()
+	if err != nil {
+		return err
+	}
+
+	if len(state.OCSPResponse) == 0 {
+		return &AssertionError{Path: "OCSP response", Message: "last HTTP(s) response TLS handshake does not have stapled OCSP response"}
+	}
+
+	return nil
+}
+
+// ISaveAs saves into cache arbitrary passed data.
+func (apiCtx *APIContext) ISaveAs(valueTemplate, cacheKey string) (err error) {
+	defer apiCtx.stepAnnotation()(&err)
+
+	if len(valueTemplate) == 0 {
+		return fmt.Errorf("pass any value")
+	}
+
+	if len(cacheKey) == 0 {
+		return fmt.Errorf("cacheKey should not be empty value")
+	}
+
+	value, err := apiCtx.TemplateEngine.Replace(valueTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "value", Err: err}
+	}
+
+	apiCtx.Cache.Save(cacheKey, value)
+
+	return nil
+}
+
+// ISaveFromTheLastResponseNodeAs saves from last response body node under given cacheKey key.
+// expr should be valid according to injected PathResolver of given data type
+func (apiCtx *APIContext) ISaveFromTheLastResponseNodeAs(dataFormat format.DataFormat, exprTemplate, cacheKey string) (err error) {
+	defer apiCtx.stepAnnotation()(&err)
+
+	body, err := apiCtx.GetLastResponseBody()
+	if err != nil {
+		return err
+	}
+
+	expr, err := apiCtx.TemplateEngine.Replace(exprTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "expression", Err: err}
+	}
+
+	var iVal interface{}
+	switch dataFormat {
+	case format.JSON:
+		iVal, err = apiCtx.PathFinders.JSON.Find(expr, body)
+	case format.YAML:
+		iVal, err = apiCtx.PathFinders.YAML.Find(expr, body)
+	case format.XML:
+		iVal, err = apiCtx.PathFinders.XML.Find(expr, body)
+	default:
+		return fmt.Errorf("provided unknown format: %s, format should be one of : %s, %s, %s",
+			dataFormat, format.JSON, format.YAML, format.XML)
+	}
+
+	if err != nil {
+		if apiCtx.Debugger.IsOn() {
+			apiCtx.Debugger.Print(fmt.Sprintf("last response body:\n\n%s", body))
+		}
+
+		return &PathFinderError{Expr: expr, Err: err}
+	}
+
+	apiCtx.Cache.Save(cacheKey, iVal)
+
+	return nil
+}
+
+// IWait waits for given timeInterval amount of time
+func (apiCtx *APIContext) IWait(timeInterval time.Duration) error {
+	time.Sleep(timeInterval)
+
+	return nil
+}
+
+// IPrintLastResponseBody prints last response from request.
+func (apiCtx *APIContext) IPrintLastResponseBody() (err error) {
+	defer apiCtx.stepAnnotation()(&err)
+
+	var tmp interface{}
+
+	body, err := apiCtx.GetLastResponseBody()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			apiCtx.Debugger.Print(string(body))
+		}
+	}()
+
+	if format.IsYAML(body) {
+		err = apiCtx.Formatters.YAML.Deserialize(body, &tmp)
+		if err != nil {
+			return nil
+		}
+
+		indentedRespBody, err := yaml.Marshal(tmp)
+		if err != nil {
+			return nil
+		}
+
+		apiCtx.Debugger.Print(string(indentedRespBody))
+		return nil
+	}
+
+	if format.IsJSON(body) {
+		err = apiCtx.Formatters.JSON.Deserialize(body, &tmp)
+		if err != nil {
+			return nil
+		}
+
+		indentedRespBody, err := json.MarshalIndent(tmp, "", "\t")
+		if err != nil {
+			return nil
+		}
+
+		apiCtx.Debugger.Print(string(indentedRespBody))
+		return nil
+	}
+
+	if format.IsXML(body) {
+		err = apiCtx.Formatters.XML.Deserialize(body, &tmp)
+		if err != nil {
+			return nil
+		}
+
+		indentedRespBody, err := xml.MarshalIndent(tmp, "", "\t")
+		if err != nil {
+			return nil
+		}
+
+		apiCtx.Debugger.Print(string(indentedRespBody))
+	}
+
+	apiCtx.Debugger.Print(string(body))
+	return nil
+}
+
+// IStartDebugMode starts debugging mode
+func (apiCtx *APIContext) IStartDebugMode() error {
+	apiCtx.Debugger.TurnOn()
+
+	return nil
+}
+
+// IStopDebugMode stops debugging mode
+func (apiCtx *APIContext) IStopDebugMode() error {
+	apiCtx.Debugger.TurnOff()
+
+	return nil
+}
+
+// IStartSoftAssertions starts soft assertions mode. In this mode, failed assertions are recorded instead of returned,
+// so scenario continues. Recorded failures are reported together by AllSoftAssertionsShouldPass. Errors other than
+// assertion failures, for example missing response or template engine problem, are returned immediately.
+func (apiCtx *APIContext) IStartSoftAssertions() error {
+	apiCtx.softAssertions = true
+
+	return nil
+}
+
+// AllSoftAssertionsShouldPass stops soft assertions mode and returns aggregated report of all failures
+// recorded since soft assertions mode was started. It may be used as closing step or called in scenario hook.
+func (apiCtx *APIContext) AllSoftAssertionsShouldPass() error {
+	failures := apiCtx.softAssertionFailures
+	apiCtx.softAssertions = false
+	apiCtx.softAssertionFailures = nil
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	return &SoftAssertionsError{Failures: failures}
+}
+
+// GetPreparedRequest returns prepared request from cache or error if failed
+func (apiCtx *APIContext) GetPreparedRequest(cacheKey string) (*http.Request, error) {
+	reqInterface, err := apiCtx.Cache.GetSaved(cacheKey)
+	if err != nil {
+		return &http.Request{}, fmt.Errorf("could not obtain %s from cache, err: %w", cacheKey, err)
+	}
+
+	req, ok := reqInterface.(*http.Request)
+	if !ok {
+		return &http.Request{}, fmt.Errorf("value under key %s in cache doesn't contain *http.Request", cacheKey)
+	}
+
+	return req, nil
+}
+
+// GetLastResponse returns last HTTP(s) response.
+func (apiCtx *APIContext) GetLastResponse() (*http.Response, error) {
+	respInterface, err := apiCtx.Cache.GetSaved(httpcache.LastHTTPResponseCacheKey)
+	if err != nil {
+		return nil, fmt.Errorf("missing last HTTP(s) response, err: %s", err.Error())
+	}
+
+	lastResp, ok := respInterface.(*http.Response)
+	if !ok {
+		return nil, fmt.Errorf("last HTTP(s) response data structure is not type *http.Response")
+	}
+
+	if lastResp == nil {
+		return nil, fmt.Errorf("missing last HTTP(s) response")
+	}
+
+	return lastResp, nil
+}
+
+// GetLastResponseTimings returns timings of last HTTP(s) request-response phases.
+func (apiCtx *APIContext) GetLastResponseTimings() (httpctx.Timings, error) {
+	timingsInterface, err := apiCtx.Cache.GetSaved(httpcache.LastHTTPResponseTimings)
+	if err != nil {
+		return httpctx.Timings{}, fmt.Errorf("missing last HTTP(s) response timings, err: %w", err)
 	}
 
-	if header == value {
-		return nil
+	timings, ok := timingsInterface.(httpctx.Timings)
+	if !ok {
+		return httpctx.Timings{}, fmt.Errorf("last HTTP(s) response timings data structure is not type httpctx.Timings")
 	}
 
-	return fmt.Errorf("%s header exists but, expected value: %s, is not equal to actual: %s", name, value, header)
+	return timings, nil
 }
 
-// IValidateLastResponseBodyWithSchemaReference validates last response body against schema as provided in referenceTemplate.
-// referenceTemplate may be: URL or full/relative path
-func (apiCtx *APIContext) IValidateLastResponseBodyWithSchemaReference(referenceTemplate string) error {
-	reference, err := apiCtx.TemplateEngine.Replace(referenceTemplate, apiCtx.Cache.All())
+// GetLastResponseTLS returns TLS connection state of last HTTP(s) response.
+func (apiCtx *APIContext) GetLastResponseTLS() (*tls.ConnectionState, error) {
+	lastResp, err := apiCtx.GetLastResponse()
+	if err != nil {
+		return nil, &MissingResponseError{Err: err}
+	}
+
+	if lastResp.TLS == nil {
+		return nil, errors.New("last HTTP(s) response was not obtained over TLS connection")
+	}
+
+	return lastResp.TLS, nil
+}
+
+// GetLastResponseBody returns last HTTP(s) response body.
+// internally method creates new NoPCloser on last response so this method is safe to reuse many times
+func (apiCtx *APIContext) GetLastResponseBody() ([]byte, error) {
+	lastResponse, err := apiCtx.GetLastResponse()
+	if err != nil {
+		return []byte(""), &MissingResponseError{Body: true, Err: err}
+	}
+
+	var bodyBytes []byte
+
+	if lastResponse != nil {
+		bodyBytes, _ = ioutil.ReadAll(lastResponse.Body)
+		defer lastResponse.Body.Close()
+
+		// last response body may be read again
+		lastResponse.Body = ioutil.NopCloser(bytes.NewBuffer(bodyBytes))
+	}
+
+	return bodyBytes, nil
+}
+
+// getLastResponseLeafCertificate returns leaf certificate presented by server during last HTTP(s) request.
+func (apiCtx *APIContext) getLastResponseLeafCertificate() (*x509.Certificate, error) {
+	state, err := apiCtx.GetLastResponseTLS()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(state.PeerCertificates) == 0 {
+		return nil, errors.New("server did not present any certificate during last HTTP(s) request")
+	}
+
+	return state.PeerCertificates[0], nil
+}
+
+// requestFromHTTPFile creates request of given name from .http file.
+func (apiCtx *APIContext) requestFromHTTPFile(requestName, pathTemplate string) (*http.Request, error) {
+	pth, err := apiCtx.TemplateEngine.Replace(pathTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return nil, &TemplateError{Name: "path", Err: err}
+	}
+
+	file, err := httpfile.Load(pth)
+	if err != nil {
+		return nil, err
+	}
+
+	fileRequest, err := file.Request(requestName)
+	if err != nil {
+		return nil, fmt.Errorf("file %s, err: %w", pth, err)
+	}
+
+	fromCache := func(name string) (string, bool) {
+		value, err := apiCtx.Cache.GetSaved(name)
+		if err != nil {
+			return "", false
+		}
+
+		return fmt.Sprint(value), true
+	}
+
+	return fileRequest.HTTPRequest(file.Dir, file.Lookup(fromCache))
+}
+
+// compareNumericNode compares numeric node with number from valueTemplate using provided comparison.
+func (apiCtx *APIContext) compareNumericNode(dataFormat format.DataFormat, exprTemplate, valueTemplate, relation string, comparison func(node, value float64) bool) error {
+	value, err := apiCtx.templateNumber(valueTemplate, "value")
+	if err != nil {
+		return err
+	}
+
+	expr, node, err := apiCtx.numericNode(dataFormat, exprTemplate)
 	if err != nil {
-		return fmt.Errorf("template engine has problem with 'reference' template, err: %w", err)
+		return err
+	}
+
+	if !comparison(node, value) {
+		return &AssertionError{Path: expr, Expected: fmt.Sprintf("%s %v", relation, value), Actual: node,
+			Message: fmt.Sprintf("node %s value %v is not %s %v", expr, node, relation, value)}
+	}
+
+	return nil
+}
+
+// numericNode obtains node from last HTTP(s) response body and converts it to number.
+func (apiCtx *APIContext) numericNode(dataFormat format.DataFormat, exprTemplate string) (string, float64, error) {
+	expr, err := apiCtx.TemplateEngine.Replace(exprTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return "", 0, &TemplateError{Name: "expression", Err: err}
 	}
 
 	body, err := apiCtx.GetLastResponseBody()
 	if err != nil {
-		return fmt.Errorf("could not obtain last HTTP(s) response body, err: %w", err)
+		return "", 0, err
 	}
 
-	if apiCtx.Debugger.IsOn() {
-		apiCtx.Debugger.Print(fmt.Sprintf("'%s' was replaced as: '%s'\n", referenceTemplate, reference))
+	iValue, err := apiCtx.findNode(dataFormat, expr, body)
+	if err != nil {
+		return "", 0, &PathFinderError{Expr: expr, Err: err}
+	}
+
+	node, err := mathutils.ToFloat64(iValue)
+	if err != nil {
+		return "", 0, &AssertionError{Path: expr, Expected: "number", Actual: iValue,
+			Message: fmt.Sprintf("node %s, err: %v", expr, err), Err: err}
 	}
 
-	return apiCtx.SchemaValidators.ReferenceValidator.Validate(string(body), reference)
+	return expr, node, nil
 }
 
-// IValidateLastResponseBodyWithSchemaString validates last response body against schema.
-func (apiCtx *APIContext) IValidateLastResponseBodyWithSchemaString(schema string) error {
+// templateNumber replaces template values in numberTemplate and converts result to number.
+func (apiCtx *APIContext) templateNumber(numberTemplate, name string) (float64, error) {
+	replaced, err := apiCtx.TemplateEngine.Replace(numberTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return 0, &TemplateError{Name: name, Err: err}
+	}
+
+	number, err := mathutils.ToFloat64(replaced)
+	if err != nil {
+		return 0, fmt.Errorf("replaced '%s' template, err: %w", name, err)
+	}
+
+	return number, nil
+}
+
+// sliceNode obtains node from last HTTP(s) response body, which should be slice.
+func (apiCtx *APIContext) sliceNode(dataFormat format.DataFormat, exprTemplate string) (string, []interface{}, error) {
+	expr, err := apiCtx.TemplateEngine.Replace(exprTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return "", nil, &TemplateError{Name: "expression", Err: err}
+	}
+
 	body, err := apiCtx.GetLastResponseBody()
 	if err != nil {
-		return fmt.Errorf("could not obtain last HTTP(s) response body, err: %w", err)
+		return "", nil, err
+	}
+
+	iValue, err := apiCtx.findNode(dataFormat, expr, body)
+	if err != nil {
+		if apiCtx.Debugger.IsOn() {
+			apiCtx.Debugger.Print(fmt.Sprintf("last response body:\n\n%s", body))
+		}
+
+		return "", nil, &PathFinderError{Expr: expr, Err: err}
 	}
 
-	return apiCtx.SchemaValidators.StringValidator.Validate(string(body), schema)
+	slice, ok := collection.ToSlice(iValue)
+	if !ok {
+		return "", nil, &AssertionError{Path: expr, Expected: "slice", Actual: iValue,
+			Message: fmt.Sprintf("%s does not point at slice(array) in last HTTP(s) response body", expr)}
+	}
+
+	return expr, slice, nil
 }
 
-// IValidateNodeWithSchemaString validates last response body JSON node against schema
-func (apiCtx *APIContext) IValidateNodeWithSchemaString(dataFormat format.DataFormat, exprTemplate, schemaTemplate string) error {
-	return apiCtx.iValidateNodeWithSchemaGeneral(dataFormat, exprTemplate, schemaTemplate, apiCtx.SchemaValidators.StringValidator)
+// elementsSubNodes obtains node pointed by elementExprTemplate from every element.
+// Empty expression or "$" means element itself.
+func (apiCtx *APIContext) elementsSubNodes(dataFormat format.DataFormat, elements []interface{}, elementExprTemplate string) (string, []interface{}, error) {
+	elementExpr, err := apiCtx.TemplateEngine.Replace(elementExprTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return "", nil, &TemplateError{Name: "element expression", Err: err}
+	}
+
+	elementExpr = strings.TrimSpace(elementExpr)
+	if elementExpr == "" || elementExpr == "$" {
+		return elementExpr, elements, nil
+	}
+
+	var serialize func(v interface{}) ([]byte, error)
+	switch dataFormat {
+	case format.JSON:
+		serialize = apiCtx.Formatters.JSON.Serialize
+	case format.YAML:
+		serialize = apiCtx.Formatters.YAML.Serialize
+	default:
+		return "", nil, fmt.Errorf("this method does not support elements sub-nodes in format: %s", dataFormat)
+	}
+
+	values := make([]interface{}, 0, len(elements))
+	for i, element := range elements {
+		data, err := serialize(element)
+		if err != nil {
+			return "", nil, fmt.Errorf("could not serialize element %d, err: %w", i, err)
+		}
+
+		value, err := apiCtx.findNode(dataFormat, elementExpr, data)
+		if err != nil {
+			return "", nil, fmt.Errorf("element %d, err: %w", i, &PathFinderError{Expr: elementExpr, Err: err})
+		}
+
+		values = append(values, value)
+	}
+
+	return elementExpr, values, nil
 }
 
-// IValidateNodeWithSchemaReference validates last response body node against schema as provided in referenceTemplate
-func (apiCtx *APIContext) IValidateNodeWithSchemaReference(dataFormat format.DataFormat, exprTemplate, referenceTemplate string) error {
-	return apiCtx.iValidateNodeWithSchemaGeneral(dataFormat, exprTemplate, referenceTemplate, apiCtx.SchemaValidators.ReferenceValidator)
+// matchElements checks for every element of slice node whether its sub-node is equal to value from valueTemplate.
+// Returned mismatches describe difference for each element, empty description means equal sub-node.
+func (apiCtx *APIContext) matchElements(dataFormat format.DataFormat, exprTemplate, elementExprTemplate, valueTemplate string) (elementsMatch, error) {
+	if dataFormat == format.XML {
+		return elementsMatch{}, fmt.Errorf("this method does not support data in format: %s", format.XML)
+	}
+
+	value, err := apiCtx.TemplateEngine.Replace(valueTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return elementsMatch{}, &TemplateError{Name: "value", Err: err}
+	}
+
+	expr, slice, err := apiCtx.sliceNode(dataFormat, exprTemplate)
+	if err != nil {
+		return elementsMatch{}, err
+	}
+
+	elementExpr, values, err := apiCtx.elementsSubNodes(dataFormat, slice, elementExprTemplate)
+	if err != nil {
+		return elementsMatch{}, err
+	}
+
+	match := elementsMatch{expr: expr, elementExpr: elementExpr, expected: collection.ParseValue(value), values: values}
+	match.mismatches = make([]string, len(values))
+	for i, v := range values {
+		if !collection.Equal(match.expected, v) {
+			match.mismatches[i] = apiCtx.Differ.Values(match.expected, v)
+		}
+	}
+
+	return match, nil
 }
 
-// TimeBetweenLastHTTPRequestResponseShouldBeLessThanOrEqualTo asserts that last HTTP request-response time
-// is <= than expected timeInterval.
-// timeInterval should be string acceptable by time.ParseDuration func
-func (apiCtx *APIContext) TimeBetweenLastHTTPRequestResponseShouldBeLessThanOrEqualTo(timeInterval time.Duration) error {
-	lastReqTimestampI, err := apiCtx.Cache.GetSaved(httpcache.LastHTTPRequestTimestamp)
+// elementsMatch holds result of comparing sub-nodes of slice elements with expected value.
+type elementsMatch struct {
+	expr, elementExpr string
+	expected          interface{}
+	values            []interface{}
+
+	// mismatches holds difference for each element, empty string when element matches.
+	mismatches []string
+}
+
+// timeNode obtains node from last HTTP(s) response body and parses it as time in given layout.
+func (apiCtx *APIContext) timeNode(dataFormat format.DataFormat, exprTemplate, layout string) (string, time.Time, error) {
+	expr, err := apiCtx.TemplateEngine.Replace(exprTemplate, apiCtx.Cache.All())
 	if err != nil {
-		return fmt.Errorf("problem during obtaining last HTTP request timestamp, err: %w", err)
+		return "", time.Time{}, &TemplateError{Name: "expression", Err: err}
 	}
 
-	lastReqTimestamp, ok := lastReqTimestampI.(time.Time)
-	if !ok {
-		return fmt.Errorf("last request timestamp: '%+v' should be time.Time", lastReqTimestampI)
+	body, err := apiCtx.GetLastResponseBody()
+	if err != nil {
+		return "", time.Time{}, err
 	}
 
-	lastResTimestampI, err := apiCtx.Cache.GetSaved(httpcache.LastHTTPResponseTimestamp)
+	iValue, err := apiCtx.findNode(dataFormat, expr, body)
 	if err != nil {
-		return fmt.Errorf("problem during obtaining last HTTP response timestamp, err: %w", err)
+		return "", time.Time{}, &PathFinderError{Expr: expr, Err: err}
 	}
 
-	lastResTimestamp, ok := lastResTimestampI.(time.Time)
+	t, err := timeutils.ParseTime(iValue, layout)
+	if err != nil {
+		return "", time.Time{}, &AssertionError{Path: expr, Expected: "time in layout " + layout, Actual: iValue,
+			Message: fmt.Sprintf("node %s, err: %v", expr, err), Err: err}
+	}
+
+	return expr, t, nil
+}
+
+// timeNodeAndCachedTime obtains time from last HTTP(s) response body node and time saved in cache under cacheKey.
+func (apiCtx *APIContext) timeNodeAndCachedTime(dataFormat format.DataFormat, exprTemplate, layout, cacheKey string) (string, time.Time, time.Time, error) {
+	cached, err := apiCtx.Cache.GetSaved(cacheKey)
+	if err != nil {
+		return "", time.Time{}, time.Time{}, err
+	}
+
+	reference, ok := cached.(time.Time)
 	if !ok {
-		return fmt.Errorf("last response timestamp: '%+v' should be time.Time", lastReqTimestampI)
+		return "", time.Time{}, time.Time{}, fmt.Errorf("value under key %s in cache is not time.Time, got: %T", cacheKey, cached)
+	}
+
+	expr, t, err := apiCtx.timeNode(dataFormat, exprTemplate, layout)
+	if err != nil {
+		return "", time.Time{}, time.Time{}, err
+	}
+
+	return expr, t, reference, nil
+}
+
+// softAssertion should be deferred at the beginning of every assertion method: defer apiCtx.softAssertion()(&err).
+// In soft assertions mode, assertion failure of outermost step is recorded together with assertion name and cleared.
+// Other errors, for example *TemplateError or *MissingResponseError, and errors of steps called by other steps
+// are returned as usual.
+func (apiCtx *APIContext) softAssertion() func(err *error) {
+	return apiCtx.trackStep(callerStepName(), true)
+}
+
+// stepAnnotation should be deferred at the beginning of every step, which is not an assertion:
+// defer apiCtx.stepAnnotation()(&err). Error of outermost step is annotated with step name.
+func (apiCtx *APIContext) stepAnnotation() func(err *error) {
+	return apiCtx.trackStep(callerStepName(), false)
+}
+
+// trackStep increments depth of nested steps and returns function, which decrements it and annotates error
+// of outermost step with its name. Assertion failure of soft assertion is recorded and cleared in soft assertions mode.
+func (apiCtx *APIContext) trackStep(name string, softAssertion bool) func(err *error) {
+	apiCtx.stepDepth++
+
+	return func(err *error) {
+		apiCtx.stepDepth--
+		if apiCtx.stepDepth > 0 || *err == nil {
+			return
+		}
+
+		*err = withStep(name, *err)
+		if !softAssertion || !apiCtx.softAssertions || !errors.Is(*err, ErrAssertion) {
+			return
+		}
+
+		apiCtx.softAssertionFailures = append(apiCtx.softAssertionFailures, *err)
+		if apiCtx.Debugger.IsOn() {
+			apiCtx.Debugger.Print(fmt.Sprintf("soft assertion %s failed, err: %s", name, (*err).Error()))
+		}
+
+		*err = nil
+	}
+}
+
+// closureSuffix matches suffix of names of functions returned by steps, for example: .func1.
+var closureSuffix = regexp.MustCompile(`\.func\d+(\.\d+)*$`)
+
+// callerStepName returns name of APIContext method, which called softAssertion or stepAnnotation.
+func callerStepName() string {
+	name := "assertion"
+	if pc, _, _, ok := runtime.Caller(2); ok {
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			name = closureSuffix.ReplaceAllString(fn.Name(), "")
+			name = name[strings.LastIndex(name, ".")+1:]
+		}
 	}
 
-	timeBetweenReqRes := lastResTimestamp.Sub(lastReqTimestamp)
-	if timeBetweenReqRes > timeInterval {
-		return fmt.Errorf("time between last request - response should be less than %+v, but it took %+v", timeInterval, timeBetweenReqRes)
+	return name
+}
+
+// nodeValueMismatch compares node value with expected one of given dataType. Returned mismatch describes
+// difference between values, whereas err means that comparison could not be performed.
+func (apiCtx *APIContext) nodeValueMismatch(expr string, iValue interface{}, dataType, expected string) (mismatch, err error) {
+	switch dataType {
+	case "string":
+		strVal, ok := iValue.(string)
+		if !ok {
+			return fmt.Errorf("expected %s to be %s, got %v", expr, dataType, iValue), nil
+		}
+
+		if strVal != expected {
+			return fmt.Errorf("node %s string value is not equal to expected string value:\n%s", expr, apiCtx.Differ.Strings(expected, strVal)), nil
+		}
+	case "int":
+		intNodeValue, err := strconv.Atoi(expected)
+		if err != nil {
+			return nil, fmt.Errorf("replaced node %s value %s could not be converted to int", expr, expected)
+		}
+
+		if strVal, ok := iValue.(string); ok {
+			if intVal, errAtoi := strconv.Atoi(strVal); errAtoi == nil {
+				if intVal != intNodeValue {
+					return fmt.Errorf("node %s int value is not equal to expected int value:\n%s", expr, apiCtx.Differ.Values(intNodeValue, intVal)), nil
+				}
+
+				return nil, nil
+			}
+		}
+
+		floatVal, ok := iValue.(float64)
+		if !ok {
+			uint64Val, ok := iValue.(uint64)
+			if !ok {
+				return fmt.Errorf("expected %s to be %s, got %v", expr, dataType, iValue), nil
+			}
+
+			floatVal = float64(uint64Val)
+		}
+
+		intVal := int(floatVal)
+		if intVal != intNodeValue {
+			return fmt.Errorf("node %s int value is not equal to expected int value:\n%s", expr, apiCtx.Differ.Values(intNodeValue, intVal)), nil
+		}
+	case "float":
+		floatNodeValue, err := strconv.ParseFloat(expected, 64)
+		if err != nil {
+			return nil, fmt.Errorf("replaced node %s value %s could not be converted to float64", expr, expected)
+		}
+
+		if strVal, ok := iValue.(string); ok {
+			if floatVal, errParseFloat := strconv.ParseFloat(strVal, 64); errParseFloat == nil {
+				if floatVal != floatNodeValue {
+					return fmt.Errorf("node %s float value is not equal to expected float value:\n%s", expr, apiCtx.Differ.Values(floatNodeValue, floatVal)), nil
+				}
+
+				return nil, nil
+			}
+		}
+
+		floatVal, ok := iValue.(float64)
+		if !ok {
+			return fmt.Errorf("expected %s to be %s, got %v", expr, dataType, iValue), nil
+		}
+
+		if floatVal != floatNodeValue {
+			return fmt.Errorf("node %s float value is not equal to expected float value:\n%s", expr, apiCtx.Differ.Values(floatNodeValue, floatVal)), nil
+		}
+	case "bool":
+		boolVal, ok := iValue.(bool)
+		if !ok {
+			strVal, ok := iValue.(string)
+			if !ok {
+				return fmt.Errorf("expected %s to be %s, got %v", expr, dataType, iValue), nil
+			}
+
+			boolVal, err = strconv.ParseBool(strVal)
+			if err != nil {
+				return fmt.Errorf("expected %s to be %s, got %v", expr, dataType, iValue), nil
+			}
+		}
+
+		boolNodeValue, err := strconv.ParseBool(expected)
+		if err != nil {
+			return nil, fmt.Errorf("replaced node %s value %s could not be converted to bool", expr, expected)
+		}
+
+		if boolVal != boolNodeValue {
+			return fmt.Errorf("node %s bool value is not equal to expected bool value:\n%s", expr, apiCtx.Differ.Values(boolNodeValue, boolVal)), nil
+		}
 	}
 
-	return nil
+	return nil, nil
 }
 
-// TheResponseShouldHaveCookie checks whether last HTTP(s) response has cookie of given name.
-func (apiCtx *APIContext) TheResponseShouldHaveCookie(name string) error {
+// lastResponseHeaderDirectives returns directives of given header of last HTTP(s) response, parsed as RFC 8941 dictionary.
+// Cache-Control header, which real values often are not valid dictionaries, falls back to lenient httpctx.ParseDirectives.
+func (apiCtx *APIContext) lastResponseHeaderDirectives(name string) ([]httpctx.Directive, error) {
 	lastResp, err := apiCtx.GetLastResponse()
 	if err != nil {
-		return fmt.Errorf("could not obtain last HTTP(s) response, err: %w", err)
+		return nil, &MissingResponseError{Err: err}
 	}
 
-	defer func() {
-		if apiCtx.Debugger.IsOn() {
+	values := lastResp.Header.Values(name)
+	if len(values) == 0 {
+		return nil, &AssertionError{Path: name, Message: fmt.Sprintf("could not find header %s in last HTTP(s) response", name)}
+	}
 
-			apiCtx.Debugger.Print(fmt.Sprintf("last HTTP(s) response cookies: %+v", lastResp.Cookies()))
-		}
-	}()
+	dict, err := httpctx.ParseDictionary(strings.Join(values, ", "))
+	if err == nil {
+		return dict.Directives(), nil
+	}
 
-	for _, cookie := range lastResp.Cookies() {
-		if cookie.Name == name {
-			return nil
-		}
+	if strings.EqualFold(name, "Cache-Control") {
+		return httpctx.ParseDirectives(values), nil
 	}
 
-	return fmt.Errorf("last HTTP(s) response does not have cookie with name '%s'", name)
+	return nil, fmt.Errorf("could not parse %s header as structured field, err: %w", name, err)
 }
 
-// TheResponseShouldHaveCookieOfValue checks whether last HTTP(s) response has cookie of given name and value.
-func (apiCtx *APIContext) TheResponseShouldHaveCookieOfValue(name, valueTemplate string) error {
+// lastResponseCookie returns cookie of given name from last HTTP(s) response.
+func (apiCtx *APIContext) lastResponseCookie(name string) (*http.Cookie, error) {
 	lastResp, err := apiCtx.GetLastResponse()
 	if err != nil {
-		return fmt.Errorf("could not obtain last HTTP(s) response, err: %w", err)
+		return nil, &MissingResponseError{Err: err}
 	}
 
-	defer func() {
-		if apiCtx.Debugger.IsOn() {
-			apiCtx.Debugger.Print(fmt.Sprintf("last HTTP(s) response cookies: %+v", lastResp.Cookies()))
+	for _, cookie := range lastResp.Cookies() {
+		if cookie.Name == name {
+			return cookie, nil
 		}
-	}()
+	}
 
-	value, err := apiCtx.TemplateEngine.Replace(valueTemplate, apiCtx.Cache.All())
+	return nil, &AssertionError{Path: name, Message: fmt.Sprintf("last HTTP(s) response does not have cookie with name '%s'", name)}
+}
+
+// lastResponseCookieExpiry returns moment when cookie of given name from last HTTP(s) response expires.
+func (apiCtx *APIContext) lastResponseCookieExpiry(name string, now time.Time) (time.Time, error) {
+	cookie, err := apiCtx.lastResponseCookie(name)
 	if err != nil {
-		return fmt.Errorf("template engine has problem with 'value' template, err: %w", err)
+		return time.Time{}, err
 	}
 
-	for _, cookie := range lastResp.Cookies() {
-		if cookie.Name == name && cookie.Value == value {
-			return nil
-		}
+	expiry, ok := httpctx.CookieExpiry(cookie, now)
+	if !ok {
+		return time.Time{}, &AssertionError{Path: name, Expected: "Max-Age or Expires attribute",
+			Message: fmt.Sprintf("cookie '%s' is session cookie, it has neither Max-Age nor Expires attribute", name)}
 	}
 
-	return fmt.Errorf("last HTTP(s) response does not have cookie with name '%s' and value: '%s'", name, value)
+	return expiry, nil
 }
 
-// ISaveAs saves into cache arbitrary passed data.
-func (apiCtx *APIContext) ISaveAs(valueTemplate, cacheKey string) error {
-	if len(valueTemplate) == 0 {
-		return fmt.Errorf("pass any value")
+// unexpectedStatusCodeError describes unexpected status code of last HTTP(s) response with its reason phrase.
+// Excerpt of response body is attached when enabled with SetStatusCodeBodyExcerptLength.
+func (apiCtx *APIContext) unexpectedStatusCodeError(lastResponse *http.Response, expected string) error {
+	status := strings.TrimSpace(fmt.Sprintf("%d %s", lastResponse.StatusCode, http.StatusText(lastResponse.StatusCode)))
+	if strings.HasPrefix(lastResponse.Status, strconv.Itoa(lastResponse.StatusCode)+" ") {
+		status = lastResponse.Status
 	}
 
-	if len(cacheKey) == 0 {
-		return fmt.Errorf("cacheKey should not be empty value")
+	assertionErr := &AssertionError{Path: "status code", Expected: expected, Actual: lastResponse.StatusCode,
+		Message: fmt.Sprintf("expected status code %s, but got %s", expected, status)}
+	if apiCtx.statusCodeBodyExcerptLength <= 0 || lastResponse.Body == nil {
+		return assertionErr
 	}
 
-	value, err := apiCtx.TemplateEngine.Replace(valueTemplate, apiCtx.Cache.All())
+	body, err := apiCtx.GetLastResponseBody()
 	if err != nil {
-		return fmt.Errorf("template engine has problem with 'value' template, err: %w", err)
+		return assertionErr
 	}
 
-	apiCtx.Cache.Save(cacheKey, value)
+	assertionErr.Message += ", response body: " + stringutils.Truncate(string(body), apiCtx.statusCodeBodyExcerptLength)
 
-	return nil
+	return assertionErr
 }
 
-// ISaveFromTheLastResponseNodeAs saves from last response body node under given cacheKey key.
-// expr should be valid according to injected PathResolver of given data type
-func (apiCtx *APIContext) ISaveFromTheLastResponseNodeAs(dataFormat format.DataFormat, exprTemplate, cacheKey string) error {
-	body, err := apiCtx.GetLastResponseBody()
-	if err != nil {
-		return fmt.Errorf("could not obtain last HTTP(s) response body, err: %w", err)
-	}
-
-	expr, err := apiCtx.TemplateEngine.Replace(exprTemplate, apiCtx.Cache.All())
-	if err != nil {
-		return fmt.Errorf("template engine has problem with 'expression' template, err: %w", err)
+// recognizeFormat returns data format of data, format.PlainText when data is not in JSON, YAML nor XML format.
+func recognizeFormat(data []byte) format.DataFormat {
+	switch {
+	case format.IsJSON(data):
+		return format.JSON
+	case format.IsYAML(data):
+		return format.YAML
+	case format.IsXML(data):
+		return format.XML
+	default:
+		return format.PlainText
 	}
+}
 
-	var iVal interface{}
+// findNode obtains node from data in provided data format using injected PathFinders.
+func (apiCtx *APIContext) findNode(dataFormat format.DataFormat, expr string, data []byte) (interface{}, error) {
 	switch dataFormat {
 	case format.JSON:
-		iVal, err = apiCtx.PathFinders.JSON.Find(expr, body)
+		return apiCtx.PathFinders.JSON.Find(expr, data)
 	case format.YAML:
-		iVal, err = apiCtx.PathFinders.YAML.Find(expr, body)
+		return apiCtx.PathFinders.YAML.Find(expr, data)
 	case format.XML:
-		iVal, err = apiCtx.PathFinders.XML.Find(expr, body)
+		return apiCtx.PathFinders.XML.Find(expr, data)
 	default:
-		return fmt.Errorf("provided unknown format: %s, format should be one of : %s, %s, %s",
+		return nil, fmt.Errorf("provided unknown format: %s, format should be one of : %s, %s, %s",
 			dataFormat, format.JSON, format.YAML, format.XML)
 	}
+}
+
+// insecureRequestDoer returns copy of RequestDoer, which does not verify TLS certificates.
+// Copy is built once and rebuilt only when RequestDoer is replaced.
+func (apiCtx *APIContext) insecureRequestDoer() (httpctx.RequestDoer, error) {
+	if cli, ok := apiCtx.RequestDoer.(*http.Client); ok && cli == apiCtx.insecureDoerOf && apiCtx.insecureDoer != nil {
+		return apiCtx.insecureDoer, nil
+	}
 
+	insecureDoer, err := httpctx.InsecureRequestDoer(apiCtx.RequestDoer)
 	if err != nil {
-		if apiCtx.Debugger.IsOn() {
-			apiCtx.Debugger.Print(fmt.Sprintf("last response body:\n\n%s", body))
+		return nil, err
+	}
+
+	apiCtx.insecureDoer = insecureDoer
+	apiCtx.insecureDoerOf, _ = apiCtx.RequestDoer.(*http.Client)
+
+	return insecureDoer, nil
+}
+
+// sendRequest sends provided HTTP(s) request and preserves obtained response as the last one.
+func (apiCtx *APIContext) sendRequest(req *http.Request) error {
+	if apiCtx.Debugger.IsOn() {
+		command, _ := http2curl.GetCurlCommand(req)
+		apiCtx.Debugger.Print(command.String())
+	}
+
+	doer := apiCtx.RequestDoer
+	if httpctx.IsInsecureSkipVerify(req) {
+		insecureDoer, err := apiCtx.insecureRequestDoer()
+		if err != nil {
+			return fmt.Errorf("could not send request %s %s, err: %w", req.Method, req.URL.String(), err)
 		}
 
-		return err
+		doer = insecureDoer
 	}
 
-	apiCtx.Cache.Save(cacheKey, iVal)
+	timings := httpctx.NewTimingsRecorder()
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), timings.ClientTrace()))
 
-	return nil
-}
+	apiCtx.Cache.Save(httpcache.LastHTTPRequestTimestamp, time.Now())
+	timings.Start()
 
-// IWait waits for given timeInterval amount of time
-func (apiCtx *APIContext) IWait(timeInterval time.Duration) error {
-	time.Sleep(timeInterval)
+	resp, err := doer.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request %s %s, reason: %w", req.Method, req.URL.String(), err)
+	}
+
+	apiCtx.Cache.Save(httpcache.LastHTTPResponseTimestamp, time.Now())
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("could not read response body of request %s %s, err: %w", req.Method, req.URL.String(), err)
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+	timings.Done()
+
+	apiCtx.Cache.Save(httpcache.LastHTTPResponseCacheKey, resp)
+	apiCtx.Cache.Save(httpcache.LastHTTPResponseTimings, timings.Timings())
+
+	if apiCtx.CoverageRecorder != nil {
+		apiCtx.CoverageRecorder.Record(req.Method, req.URL.Path, resp.StatusCode)
+	}
+
+	if apiCtx.Debugger.IsOn() {
+		apiCtx.Debugger.Print(fmt.Sprintf("%s %s response body (status code: %d):\n\n%s\n", req.Method, req.URL.String(), resp.StatusCode, respBody))
+		apiCtx.Debugger.Print(fmt.Sprintf("%s %s timings:\n\n%s\n", req.Method, req.URL.String(), timings.Timings()))
+	}
 
 	return nil
 }
 
-// IPrintLastResponseBody prints last response from request.
-func (apiCtx *APIContext) IPrintLastResponseBody() error {
-	var tmp interface{}
-
+// iValidateNodeWithSchemaGeneral validates last response body node against schema as provided in reference.
+func (apiCtx *APIContext) iValidateNodeWithSchemaGeneral(dataFormat format.DataFormat, exprTemplate, referenceTemplate string, validator validator.SchemaValidator) error {
 	body, err := apiCtx.GetLastResponseBody()
 	if err != nil {
-		return fmt.Errorf("could not obtain last HTTP(s) response body, err: %w", err)
+		return err
 	}
 
-	defer func() {
-		if err != nil {
-			apiCtx.Debugger.Print(string(body))
-		}
-	}()
-
-	if format.IsYAML(body) {
-		err = apiCtx.Formatters.YAML.Deserialize(body, &tmp)
-		if err != nil {
-			return nil
-		}
+	reference, err := apiCtx.TemplateEngine.Replace(referenceTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "reference", Err: err}
+	}
 
-		indentedRespBody, err := yaml.Marshal(tmp)
-		if err != nil {
-			return nil
-		}
+	if apiCtx.Debugger.IsOn() {
+		apiCtx.Debugger.Print(fmt.Sprintf("'%s' was replaced as: '%s'\n", referenceTemplate, reference))
+	}
 
-		apiCtx.Debugger.Print(string(indentedRespBody))
-		return nil
+	expr, err := apiCtx.TemplateEngine.Replace(exprTemplate, apiCtx.Cache.All())
+	if err != nil {
+		return &TemplateError{Name: "expression", Err: err}
 	}
 
-	if format.IsJSON(body) {
-		err = apiCtx.Formatters.JSON.Deserialize(body, &tmp)
-		if err != nil {
-			return nil
+	var node interface{}
+	switch dataFormat {
+	case format.JSON:
+		node, err = apiCtx.PathFinders.JSON.Find(expr, body)
+	case format.YAML:
+		node, err = apiCtx.PathFinders.YAML.Find(expr, body)
+	case format.XML:
+		var element []byte
+		if element, err = pathfinder.FindXMLElement(expr, body); err == nil {
+			node = string(element)
 		}
+	default:
+		return fmt.Errorf("provided unknown format: %s, format should be one of : %s, %s, %s",
+			dataFormat, format.JSON, format.YAML, format.XML)
+	}
 
-		indentedRespBody, err := json.MarshalIndent(tmp, "", "\t")
-		if err != nil {
-			return nil
-		}
+	if err != nil {
+		return &PathFinderError{Expr: expr, Err: err}
+	}
 
-		apiCtx.Debugger.Print(string(indentedRespBody))
-		return nil
+	var jsonNode []byte
+	if dataFormat == format.XML {
+		jsonNode, err = schema.XMLToJSON([]byte(node.(string)))
+	} else {
+		jsonNode, err = apiCtx.Formatters.JSON.Serialize(node)
 	}
 
-	if format.IsXML(body) {
-		err = apiCtx.Formatters.XML.Deserialize(body, &tmp)
-		if err != nil {
-			return nil
-		}
+	if err != nil {
+		return fmt.Errorf("problem during formatting data to JSON, err: %w", err)
+	}
 
-		indentedRespBody, err := xml.MarshalIndent(tmp, "", "\t")
-		if err != nil {
-			return nil
-		}
+	if apiCtx.Debugger.IsOn() {
+		apiCtx.Debugger.Print(fmt.Sprintf("%+v\n\n was marshaled to:\n\n %s \n\nand passed for validation", node, jsonNode))
+	}
 
-		apiCtx.Debugger.Print(string(indentedRespBody))
+	if err = validator.Validate(string(jsonNode), reference); err != nil {
+		apiCtx.printSchemaViolations(err)
+
+		return schemaMismatch(err, expr, reference, jsonNode, fmt.Sprintf("%s node '%s' does not match schema", dataFormat, expr))
 	}
 
-	apiCtx.Debugger.Print(string(body))
 	return nil
 }
 
-// IStartDebugMode starts debugging mode
-func (apiCtx *APIContext) IStartDebugMode() error {
-	apiCtx.Debugger.TurnOn()
+// validateBodyWithSchema converts body to JSON, according to Content-Type of last HTTP(s) response,
+// and validates it against schema.
+func (apiCtx *APIContext) validateBodyWithSchema(body []byte, reference string, validator validator.SchemaValidator) error {
+	contentType := ""
+	if lastResp, err := apiCtx.GetLastResponse(); err == nil {
+		contentType = lastResp.Header.Get("Content-Type")
+	}
 
-	return nil
-}
+	dataFormat, document, err := schema.ToJSONDocumentOfContentType(body, contentType)
+	if err != nil {
+		return fmt.Errorf("could not convert last HTTP(s) response body to JSON, err: %w", err)
+	}
 
-// IStopDebugMode stops debugging mode
-func (apiCtx *APIContext) IStopDebugMode() error {
-	apiCtx.Debugger.TurnOff()
+	if apiCtx.Debugger.IsOn() && (dataFormat == format.YAML || dataFormat == format.XML) {
+		apiCtx.Debugger.Print(fmt.Sprintf("%s body was converted to JSON:\n\n%s\n\nand passed for validation", dataFormat, document))
+	}
+
+	if err = validator.Validate(string(document), reference); err != nil {
+		apiCtx.printSchemaViolations(err)
+
+		return schemaMismatch(err, "body", reference, document, fmt.Sprintf("%s body does not match schema", dataFormat))
+	}
 
 	return nil
 }
 
-// GetPreparedRequest returns prepared request from cache or error if failed
-func (apiCtx *APIContext) GetPreparedRequest(cacheKey string) (*http.Request, error) {
-	reqInterface, err := apiCtx.Cache.GetSaved(cacheKey)
+// generateJSONSchema infers JSON schema from documents and writes it to file under JSON schemas directory.
+func (apiCtx *APIContext) generateJSONSchema(documents [][]byte, fileNameTemplate string) error {
+	fileName, err := apiCtx.TemplateEngine.Replace(fileNameTemplate, apiCtx.Cache.All())
 	if err != nil {
-		return &http.Request{}, fmt.Errorf("could not obtain %s from cache, err: %w", cacheKey, err)
+		return &TemplateError{Name: "file name", Err: err}
 	}
 
-	req, ok := reqInterface.(*http.Request)
-	if !ok {
-		return &http.Request{}, fmt.Errorf("value under key %s in cache doesn't contain *http.Request", cacheKey)
+	if apiCtx.jsonSchemaDir == "" {
+		return errors.New("JSON schemas directory is not set, use SetJSONSchemaDir first")
 	}
 
-	return req, nil
-}
+	pth := filepath.Join(apiCtx.jsonSchemaDir, fileName)
+	rel, err := filepath.Rel(apiCtx.jsonSchemaDir, pth)
+	if err != nil || filepath.IsAbs(fileName) || rel == "." || strings.HasPrefix(rel, "..") {
+		return fmt.Errorf("file name '%s' should be relative path to file under JSON schemas directory", fileName)
+	}
 
-// GetLastResponse returns last HTTP(s) response.
-func (apiCtx *APIContext) GetLastResponse() (*http.Response, error) {
-	respInterface, err := apiCtx.Cache.GetSaved(httpcache.LastHTTPResponseCacheKey)
+	inferred, err := schema.Infer(documents...)
 	if err != nil {
-		return nil, fmt.Errorf("missing last HTTP(s) response, err: %s", err.Error())
+		return fmt.Errorf("could not infer JSON schema, err: %w", err)
 	}
 
-	lastResp, ok := respInterface.(*http.Response)
-	if !ok {
-		return nil, fmt.Errorf("last HTTP(s) response data structure is not type *http.Response")
+	if err = os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+		return err
 	}
 
-	if lastResp == nil {
-		return nil, fmt.Errorf("missing last HTTP(s) response")
+	if err = ioutil.WriteFile(pth, append(inferred, '\n'), 0644); err != nil {
+		return err
 	}
 
-	return lastResp, nil
+	if apiCtx.Debugger.IsOn() {
+		apiCtx.Debugger.Print(fmt.Sprintf("JSON schema inferred from %d document(s) was written to %s:\n\n%s", len(documents), pth, inferred))
+	}
+
+	return nil
 }
 
-// GetLastResponseBody returns last HTTP(s) response body.
-// internally method creates new NoPCloser on last response so this method is safe to reuse many times
-func (apiCtx *APIContext) GetLastResponseBody() ([]byte, error) {
-	lastResponse, err := apiCtx.GetLastResponse()
-	if err != nil {
-		return []byte(""), fmt.Errorf("could not obtain last HTTP(s) response body, err: %w", err)
+// printSchemaViolations prints excerpts of nodes violating JSON schema, when debug mode is on.
+func (apiCtx *APIContext) printSchemaViolations(err error) {
+	var validationErr *schema.ValidationError
+	if !apiCtx.Debugger.IsOn() || !errors.As(err, &validationErr) {
+		return
 	}
 
-	var bodyBytes []byte
+	for _, violation := range validationErr.Violations {
+		apiCtx.Debugger.Print(fmt.Sprintf("node #%s violating schema:\n\n%s", violation.InstancePath, violation.Excerpt))
+	}
+}
 
-	if lastResponse != nil {
-		bodyBytes, _ = ioutil.ReadAll(lastResponse.Body)
-		defer lastResponse.Body.Close()
+// schemaMismatch returns *AssertionError when err describes violations of JSON schema or XML Schema by document.
+// Other errors, for example of schema that could not be compiled, are returned as they are.
+// Non-empty message is prepended to description of err.
+func schemaMismatch(err error, path, reference string, document []byte, message string) error {
+	if err == nil {
+		return nil
+	}
 
-		// last response body may be read again
-		lastResponse.Body = ioutil.NopCloser(bytes.NewBuffer(bodyBytes))
+	var validationErr *schema.ValidationError
+	var violations xsd.Errors
+	isViolation := errors.As(err, &validationErr) || errors.As(err, &violations)
+
+	if message != "" {
+		err = fmt.Errorf("%s:\n%w", message, err)
 	}
 
-	return bodyBytes, nil
+	if isViolation {
+		return &AssertionError{Path: path, Expected: reference, Actual: string(document), Err: err}
+	}
+
+	return err
 }
 
-// iValidateNodeWithSchemaGeneral validates last response body node against schema as provided in reference.
-func (apiCtx *APIContext) iValidateNodeWithSchemaGeneral(dataFormat format.DataFormat, exprTemplate, referenceTemplate string, validator validator.SchemaValidator) error {
+// iValidateXMLNodeWithXSDGeneral validates last response body XML element against XML Schema as provided in reference.
+func (apiCtx *APIContext) iValidateXMLNodeWithXSDGeneral(exprTemplate, referenceTemplate string, validator validator.SchemaValidator) error {
 	body, err := apiCtx.GetLastResponseBody()
 	if err != nil {
-		return fmt.Errorf("could not obtain last HTTP(s) response body, err: %w", err)
+		return err
 	}
 
 	reference, err := apiCtx.TemplateEngine.Replace(referenceTemplate, apiCtx.Cache.All())
 	if err != nil {
-		return fmt.Errorf("template engine has problem with 'reference' template, err: %w", err)
-	}
-
-	if apiCtx.Debugger.IsOn() {
-		apiCtx.Debugger.Print(fmt.Sprintf("'%s' was replaced as: '%s'\n", referenceTemplate, reference))
+		return &TemplateError{Name: "reference", Err: err}
 	}
 
 	expr, err := apiCtx.TemplateEngine.Replace(exprTemplate, apiCtx.Cache.All())
 	if err != nil {
-		return fmt.Errorf("template engine has problem with 'expression' template, err: %w", err)
-	}
-
-	var node interface{}
-	switch dataFormat {
-	case format.JSON:
-		node, err = apiCtx.PathFinders.JSON.Find(expr, body)
-	case format.YAML:
-		node, err = apiCtx.PathFinders.YAML.Find(expr, body)
-	case format.XML:
-		return fmt.Errorf("this method does not support data in format: %s", format.XML)
-	default:
-		return fmt.Errorf("provided unknown format: %s, format should be one of : %s, %s, %s",
-			dataFormat, format.JSON, format.YAML, format.XML)
+		return &TemplateError{Name: "expression", Err: err}
 	}
 
-	if err != nil {
-		return err
+	if validator == nil {
+		return errors.New("XML Schema validator is not set")
 	}
 
-	jsonNode, err := apiCtx.Formatters.JSON.Serialize(node)
+	element, err := pathfinder.FindXMLElement(expr, body)
 	if err != nil {
-		return fmt.Errorf("problem during formatting data to JSON, err: %w", err)
+		return &PathFinderError{Expr: expr, Err: err}
 	}
 
 	if apiCtx.Debugger.IsOn() {
-		apiCtx.Debugger.Print(fmt.Sprintf("%+v\n\n was marshaled to:\n\n %s \n\nand passed for validation", node, jsonNode))
+		apiCtx.Debugger.Print(fmt.Sprintf("node %s:\n\n%s\n\npassed for validation", expr, element))
 	}
 
-	return validator.Validate(string(jsonNode), reference)
+	return schemaMismatch(validator.Validate(string(element), reference), expr, reference, element, "")
 }
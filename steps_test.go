@@ -2,10 +2,15 @@ package gdutils
 
 import (
 	"bytes"
+	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
@@ -14,9 +19,16 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	"github.com/pawelWritesCode/gdutils/pkg/cache"
+	"github.com/pawelWritesCode/gdutils/pkg/collection"
+	"github.com/pawelWritesCode/gdutils/pkg/compare"
+	"github.com/pawelWritesCode/gdutils/pkg/debugger"
+	"github.com/pawelWritesCode/gdutils/pkg/diff"
 	"github.com/pawelWritesCode/gdutils/pkg/format"
 	"github.com/pawelWritesCode/gdutils/pkg/httpcache"
+	"github.com/pawelWritesCode/gdutils/pkg/httpctx"
 	"github.com/pawelWritesCode/gdutils/pkg/mathutils"
+	"github.com/pawelWritesCode/gdutils/pkg/schema"
+	"github.com/pawelWritesCode/gdutils/pkg/snapshot"
 	"github.com/pawelWritesCode/gdutils/pkg/stringutils"
 	"github.com/pawelWritesCode/gdutils/pkg/template"
 	"github.com/pawelWritesCode/gdutils/pkg/timeutils"
@@ -129,6 +141,186 @@ func TestState_IPrepareNewRequestToAndSaveItAs(t *testing.T) {
 	}
 }
 
+func TestState_IPrepareNewRequestFromCurlCommandAndSaveItAs(t *testing.T) {
+	type args struct {
+		curlTemplate string
+		cacheKey     string
+	}
+	tests := []struct {
+		name       string
+		cache      map[string]interface{}
+		args       args
+		wantMethod string
+		wantURL    string
+		wantBody   string
+		wantErr    bool
+	}{
+		{name: "missing template value", args: args{curlTemplate: "curl {{.HOST}}/users", cacheKey: "REQ"}, wantErr: true},
+		{name: "not curl command", args: args{curlTemplate: "wget http://a.com", cacheKey: "REQ"}, wantErr: true},
+		{name: "unsupported option", args: args{curlTemplate: "curl http://a.com --proxy b", cacheKey: "REQ"}, wantErr: true},
+		{name: "templated curl command", cache: map[string]interface{}{"HOST": "https://a.com", "NAME": "ivo"},
+			args: args{curlTemplate: `curl -k -X PATCH '{{.HOST}}/users/1' \
+  -H 'Content-Type: application/json' \
+  --data-raw '{"name": "{{.NAME}}"}'`, cacheKey: "REQ"},
+			wantMethod: http.MethodPatch, wantURL: "https://a.com/users/1", wantBody: `{"name": "ivo"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewDefaultAPIContext(false, "")
+			for key, val := range tt.cache {
+				s.Cache.Save(key, val)
+			}
+
+			err := s.IPrepareNewRequestFromCurlCommandAndSaveItAs(tt.args.curlTemplate, tt.args.cacheKey)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("IPrepareNewRequestFromCurlCommandAndSaveItAs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			req, err := s.GetPreparedRequest(tt.args.cacheKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			body, _ := ioutil.ReadAll(req.Body)
+			if req.Method != tt.wantMethod || req.URL.String() != tt.wantURL || string(body) != tt.wantBody {
+				t.Errorf("unexpected request %s %s with body %s", req.Method, req.URL, body)
+			}
+
+			if req.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("unexpected Content-Type header: %s", req.Header.Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestState_IPrepareNewRequestFromCurlCommandAndSaveItAs_insecure(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	s := NewDefaultAPIContext(false, "")
+	s.SetRequestDoer(&http.Client{})
+
+	if err := s.IPrepareNewRequestFromCurlCommandAndSaveItAs("curl "+srv.URL, "SECURE"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.ISendRequest("SECURE"); err == nil {
+		t.Errorf("request to server with self-signed certificate should fail without -k")
+	}
+
+	if err := s.IPrepareNewRequestFromCurlCommandAndSaveItAs("curl -k "+srv.URL, "INSECURE"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.ISendRequest("INSECURE"); err != nil {
+		t.Errorf("request with -k should skip TLS verification, err: %v", err)
+	}
+
+	if err := s.TheResponseStatusCodeShouldBe(http.StatusNoContent); err != nil {
+		t.Error(err)
+	}
+
+	insecureDoer := s.insecureDoer
+	if err := s.ISendRequest("INSECURE"); err != nil || s.insecureDoer != insecureDoer {
+		t.Errorf("requests with -k should reuse the same client, err: %v", err)
+	}
+
+	s.SetRequestDoer(&http.Client{})
+	if err := s.ISendRequest("INSECURE"); err != nil || s.insecureDoer == insecureDoer {
+		t.Errorf("requests with -k should use client built from replaced RequestDoer, err: %v", err)
+	}
+
+	s = NewDefaultAPIContext(false, "")
+	if err := s.IPrepareNewRequestFromCurlCommandAndSaveItAs("curl -k "+srv.URL, "INSECURE"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.ISendRequest("INSECURE"); err != nil || s.insecureDoer != s.RequestDoer {
+		t.Errorf("requests with -k should use default client, which already skips TLS verification, err: %v", err)
+	}
+}
+
+func TestState_HTTPFileRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("X-Auth", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(r.Method + " " + r.URL.String() + " " + string(body)))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	httpFile := filepath.Join(dir, "users.http")
+	envFile := filepath.Join(dir, "http-client.env.json")
+	content := `@usersURL = {{host}}/users
+
+### create user
+POST {{usersURL}}?id={{ID}}
+Authorization: Bearer {{token}}
+
+{"name": "ivo"}
+`
+	if err := ioutil.WriteFile(httpFile, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(envFile, []byte(`{"dev": {"host": "`+srv.URL+`", "token": "abc"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewDefaultAPIContext(false, "")
+	s.Cache.Save("DIR", dir)
+	s.Cache.Save("ID", 10)
+
+	if err := s.ISendRequestFromHTTPFile("create user", "{{.DIR}}/users.http"); err == nil {
+		t.Errorf("ISendRequestFromHTTPFile() expected error for missing environment variables")
+	}
+
+	if err := s.ILoadHTTPClientEnvironmentFromFile("prod", "{{.DIR}}/http-client.env.json"); err == nil {
+		t.Errorf("ILoadHTTPClientEnvironmentFromFile() expected error for missing environment")
+	}
+
+	if err := s.ILoadHTTPClientEnvironmentFromFile("dev", "{{.DIR}}/http-client.env.json"); err != nil {
+		t.Fatalf("ILoadHTTPClientEnvironmentFromFile() error = %v", err)
+	}
+
+	if err := s.IPrepareNewRequestFromHTTPFileAndSaveItAs("missing", "{{.DIR}}/users.http", "REQ"); err == nil {
+		t.Errorf("IPrepareNewRequestFromHTTPFileAndSaveItAs() expected error for missing request")
+	}
+
+	if err := s.IPrepareNewRequestFromHTTPFileAndSaveItAs("create user", "{{.DIR}}/users.http", "REQ"); err != nil {
+		t.Fatalf("IPrepareNewRequestFromHTTPFileAndSaveItAs() error = %v", err)
+	}
+
+	req, err := s.GetPreparedRequest("REQ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if req.Method != http.MethodPost || req.URL.String() != srv.URL+"/users?id=10" {
+		t.Errorf("unexpected prepared request %s %s", req.Method, req.URL)
+	}
+
+	if err = s.ISendRequestFromHTTPFile("create user", "{{.DIR}}/users.http"); err != nil {
+		t.Fatalf("ISendRequestFromHTTPFile() error = %v", err)
+	}
+
+	resp, err := s.GetLastResponse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := s.GetLastResponseBody()
+	if string(body) != `POST /users?id=10 {"name": "ivo"}` || resp.Header.Get("X-Auth") != "Bearer abc" {
+		t.Errorf("unexpected response %s with headers %v", body, resp.Header)
+	}
+}
+
 func TestState_ISetFollowingHeadersForPreparedRequest(t *testing.T) {
 	type fields struct {
 		reqMethod string
@@ -1693,6 +1885,17 @@ func TestState_TheNodeShouldBeOfValue(t *testing.T) {
 			dataType:  "int",
 			dataValue: "10",
 		}, wantErr: false},
+		{name: "unknown data type is not compared", fields: fields{
+			lastResponse: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+{
+	"number": 10
+}`))},
+		}, args: args{
+			df:        format.JSON,
+			expr:      "number",
+			dataType:  "abc",
+			dataValue: "11",
+		}, wantErr: false},
 		{name: "json with first level field with float64 data type", fields: fields{
 			lastResponse: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
@@ -2485,10 +2688,11 @@ func TestState_TheResponseShouldHaveCookieOfValue(t *testing.T) {
 		valueTemplate string
 	}
 	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		wantErr bool
+		name        string
+		fields      fields
+		args        args
+		wantErr     bool
+		wantMessage string
 	}{
 		{name: "missing last response", fields: fields{
 			TemplateEngine: mTemplateEngine,
@@ -2518,6 +2722,26 @@ func TestState_TheResponseShouldHaveCookieOfValue(t *testing.T) {
 			name:          "a",
 			valueTemplate: "a",
 		}, wantErr: true},
+		{name: "cookie has expected value", fields: fields{
+			TemplateEngine: mTemplateEngine,
+			response:       &http.Response{Header: http.Header{"Set-Cookie": []string{"session=abcd"}}},
+			mockFunc: func() {
+				mTemplateEngine.On("Replace", "abcd", mock.Anything).Return("abcd", nil).Once()
+			},
+		}, args: args{
+			name:          "session",
+			valueTemplate: "abcd",
+		}, wantErr: false},
+		{name: "cookie has different value", fields: fields{
+			TemplateEngine: mTemplateEngine,
+			response:       &http.Response{Header: http.Header{"Set-Cookie": []string{"session=abXd"}}},
+			mockFunc: func() {
+				mTemplateEngine.On("Replace", "abcd", mock.Anything).Return("abcd", nil).Once()
+			},
+		}, args: args{
+			name:          "session",
+			valueTemplate: "abcd",
+		}, wantErr: true, wantMessage: "first difference at position 2"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -2527,9 +2751,14 @@ func TestState_TheResponseShouldHaveCookieOfValue(t *testing.T) {
 
 			tt.fields.mockFunc()
 
-			if err := s.TheResponseShouldHaveCookieOfValue(tt.args.name, tt.args.valueTemplate); (err != nil) != tt.wantErr {
+			err := s.TheResponseShouldHaveCookieOfValue(tt.args.name, tt.args.valueTemplate)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("TheResponseShouldHaveCookieOfValue() error = %v, wantErr %v", err, tt.wantErr)
 			}
+
+			if err != nil && !strings.Contains(err.Error(), tt.wantMessage) {
+				t.Errorf("TheResponseShouldHaveCookieOfValue() error = %v, want message containing %q", err, tt.wantMessage)
+			}
 		})
 	}
 }
@@ -2812,3 +3041,1339 @@ user:
 		})
 	}
 }
+
+func TestState_IFollowPaginationOfPreparedRequestAndSaveNodesAs(t *testing.T) {
+	allItems := []int{1, 2, 3, 4, 5, 6, 7}
+	pageOf := func(from int) []int {
+		if from >= len(allItems) {
+			return []int{}
+		}
+
+		to := from + 3
+		if to > len(allItems) {
+			to = len(allItems)
+		}
+
+		return allItems[from:to]
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch r.URL.Path {
+		case "/link":
+			page, _ := strconv.Atoi(query.Get("p"))
+			if (page+1)*3 < len(allItems) {
+				w.Header().Set("Link", fmt.Sprintf(`</link?p=%d>; rel="next"`, page+1))
+			}
+
+			body, _ := json.Marshal(map[string]interface{}{"data": pageOf(page * 3)})
+			w.Write(body)
+		case "/cursor":
+			from, _ := strconv.Atoi(query.Get("after"))
+			next := ""
+			if from+3 < len(allItems) {
+				next = strconv.Itoa(from + 3)
+			}
+
+			body, _ := json.Marshal(map[string]interface{}{"data": pageOf(from), "meta": map[string]string{"next": next}})
+			w.Write(body)
+		case "/numeric-cursor":
+			// cursors are large numbers: 1000000 points at first item, 1000003 at fourth and so on
+			from := 0
+			if after := query.Get("after"); after != "" {
+				cursor, err := strconv.Atoi(after)
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				from = cursor - 1000000
+			}
+
+			var next interface{}
+			if from+3 < len(allItems) {
+				next = 1000000 + from + 3
+			}
+
+			body, _ := json.Marshal(map[string]interface{}{"data": pageOf(from), "meta": map[string]interface{}{"next": next}})
+			w.Write(body)
+		case "/page":
+			page, _ := strconv.Atoi(query.Get("page"))
+			body, _ := json.Marshal(map[string]interface{}{"data": pageOf((page - 1) * 3)})
+			w.Write(body)
+		case "/offset":
+			offset, _ := strconv.Atoi(query.Get("offset"))
+			w.Write([]byte("---\ndata:\n"))
+			for _, item := range pageOf(offset) {
+				w.Write([]byte(fmt.Sprintf("  - %d\n", item)))
+			}
+		case "/endless":
+			w.Header().Set("Link", `</endless>; rel="next"`)
+			w.Write([]byte(`{"data": [1]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	type args struct {
+		path               string
+		paginationTemplate string
+		dataFormat         format.DataFormat
+		expr               string
+		pageLimit          int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    int
+		wantErr bool
+	}{
+		{name: "invalid page limit", args: args{path: "/link", paginationTemplate: `{"strategy": "link"}`, dataFormat: format.JSON, expr: "data", pageLimit: 0}, wantErr: true},
+		{name: "unknown strategy", args: args{path: "/link", paginationTemplate: `{"strategy": "abc"}`, dataFormat: format.JSON, expr: "data", pageLimit: 5}, wantErr: true},
+		{name: "unsupported XML format", args: args{path: "/link", paginationTemplate: `{"strategy": "link"}`, dataFormat: format.XML, expr: "//data", pageLimit: 5}, wantErr: true},
+		{name: "node is not slice", args: args{path: "/cursor", paginationTemplate: `{"strategy": "cursor", "cursor": "meta.next", "param": "after"}`, dataFormat: format.JSON, expr: "meta", pageLimit: 5}, wantErr: true},
+		{name: "missing node", args: args{path: "/link", paginationTemplate: `{"strategy": "link"}`, dataFormat: format.JSON, expr: "items", pageLimit: 5}, wantErr: true},
+		{name: "link strategy", args: args{path: "/link", paginationTemplate: `{"strategy": "link"}`, dataFormat: format.JSON, expr: "data", pageLimit: 10}, want: 7},
+		{name: "cursor strategy", args: args{path: "/cursor", paginationTemplate: `{"strategy": "cursor", "cursor": "meta.next", "param": "after"}`, dataFormat: format.JSON, expr: "data", pageLimit: 10}, want: 7},
+		{name: "cursor strategy with numeric cursor", args: args{path: "/numeric-cursor", paginationTemplate: `{"strategy": "cursor", "cursor": "meta.next", "param": "after"}`, dataFormat: format.JSON, expr: "data", pageLimit: 10}, want: 7},
+		{name: "page strategy in YAML config", args: args{path: "/page", paginationTemplate: "strategy: page\nparam: page", dataFormat: format.JSON, expr: "$.data", pageLimit: 10}, want: 7},
+		{name: "offset strategy with YAML body", args: args{path: "/offset", paginationTemplate: `{"strategy": "offset", "param": "offset", "size": 3}`, dataFormat: format.YAML, expr: "$.data", pageLimit: 10}, want: 7},
+		{name: "page limit", args: args{path: "/endless", paginationTemplate: `{"strategy": "link"}`, dataFormat: format.JSON, expr: "data", pageLimit: 4}, wantErr: true},
+		{name: "page limit equal to number of pages", args: args{path: "/link", paginationTemplate: `{"strategy": "link"}`, dataFormat: format.JSON, expr: "data", pageLimit: 3}, want: 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewDefaultAPIContext(false, "")
+
+			if err := s.IPrepareNewRequestToAndSaveItAs(http.MethodGet, srv.URL+tt.args.path, "REQ"); err != nil {
+				t.Fatal(err)
+			}
+
+			err := s.IFollowPaginationOfPreparedRequestAndSaveNodesAs("REQ", tt.args.paginationTemplate, tt.args.dataFormat, tt.args.expr, tt.args.pageLimit, "ITEMS")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("IFollowPaginationOfPreparedRequestAndSaveNodesAs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			items, err := s.Cache.GetSaved("ITEMS")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := len(items.([]interface{})); got != tt.want {
+				t.Errorf("aggregated %d items, expected %d", got, tt.want)
+			}
+
+			if err = s.TheResponseShouldHaveNode(tt.args.dataFormat, "$.data"); err != nil {
+				t.Errorf("last response should be response of the last page, err: %v", err)
+			}
+		})
+	}
+}
+
+func TestState_TimeOfLastHTTPRequestPhaseShouldBeLessThanOrEqualTo(t *testing.T) {
+	type args struct {
+		phase        httpctx.TimingPhase
+		timeInterval time.Duration
+	}
+	tests := []struct {
+		name    string
+		timings interface{}
+		args    args
+		wantErr bool
+	}{
+		{name: "missing timings", timings: nil, args: args{phase: httpctx.TimingPhaseTTFB, timeInterval: time.Second}, wantErr: true},
+		{name: "invalid timings type", timings: "abc", args: args{phase: httpctx.TimingPhaseTTFB, timeInterval: time.Second}, wantErr: true},
+		{name: "unknown phase", timings: httpctx.Timings{}, args: args{phase: "abc", timeInterval: time.Second}, wantErr: true},
+		{name: "phase took too long", timings: httpctx.Timings{TimeToFirstByte: 200 * time.Millisecond}, args: args{phase: httpctx.TimingPhaseTTFB, timeInterval: 150 * time.Millisecond}, wantErr: true},
+		{name: "phase took exactly expected time", timings: httpctx.Timings{TLSHandshake: 150 * time.Millisecond}, args: args{phase: httpctx.TimingPhaseTLS, timeInterval: 150 * time.Millisecond}, wantErr: false},
+		{name: "phase took less time", timings: httpctx.Timings{DNSLookup: time.Millisecond}, args: args{phase: httpctx.TimingPhaseDNS, timeInterval: 150 * time.Millisecond}, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewDefaultAPIContext(false, "")
+			if tt.timings != nil {
+				s.Cache.Save(httpcache.LastHTTPResponseTimings, tt.timings)
+			}
+
+			if err := s.TimeOfLastHTTPRequestPhaseShouldBeLessThanOrEqualTo(tt.args.phase, tt.args.timeInterval); (err != nil) != tt.wantErr {
+				t.Errorf("TimeOfLastHTTPRequestPhaseShouldBeLessThanOrEqualTo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestState_ISendRequestMeasuresTimings(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "abc"}`))
+	}))
+	defer srv.Close()
+
+	s := NewDefaultAPIContext(false, "")
+	if err := s.IPrepareNewRequestToAndSaveItAs(http.MethodGet, srv.URL, "REQ"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.ISendRequest("REQ"); err != nil {
+		t.Fatal(err)
+	}
+
+	timings, err := s.GetLastResponseTimings()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if timings.Total <= 0 || timings.TCPConnection <= 0 {
+		t.Errorf("timings were not measured: %+v", timings)
+	}
+
+	if err = s.TheNodeShouldBeOfValue(format.JSON, "name", "string", "abc"); err != nil {
+		t.Errorf("response body is not readable after measuring timings, err: %v", err)
+	}
+}
+
+func TestState_TLSAssertions(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`ok`))
+	}))
+	srv.StartTLS()
+	defer srv.Close()
+
+	staplingSrv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`ok`))
+	}))
+	staplingSrv.StartTLS()
+	staplingSrv.TLS.Certificates[0].OCSPStaple = []byte("ocsp")
+	defer staplingSrv.Close()
+
+	send := func(t *testing.T, s *APIContext, url string) {
+		if err := s.IPrepareNewRequestToAndSaveItAs(http.MethodGet, url, "REQ"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := s.ISendRequest("REQ"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("plain HTTP response", func(t *testing.T) {
+		s := NewDefaultAPIContext(false, "")
+		s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{})
+
+		if err := s.TheResponseTLSVersionShouldBeAtLeast("TLS 1.2"); err == nil {
+			t.Errorf("TheResponseTLSVersionShouldBeAtLeast() should fail for response without TLS")
+		}
+	})
+
+	s := NewDefaultAPIContext(false, "")
+	send(t, s, srv.URL)
+	state, err := s.GetLastResponseTLS()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		assert  func() error
+		wantErr bool
+	}{
+		{name: "TLS version", assert: func() error { return s.TheResponseTLSVersionShouldBe(httpctx.TLSVersionName(state.Version)) }},
+		{name: "invalid TLS version", assert: func() error { return s.TheResponseTLSVersionShouldBe("TLS 1.0") }, wantErr: true},
+		{name: "unknown TLS version", assert: func() error { return s.TheResponseTLSVersionShouldBe("SSL 3") }, wantErr: true},
+		{name: "TLS version at least", assert: func() error { return s.TheResponseTLSVersionShouldBeAtLeast("1.2") }},
+		{name: "cipher suite", assert: func() error { return s.TheResponseTLSCipherSuiteShouldBe(tls.CipherSuiteName(state.CipherSuite)) }},
+		{name: "invalid cipher suite", assert: func() error { return s.TheResponseTLSCipherSuiteShouldBe("TLS_RSA_WITH_RC4_128_SHA") }, wantErr: true},
+		{name: "subject", assert: func() error { return s.TheResponseCertificateShouldHaveSubject("O=Acme Co") }},
+		{name: "invalid subject", assert: func() error { return s.TheResponseCertificateShouldHaveSubject("example.org") }, wantErr: true},
+		{name: "valid for host from SAN", assert: func() error { return s.TheResponseCertificateShouldBeValidForHost("example.com") }},
+		{name: "valid for IP from SAN", assert: func() error { return s.TheResponseCertificateShouldBeValidForHost("127.0.0.1") }},
+		{name: "not valid for host", assert: func() error { return s.TheResponseCertificateShouldBeValidForHost("example.org") }, wantErr: true},
+		{name: "issuer", assert: func() error { return s.TheResponseCertificateShouldBeIssuedBy("Acme Co") }},
+		{name: "invalid issuer", assert: func() error { return s.TheResponseCertificateShouldBeIssuedBy("Let's Encrypt") }, wantErr: true},
+		{name: "days until expiry", assert: func() error { return s.TheResponseCertificateShouldBeValidForAtLeastDays(30) }},
+		{name: "expires too soon", assert: func() error { return s.TheResponseCertificateShouldBeValidForAtLeastDays(365 * 1000) }, wantErr: true},
+		{name: "missing OCSP staple", assert: func() error { return s.TheResponseShouldHaveStapledOCSPResponse() }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.assert(); (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("stapled OCSP response", func(t *testing.T) {
+		s := NewDefaultAPIContext(false, "")
+		send(t, s, staplingSrv.URL)
+
+		if err := s.TheResponseShouldHaveStapledOCSPResponse(); err != nil {
+			t.Errorf("TheResponseShouldHaveStapledOCSPResponse() error = %v", err)
+		}
+	})
+}
+
+func TestState_TheNodeShouldMatchDocument(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), "user.yaml")
+	if err := ioutil.WriteFile(pth, []byte("name: \"{{.NAME}}\"\ntags: [b, a]\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	jsonBody := `{"id": 10, "user": {"name": "ivo", "tags": ["a", "b"], "createdAt": "2021-01-01"}}`
+	yamlBody := "id: 10\nuser:\n  name: ivo\n  tags:\n    - a\n    - b\n"
+
+	type args struct {
+		df           format.DataFormat
+		expr         string
+		mode         compare.Mode
+		document     string
+		ignoredPaths string
+	}
+	tests := []struct {
+		name         string
+		respBody     string
+		args         args
+		wantErr      bool
+		wantErrParts []string
+	}{
+		{name: "unsupported format", respBody: jsonBody, args: args{df: format.XML, expr: "$", mode: compare.ModeExact, document: "{}"}, wantErr: true},
+		{name: "unknown mode", respBody: jsonBody, args: args{df: format.JSON, expr: "$", mode: "partial", document: "{}"}, wantErr: true},
+		{name: "invalid file reference", respBody: jsonBody, args: args{df: format.JSON, expr: "$", mode: compare.ModeExact, document: "file://missing.json"}, wantErr: true},
+		{name: "XML document", respBody: jsonBody, args: args{df: format.JSON, expr: "$", mode: compare.ModeExact, document: "<a>1</a>"}, wantErr: true},
+		{name: "missing node", respBody: jsonBody, args: args{df: format.JSON, expr: "$.missing", mode: compare.ModeExact, document: "{}"}, wantErr: true},
+		{name: "json exact match ignoring paths", respBody: jsonBody, args: args{df: format.JSON, expr: "$", mode: compare.ModeExact,
+			document: `{"id": 1, "user": {"name": "{{.NAME}}", "tags": ["a", "b"]}}`, ignoredPaths: "$.id, $.user.createdAt"}},
+		{name: "json exact mismatch reports every path", respBody: jsonBody, args: args{df: format.JSON, expr: "$.user", mode: compare.ModeExact,
+			document: `{"name": "abc", "tags": ["a", "b"]}`}, wantErr: true,
+			wantErrParts: []string{"2 difference(s)", `$.name: expected string "abc", got string "ivo"`, "$.createdAt: unexpected node"}},
+		{name: "json subset from file", respBody: jsonBody, args: args{df: format.JSON, expr: "$.user", mode: compare.ModeSubset, document: "file://" + pth}},
+		{name: "json exact from file fails on order", respBody: jsonBody, args: args{df: format.JSON, expr: "user", mode: compare.ModeExact,
+			document: "file://" + pth, ignoredPaths: "$.createdAt"}, wantErr: true},
+		{name: "yaml unordered match", respBody: yamlBody, args: args{df: format.YAML, expr: "$", mode: compare.ModeUnordered,
+			document: `{"id": 10, "user": {"name": "ivo", "tags": ["b", "a"]}}`}},
+		{name: "yaml unordered node match", respBody: yamlBody, args: args{df: format.YAML, expr: "$.user", mode: compare.ModeUnordered,
+			document: "tags:\n  - b\n  - a\nname: ivo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewDefaultAPIContext(false, "")
+			s.Cache.Save("NAME", "ivo")
+			s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(tt.respBody))})
+
+			err := s.TheNodeShouldMatchDocument(tt.args.df, tt.args.expr, tt.args.mode, tt.args.document, tt.args.ignoredPaths)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TheNodeShouldMatchDocument() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			for _, part := range tt.wantErrParts {
+				if !strings.Contains(err.Error(), part) {
+					t.Errorf("TheNodeShouldMatchDocument() error = %v, should contain %s", err, part)
+				}
+			}
+		})
+	}
+}
+
+func TestState_TheResponseBodyShouldMatchSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	setBody := func(s *APIContext, body string) {
+		s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(body))})
+	}
+
+	s := NewDefaultAPIContext(false, "")
+	setBody(s, `{"id": 1}`)
+	if err := s.TheResponseBodyShouldMatchSnapshot(format.JSON, ""); err == nil {
+		t.Errorf("TheResponseBodyShouldMatchSnapshot() should fail when snapshots are not enabled")
+	}
+
+	s.EnableSnapshots(dir)
+	s.Snapshots.Update = false
+	if err := s.TheResponseBodyShouldMatchSnapshot(format.JSON, ""); err == nil {
+		t.Errorf("TheResponseBodyShouldMatchSnapshot() should fail when scenario is not set")
+	}
+
+	s.SetScenario("features/users.feature", "Get user", "")
+	setBody(s, `{"name": "ivo", "id": 1, "createdAt": "2021-01-01"}`)
+	if err := s.TheResponseBodyShouldMatchSnapshot(format.JSON, "$.id, $.createdAt"); err != nil {
+		t.Fatalf("TheResponseBodyShouldMatchSnapshot() error = %v", err)
+	}
+
+	setBody(s, "name: ivo\n")
+	if err := s.TheResponseBodyShouldMatchSnapshot(format.YAML, ""); err != nil {
+		t.Fatalf("TheResponseBodyShouldMatchSnapshot() error = %v", err)
+	}
+
+	stored, err := ioutil.ReadFile(filepath.Join(dir, "features_users", "get_user.json"))
+	if err != nil || string(stored) != "{\n  \"createdAt\": \"<redacted>\",\n  \"id\": \"<redacted>\",\n  \"name\": \"ivo\"\n}\n" {
+		t.Fatalf("unexpected snapshot %q, err: %v", stored, err)
+	}
+
+	if _, err = os.Stat(filepath.Join(dir, "features_users", "get_user_2.yaml")); err != nil {
+		t.Fatalf("second snapshot of scenario was not created, err: %v", err)
+	}
+
+	s.ResetState(false)
+	s.SetScenario("features/users.feature", "Get user", "")
+	setBody(s, `{"createdAt": "2022-02-02", "id": 2, "name": "ivo"}`)
+	if err = s.TheResponseBodyShouldMatchSnapshot(format.JSON, "$.id,$.createdAt"); err != nil {
+		t.Errorf("TheResponseBodyShouldMatchSnapshot() error = %v", err)
+	}
+
+	s.SetScenario("features/users.feature", "Get user", "")
+	setBody(s, `{"id": 2, "name": "john"}`)
+	if err = s.TheResponseBodyShouldMatchSnapshot(format.JSON, "$.id, $.createdAt"); !errors.Is(err, snapshot.ErrMismatch) {
+		t.Errorf("TheResponseBodyShouldMatchSnapshot() expected snapshot mismatch, got %v", err)
+	}
+
+	s.SetScenario("features/users.feature", "Get user", "")
+	s.Snapshots.Update = true
+	if err = s.TheResponseBodyShouldMatchSnapshot(format.JSON, ""); err != nil {
+		t.Errorf("TheResponseBodyShouldMatchSnapshot() error = %v", err)
+	}
+
+	stored, _ = ioutil.ReadFile(filepath.Join(dir, "features_users", "get_user.json"))
+	if string(stored) != "{\n  \"id\": 2,\n  \"name\": \"john\"\n}\n" {
+		t.Errorf("snapshot was not updated: %q", stored)
+	}
+
+	s.Snapshots.Update = false
+	for _, scenario := range [][2]string{{"features/admins.feature", ""}, {"features/users.feature", "2"}} {
+		s.SetScenario(scenario[0], "Get user", scenario[1])
+		setBody(s, `{"id": 3}`)
+		if err = s.TheResponseBodyShouldMatchSnapshot(format.JSON, ""); err != nil {
+			t.Errorf("snapshot of other feature file or example should not be compared with %s, err: %v", scenario, err)
+		}
+	}
+
+	for _, pth := range []string{"features_admins/get_user.json", "features_users/get_user.2.json"} {
+		if _, err = os.Stat(filepath.Join(dir, filepath.FromSlash(pth))); err != nil {
+			t.Errorf("snapshot %s was not created, err: %v", pth, err)
+		}
+	}
+}
+
+func TestState_NumericNodeAssertions(t *testing.T) {
+	bodies := map[format.DataFormat]string{
+		format.JSON: `{"price": 10.5, "count": 12, "name": "abc"}`,
+		format.YAML: "price: 10.5\ncount: 12\nname: abc\n",
+		format.XML:  "<item><price>10.5</price><count>12</count><name>abc</name></item>",
+	}
+	exprs := map[format.DataFormat][3]string{
+		format.JSON: {"$.price", "$.count", "$.name"},
+		format.YAML: {"$.price", "$.count", "$.name"},
+		format.XML:  {"//price", "//count", "//name"},
+	}
+
+	for df, body := range bodies {
+		price, count, name := exprs[df][0], exprs[df][1], exprs[df][2]
+		tests := []struct {
+			name    string
+			assert  func(s *APIContext) error
+			wantErr bool
+		}{
+			{name: "greater than", assert: func(s *APIContext) error { return s.TheNodeShouldBeGreaterThan(df, price, "10") }},
+			{name: "not greater than", assert: func(s *APIContext) error { return s.TheNodeShouldBeGreaterThan(df, price, "10.5") }, wantErr: true},
+			{name: "greater than or equal to template value", assert: func(s *APIContext) error {
+				return s.TheNodeShouldBeGreaterThanOrEqualTo(df, count, "{{.MIN}}")
+			}},
+			{name: "less than", assert: func(s *APIContext) error { return s.TheNodeShouldBeLessThan(df, count, "13") }},
+			{name: "not less than", assert: func(s *APIContext) error { return s.TheNodeShouldBeLessThan(df, count, "12") }, wantErr: true},
+			{name: "less than or equal to", assert: func(s *APIContext) error { return s.TheNodeShouldBeLessThanOrEqualTo(df, count, "12") }},
+			{name: "invalid expected value", assert: func(s *APIContext) error { return s.TheNodeShouldBeLessThan(df, count, "abc") }, wantErr: true},
+			{name: "not numeric node", assert: func(s *APIContext) error { return s.TheNodeShouldBeLessThan(df, name, "1") }, wantErr: true},
+			{name: "between", assert: func(s *APIContext) error { return s.TheNodeShouldBeBetween(df, price, "10", "{{.MIN}}") }},
+			{name: "not between", assert: func(s *APIContext) error { return s.TheNodeShouldBeBetween(df, price, "11", "12") }, wantErr: true},
+			{name: "invalid range", assert: func(s *APIContext) error { return s.TheNodeShouldBeBetween(df, price, "12", "11") }, wantErr: true},
+			{name: "approximately", assert: func(s *APIContext) error { return s.TheNodeShouldBeApproximately(df, price, "10.45", "0.05") }},
+			{name: "not approximately", assert: func(s *APIContext) error { return s.TheNodeShouldBeApproximately(df, price, "10.4", "0.05") }, wantErr: true},
+			{name: "negative tolerance", assert: func(s *APIContext) error { return s.TheNodeShouldBeApproximately(df, price, "10.5", "-1") }, wantErr: true},
+			{name: "divisible by", assert: func(s *APIContext) error { return s.TheNodeShouldBeDivisibleBy(df, count, "4") }},
+			{name: "not divisible by", assert: func(s *APIContext) error { return s.TheNodeShouldBeDivisibleBy(df, count, "5") }, wantErr: true},
+			{name: "divisible by zero", assert: func(s *APIContext) error { return s.TheNodeShouldBeDivisibleBy(df, count, "0") }, wantErr: true},
+		}
+		for _, tt := range tests {
+			t.Run(string(df)+" "+tt.name, func(t *testing.T) {
+				s := NewDefaultAPIContext(false, "")
+				s.Cache.Save("MIN", 12)
+				s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(body))})
+
+				if err := tt.assert(s); (err != nil) != tt.wantErr {
+					t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				}
+			})
+		}
+	}
+}
+
+func TestState_CollectionAssertions(t *testing.T) {
+	jsonBody := `{"users": [{"id": 1, "name": "a", "active": true}, {"id": 2, "name": "b", "active": true}, {"id": 3, "name": "c", "active": false}], "tags": ["x", "y", "x"], "name": "abc"}`
+	yamlBody := "users:\n  - id: 3\n    role: admin\n  - id: 2\n    role: user\n"
+	xmlBody := "<list><n>9</n><n>10</n><n>11</n></list>"
+
+	tests := []struct {
+		name    string
+		body    string
+		assert  func(s *APIContext) error
+		wantErr bool
+	}{
+		{name: "not slice", body: jsonBody, assert: func(s *APIContext) error { return s.TheNodeShouldBeSliceOfLengthAtLeast(format.JSON, "$.name", 1) }, wantErr: true},
+		{name: "length at least", body: jsonBody, assert: func(s *APIContext) error { return s.TheNodeShouldBeSliceOfLengthAtLeast(format.JSON, "$.users", 3) }},
+		{name: "length not at least", body: jsonBody, assert: func(s *APIContext) error { return s.TheNodeShouldBeSliceOfLengthAtLeast(format.JSON, "$.users", 4) }, wantErr: true},
+		{name: "length at most", body: yamlBody, assert: func(s *APIContext) error { return s.TheNodeShouldBeSliceOfLengthAtMost(format.YAML, "$.users", 2) }},
+		{name: "length not at most", body: xmlBody, assert: func(s *APIContext) error { return s.TheNodeShouldBeSliceOfLengthAtMost(format.XML, "//n", 2) }, wantErr: true},
+		{name: "contains string", body: jsonBody, assert: func(s *APIContext) error { return s.TheNodeShouldContainElement(format.JSON, "$.tags", "y") }},
+		{name: "contains object", body: jsonBody, assert: func(s *APIContext) error {
+			return s.TheNodeShouldContainElement(format.JSON, "$.users", `{"id": {{.ID}}, "name": "b", "active": true}`)
+		}},
+		{name: "does not contain", body: jsonBody, assert: func(s *APIContext) error { return s.TheNodeShouldContainElement(format.JSON, "$.tags", `"z"`) }, wantErr: true},
+		{name: "xml contains number", body: xmlBody, assert: func(s *APIContext) error { return s.TheNodeShouldContainElement(format.XML, "//n", "10") }},
+		{name: "every element", body: yamlBody, assert: func(s *APIContext) error {
+			return s.EveryElementOfTheNodeShouldHaveNodeOfValue(format.YAML, "$.users", "$.role", "admin")
+		}, wantErr: true},
+		{name: "every element matches", body: `{"items": [{"active": true}, {"active": true}]}`, assert: func(s *APIContext) error {
+			return s.EveryElementOfTheNodeShouldHaveNodeOfValue(format.JSON, "$.items", "$.active", "true")
+		}},
+		{name: "every element of empty slice", body: `{"items": []}`, assert: func(s *APIContext) error {
+			return s.EveryElementOfTheNodeShouldHaveNodeOfValue(format.JSON, "$.items", "$.active", "true")
+		}},
+		{name: "any element of empty slice", body: `{"items": []}`, assert: func(s *APIContext) error {
+			return s.AnyElementOfTheNodeShouldHaveNodeOfValue(format.JSON, "$.items", "$.active", "true")
+		}, wantErr: true},
+		{name: "any element", body: yamlBody, assert: func(s *APIContext) error {
+			return s.AnyElementOfTheNodeShouldHaveNodeOfValue(format.YAML, "$.users", "$.role", "admin")
+		}},
+		{name: "no element", body: jsonBody, assert: func(s *APIContext) error {
+			return s.AnyElementOfTheNodeShouldHaveNodeOfValue(format.JSON, "$.users", "$.id", "{{.MISSING_ID}}")
+		}, wantErr: true},
+		{name: "element missing sub-node", body: jsonBody, assert: func(s *APIContext) error {
+			return s.AnyElementOfTheNodeShouldHaveNodeOfValue(format.JSON, "$.users", "$.missing", "1")
+		}, wantErr: true},
+		{name: "every element xml", body: xmlBody, assert: func(s *APIContext) error {
+			return s.EveryElementOfTheNodeShouldHaveNodeOfValue(format.XML, "//n", "", "1")
+		}, wantErr: true},
+		{name: "sorted ascending", body: jsonBody, assert: func(s *APIContext) error { return s.TheNodeShouldBeSortedBy(format.JSON, "$.users", "$.name", "asc") }},
+		{name: "not sorted descending", body: jsonBody, assert: func(s *APIContext) error {
+			return s.TheNodeShouldBeSortedBy(format.JSON, "$.users", "$.id", collection.Descending)
+		}, wantErr: true},
+		{name: "sorted descending", body: yamlBody, assert: func(s *APIContext) error {
+			return s.TheNodeShouldBeSortedBy(format.YAML, "$.users", "$.id", collection.Descending)
+		}},
+		{name: "xml sorted numerically", body: xmlBody, assert: func(s *APIContext) error { return s.TheNodeShouldBeSortedBy(format.XML, "//n", "", "asc") }},
+		{name: "unknown order", body: xmlBody, assert: func(s *APIContext) error { return s.TheNodeShouldBeSortedBy(format.XML, "//n", "", "random") }, wantErr: true},
+		{name: "unique by", body: jsonBody, assert: func(s *APIContext) error { return s.TheNodeShouldBeUniqueBy(format.JSON, "$.users", "$.id") }},
+		{name: "not unique by", body: jsonBody, assert: func(s *APIContext) error { return s.TheNodeShouldBeUniqueBy(format.JSON, "$.users", "$.active") }, wantErr: true},
+		{name: "not unique", body: jsonBody, assert: func(s *APIContext) error { return s.TheNodeShouldBeUniqueBy(format.JSON, "$.tags", "") }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewDefaultAPIContext(false, "")
+			s.Cache.Save("ID", 2)
+			s.Cache.Save("MISSING_ID", 10)
+			s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(tt.body))})
+
+			if err := tt.assert(s); (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestState_TimeNodeAssertions(t *testing.T) {
+	reference := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+	jsonBody := `{"createdAt": "2021-05-01T09:59:30Z", "updatedAt": 1619863260, "date": "01.05.2021", "name": "abc"}`
+	xmlBody := "<item><createdAt>2021-05-01T10:00:30+00:00</createdAt></item>"
+
+	tests := []struct {
+		name    string
+		body    string
+		assert  func(s *APIContext) error
+		wantErr bool
+	}{
+		{name: "valid time", body: jsonBody, assert: func(s *APIContext) error { return s.TheNodeShouldBeTimeInLayout(format.JSON, "$.date", "02.01.2006") }},
+		{name: "invalid time", body: jsonBody, assert: func(s *APIContext) error { return s.TheNodeShouldBeTimeInLayout(format.JSON, "$.name", "RFC3339") }, wantErr: true},
+		{name: "missing node", body: jsonBody, assert: func(s *APIContext) error { return s.TheNodeShouldBeTimeInLayout(format.JSON, "$.missing", "RFC3339") }, wantErr: true},
+		{name: "before", body: jsonBody, assert: func(s *APIContext) error {
+			return s.TheNodeShouldBeTimeInLayoutBefore(format.JSON, "$.createdAt", "RFC3339", "REF")
+		}},
+		{name: "not before", body: jsonBody, assert: func(s *APIContext) error {
+			return s.TheNodeShouldBeTimeInLayoutBefore(format.JSON, "$.updatedAt", "unix", "REF")
+		}, wantErr: true},
+		{name: "after", body: xmlBody, assert: func(s *APIContext) error {
+			return s.TheNodeShouldBeTimeInLayoutAfter(format.XML, "//createdAt", "RFC3339", "REF")
+		}},
+		{name: "not after", body: jsonBody, assert: func(s *APIContext) error {
+			return s.TheNodeShouldBeTimeInLayoutAfter(format.JSON, "$.createdAt", "RFC3339", "REF")
+		}, wantErr: true},
+		{name: "missing cached time", body: jsonBody, assert: func(s *APIContext) error {
+			return s.TheNodeShouldBeTimeInLayoutAfter(format.JSON, "$.createdAt", "RFC3339", "MISSING")
+		}, wantErr: true},
+		{name: "cached value is not time", body: jsonBody, assert: func(s *APIContext) error {
+			return s.TheNodeShouldBeTimeInLayoutAfter(format.JSON, "$.createdAt", "RFC3339", "NOT_TIME")
+		}, wantErr: true},
+		{name: "within", body: jsonBody, assert: func(s *APIContext) error {
+			return s.TheNodeShouldBeTimeInLayoutWithinOf(format.JSON, "$.updatedAt", "unix", time.Minute, "REF")
+		}},
+		{name: "not within", body: jsonBody, assert: func(s *APIContext) error {
+			return s.TheNodeShouldBeTimeInLayoutWithinOf(format.JSON, "$.createdAt", "RFC3339", 10*time.Second, "REF")
+		}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewDefaultAPIContext(false, "")
+			s.Cache.Save("REF", reference)
+			s.Cache.Save("NOT_TIME", "2021-05-01")
+			s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(tt.body))})
+
+			if err := tt.assert(s); (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestState_IParseNodeAsTimeInLayoutAndSaveItAs(t *testing.T) {
+	s := NewDefaultAPIContext(false, "")
+	s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString("createdAt: 2021-05-01\n"))})
+
+	if err := s.IParseNodeAsTimeInLayoutAndSaveItAs(format.YAML, "$.createdAt", "RFC3339", "CREATED_AT"); err == nil {
+		t.Errorf("IParseNodeAsTimeInLayoutAndSaveItAs() expected error for invalid layout")
+	}
+
+	if err := s.IParseNodeAsTimeInLayoutAndSaveItAs(format.YAML, "$.createdAt", "DateOnly", "CREATED_AT"); err != nil {
+		t.Fatalf("IParseNodeAsTimeInLayoutAndSaveItAs() error = %v", err)
+	}
+
+	saved, err := s.Cache.GetSaved("CREATED_AT")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if savedTime, ok := saved.(time.Time); !ok || !savedTime.Equal(time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected saved time: %v", saved)
+	}
+}
+
+func TestState_SoftAssertions(t *testing.T) {
+	s := NewDefaultAPIContext(false, "")
+	s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(`{"name": "abc", "count": 2}`)),
+	})
+
+	if err := s.TheResponseStatusCodeShouldBe(http.StatusCreated); err == nil {
+		t.Fatalf("TheResponseStatusCodeShouldBe() should fail outside of soft assertions mode")
+	}
+
+	if err := s.IStartSoftAssertions(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.TheResponseStatusCodeShouldBe(http.StatusCreated); err != nil {
+		t.Errorf("TheResponseStatusCodeShouldBe() error should be recorded, got %v", err)
+	}
+
+	if err := s.TheNodeShouldBe(format.JSON, "$.name", "string"); err != nil {
+		t.Errorf("TheNodeShouldBe() error = %v", err)
+	}
+
+	if err := s.TheNodeShouldBe(format.JSON, "$.count", "string"); err != nil {
+		t.Errorf("TheNodeShouldBe() error should be recorded, got %v", err)
+	}
+
+	if err := s.TheNodeShouldBeGreaterThan(format.JSON, "$.count", "1"); err != nil {
+		t.Errorf("TheNodeShouldBeGreaterThan() error = %v", err)
+	}
+
+	if err := s.TheNodeShouldBeOfValue(format.JSON, "$.name", "string", "{{.missing}}"); !errors.Is(err, ErrTemplate) {
+		t.Errorf("TheNodeShouldBeOfValue() should return template error immediately, got %v", err)
+	}
+
+	if err := s.TheNodeShouldBeSliceOfLength(format.JSON, "$.missing", 1); !errors.Is(err, ErrPathFinder) {
+		t.Errorf("TheNodeShouldBeSliceOfLength() should return path finder error immediately, got %v", err)
+	}
+
+	err := s.AllSoftAssertionsShouldPass()
+	if err == nil {
+		t.Fatalf("AllSoftAssertionsShouldPass() should report recorded failures")
+	}
+
+	for _, part := range []string{"2 soft assertion(s) failed", "1) TheResponseStatusCodeShouldBe:", "2) TheNodeShouldBe: $.count"} {
+		if !strings.Contains(err.Error(), part) {
+			t.Errorf("AllSoftAssertionsShouldPass() error = %v, should contain %s", err, part)
+		}
+	}
+
+	if err = s.AllSoftAssertionsShouldPass(); err != nil {
+		t.Errorf("AllSoftAssertionsShouldPass() should clear recorded failures, got %v", err)
+	}
+
+	if err = s.TheResponseStatusCodeShouldBe(http.StatusCreated); err == nil {
+		t.Errorf("AllSoftAssertionsShouldPass() should stop soft assertions mode")
+	}
+
+	_ = s.IStartSoftAssertions()
+	_ = s.TheResponseStatusCodeShouldBe(http.StatusCreated)
+	s.ResetState(false)
+	if err = s.AllSoftAssertionsShouldPass(); err != nil {
+		t.Errorf("ResetState() should clear recorded failures, got %v", err)
+	}
+}
+
+func TestState_TheResponseShouldNotHaveNode(t *testing.T) {
+	body := `{"user": {"name": "ivo", "password": null}}`
+	tests := []struct {
+		name    string
+		body    string
+		df      format.DataFormat
+		expr    string
+		wantErr bool
+	}{
+		{name: "missing node", df: format.JSON, expr: "user.token", wantErr: false},
+		{name: "missing node by JSON path", df: format.JSON, expr: "$.user.token", wantErr: false},
+		{name: "existing node", df: format.JSON, expr: "user.name", wantErr: true},
+		{name: "existing node of null value", df: format.JSON, expr: "user.password", wantErr: true},
+		{name: "unknown format", df: "abc", expr: "user.token", wantErr: true},
+		{name: "body is not JSON", body: `<user><name>ivo</name></user>`, df: format.JSON, expr: "$.user.token", wantErr: true},
+		{name: "invalid expression", df: format.JSON, expr: "$.user[", wantErr: true},
+		{name: "invalid qjson expression", df: format.JSON, expr: "user[token", wantErr: true},
+		{name: "qjson expression of not digit index", df: format.JSON, expr: "user[a]", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewDefaultAPIContext(false, "")
+			respBody := body
+			if tt.body != "" {
+				respBody = tt.body
+			}
+			s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(respBody))})
+
+			if err := s.TheResponseShouldNotHaveNode(tt.df, tt.expr); (err != nil) != tt.wantErr {
+				t.Errorf("TheResponseShouldNotHaveNode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestState_TheResponseShouldNotHaveNodes(t *testing.T) {
+	body := `{"id": 1, "name": "ivo"}`
+	tests := []struct {
+		name        string
+		expressions string
+		wantErr     bool
+	}{
+		{name: "none of nodes exists", expressions: "password, token", wantErr: false},
+		{name: "one of nodes exists", expressions: "password, name", wantErr: true},
+		{name: "one of expressions is invalid", expressions: "password, $.name[", wantErr: true},
+		{name: "one of qjson expressions is invalid", expressions: "password, name[", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewDefaultAPIContext(false, "")
+			s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(body))})
+
+			if err := s.TheResponseShouldNotHaveNodes(format.JSON, tt.expressions); (err != nil) != tt.wantErr {
+				t.Errorf("TheResponseShouldNotHaveNodes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestState_TheNodeShouldNotBeOfValue(t *testing.T) {
+	body := `{"name": "ivo", "age": 30, "active": true}`
+	tests := []struct {
+		name      string
+		expr      string
+		dataType  string
+		dataValue string
+		wantErr   bool
+	}{
+		{name: "different string", expr: "name", dataType: "string", dataValue: "john", wantErr: false},
+		{name: "equal string", expr: "name", dataType: "string", dataValue: "ivo", wantErr: true},
+		{name: "different int", expr: "age", dataType: "int", dataValue: "31", wantErr: false},
+		{name: "equal int", expr: "age", dataType: "int", dataValue: "30", wantErr: true},
+		{name: "different type", expr: "active", dataType: "string", dataValue: "true", wantErr: false},
+		{name: "equal bool", expr: "active", dataType: "bool", dataValue: "true", wantErr: true},
+		{name: "invalid expected value", expr: "age", dataType: "int", dataValue: "abc", wantErr: true},
+		{name: "unknown data type", expr: "age", dataType: "abc", dataValue: "30", wantErr: true},
+		{name: "missing node", expr: "surname", dataType: "string", dataValue: "ivo", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewDefaultAPIContext(false, "")
+			s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(body))})
+
+			if err := s.TheNodeShouldNotBeOfValue(format.JSON, tt.expr, tt.dataType, tt.dataValue); (err != nil) != tt.wantErr {
+				t.Errorf("TheNodeShouldNotBeOfValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestState_TheNodeShouldNotMatchRegExp(t *testing.T) {
+	body := `{"email": "ivo@example.com"}`
+	tests := []struct {
+		name    string
+		expr    string
+		regExp  string
+		wantErr bool
+	}{
+		{name: "not matching", expr: "email", regExp: `^"\d+"$`, wantErr: false},
+		{name: "matching", expr: "email", regExp: `@example\.com`, wantErr: true},
+		{name: "invalid regExp", expr: "email", regExp: `(`, wantErr: true},
+		{name: "missing node", expr: "phone", regExp: `\d+`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewDefaultAPIContext(false, "")
+			s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(body))})
+
+			if err := s.TheNodeShouldNotMatchRegExp(format.JSON, tt.expr, tt.regExp); (err != nil) != tt.wantErr {
+				t.Errorf("TheNodeShouldNotMatchRegExp() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestState_TheResponseShouldNotHaveHeaderOfValue(t *testing.T) {
+	resp := &http.Response{Header: map[string][]string{"Vary": {"Accept", "Origin"}}}
+	tests := []struct {
+		name    string
+		header  string
+		value   string
+		wantErr bool
+	}{
+		{name: "missing header", header: "Server", value: "nginx", wantErr: false},
+		{name: "different value", header: "Vary", value: "Cookie", wantErr: false},
+		{name: "one of values is equal", header: "vary", value: "Origin", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewDefaultAPIContext(false, "")
+			s.Cache.Save(httpcache.LastHTTPResponseCacheKey, resp)
+
+			if err := s.TheResponseShouldNotHaveHeaderOfValue(tt.header, tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("TheResponseShouldNotHaveHeaderOfValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	s := NewDefaultAPIContext(false, "")
+	s.Cache.Save(httpcache.LastHTTPResponseCacheKey, resp)
+	if err := s.TheResponseShouldNotHaveHeader("Server"); err != nil {
+		t.Errorf("TheResponseShouldNotHaveHeader() unexpected error = %v", err)
+	}
+
+	if err := s.TheResponseShouldNotHaveHeader("Vary"); err == nil {
+		t.Errorf("TheResponseShouldNotHaveHeader() expected error")
+	}
+}
+
+func TestState_TheResponseShouldNotHaveCookieOfValue(t *testing.T) {
+	resp := &http.Response{Header: map[string][]string{"Set-Cookie": {"session=abc; HttpOnly"}}}
+	tests := []struct {
+		name    string
+		cookie  string
+		value   string
+		wantErr bool
+	}{
+		{name: "missing cookie", cookie: "token", value: "abc", wantErr: false},
+		{name: "different value", cookie: "session", value: "xyz", wantErr: false},
+		{name: "equal value", cookie: "session", value: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewDefaultAPIContext(false, "")
+			s.Cache.Save(httpcache.LastHTTPResponseCacheKey, resp)
+
+			if err := s.TheResponseShouldNotHaveCookieOfValue(tt.cookie, tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("TheResponseShouldNotHaveCookieOfValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	s := NewDefaultAPIContext(false, "")
+	s.Cache.Save(httpcache.LastHTTPResponseCacheKey, resp)
+	if err := s.TheResponseShouldNotHaveCookie("token"); err != nil {
+		t.Errorf("TheResponseShouldNotHaveCookie() unexpected error = %v", err)
+	}
+
+	if err := s.TheResponseShouldNotHaveCookie("session"); err == nil {
+		t.Errorf("TheResponseShouldNotHaveCookie() expected error")
+	}
+
+	s.Cache.Save(httpcache.LastHTTPResponseCacheKey, nil)
+	if err := s.TheResponseShouldNotHaveCookie("token"); err == nil {
+		t.Errorf("TheResponseShouldNotHaveCookie() expected error for missing last response")
+	}
+}
+
+func TestState_ResponseHeaderAssertions(t *testing.T) {
+	resp := &http.Response{Header: map[string][]string{
+		"Content-Type":  {"application/json; charset=UTF-8"},
+		"Vary":          {"Accept, Origin", "Cookie"},
+		"Cache-Control": {`max-age=3600, no-cache="Set-Cookie"`, "private"},
+		"X-Legacy":      {"No-Cache, Max-Age=1"},
+		"X-Structured":  {"a;q=0.5, b=(1 2)"},
+	}}
+	legacy := &http.Response{Header: map[string][]string{"Cache-Control": {"No-Cache, Max-Age=1"}}}
+	tests := []struct {
+		name    string
+		resp    *http.Response
+		assert  func(s *APIContext) error
+		wantErr bool
+	}{
+		{name: "header matches regExp", assert: func(s *APIContext) error {
+			return s.TheResponseHeaderShouldMatchRegExp("Vary", `^Accept, Origin, Cookie$`)
+		}},
+		{name: "header does not match regExp", assert: func(s *APIContext) error {
+			return s.TheResponseHeaderShouldMatchRegExp("Vary", `Referer`)
+		}, wantErr: true},
+		{name: "missing header does not match regExp", assert: func(s *APIContext) error {
+			return s.TheResponseHeaderShouldMatchRegExp("Server", `.*`)
+		}, wantErr: true},
+		{name: "header contains value", assert: func(s *APIContext) error {
+			return s.TheResponseHeaderShouldContainValue("vary", "Origin")
+		}},
+		{name: "header contains whole line", assert: func(s *APIContext) error {
+			return s.TheResponseHeaderShouldContainValue("Vary", "Accept, Origin")
+		}},
+		{name: "header does not contain value", assert: func(s *APIContext) error {
+			return s.TheResponseHeaderShouldContainValue("Vary", "Referer")
+		}, wantErr: true},
+		{name: "content type matches", assert: func(s *APIContext) error {
+			return s.TheResponseContentTypeShouldBe("application/json;charset=utf-8")
+		}},
+		{name: "content type does not match", assert: func(s *APIContext) error {
+			return s.TheResponseContentTypeShouldBe("application/xml")
+		}, wantErr: true},
+		{name: "header has directive", assert: func(s *APIContext) error {
+			return s.TheResponseHeaderShouldHaveDirective("Cache-Control", "private")
+		}},
+		{name: "header does not have directive", assert: func(s *APIContext) error {
+			return s.TheResponseHeaderShouldHaveDirective("Cache-Control", "no-store")
+		}, wantErr: true},
+		{name: "directive of integer value", assert: func(s *APIContext) error {
+			return s.TheResponseHeaderDirectiveShouldBeOfValue("Cache-Control", "max-age", "3600")
+		}},
+		{name: "directive of string value", assert: func(s *APIContext) error {
+			return s.TheResponseHeaderDirectiveShouldBeOfValue("Cache-Control", "no-cache", "Set-Cookie")
+		}},
+		{name: "directive of different value", assert: func(s *APIContext) error {
+			return s.TheResponseHeaderDirectiveShouldBeOfValue("Cache-Control", "max-age", "60")
+		}, wantErr: true},
+		{name: "directive with parameters", assert: func(s *APIContext) error {
+			return s.TheResponseHeaderShouldHaveDirective("X-Structured", "a")
+		}},
+		{name: "directive of inner list value", assert: func(s *APIContext) error {
+			return s.TheResponseHeaderDirectiveShouldBeOfValue("X-Structured", "b", "(1 2)")
+		}},
+		{name: "header is not structured field", assert: func(s *APIContext) error {
+			return s.TheResponseHeaderShouldHaveDirective("X-Legacy", "no-cache")
+		}, wantErr: true},
+		{name: "cache control directive names are case-insensitive", resp: legacy, assert: func(s *APIContext) error {
+			return s.TheResponseHeaderShouldHaveDirective("Cache-Control", "no-cache")
+		}},
+		{name: "cache control directive of mixed case name", resp: legacy, assert: func(s *APIContext) error {
+			return s.TheResponseHeaderDirectiveShouldBeOfValue("Cache-Control", "max-age", "1")
+		}},
+		{name: "directive of missing header", assert: func(s *APIContext) error {
+			return s.TheResponseHeaderShouldHaveDirective("Surrogate-Control", "max-age")
+		}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewDefaultAPIContext(false, "")
+			if tt.resp != nil {
+				s.Cache.Save(httpcache.LastHTTPResponseCacheKey, tt.resp)
+			} else {
+				s.Cache.Save(httpcache.LastHTTPResponseCacheKey, resp)
+			}
+
+			if err := tt.assert(s); (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestState_ResponseCookieAttributeAssertions(t *testing.T) {
+	resp := &http.Response{Header: map[string][]string{"Set-Cookie": {
+		"sid=abc; Path=/; Secure; HttpOnly; SameSite=Lax; Max-Age=3600",
+		"theme=dark; Path=/app; SameSite=None",
+		"tmp=1",
+	}}}
+	tests := []struct {
+		name    string
+		assert  func(s *APIContext) error
+		wantErr bool
+	}{
+		{name: "secure flag", assert: func(s *APIContext) error {
+			return s.TheResponseCookieShouldHaveAttributeOfValue("sid", "secure", "true")
+		}},
+		{name: "missing secure flag", assert: func(s *APIContext) error {
+			return s.TheResponseCookieShouldHaveAttributeOfValue("theme", httpctx.CookieAttributeSecure, "true")
+		}, wantErr: true},
+		{name: "same site", assert: func(s *APIContext) error {
+			return s.TheResponseCookieShouldHaveAttributeOfValue("theme", httpctx.CookieAttributeSameSite, "none")
+		}},
+		{name: "path", assert: func(s *APIContext) error {
+			return s.TheResponseCookieShouldHaveAttributeOfValue("theme", httpctx.CookieAttributePath, "/")
+		}, wantErr: true},
+		{name: "unknown attribute", assert: func(s *APIContext) error {
+			return s.TheResponseCookieShouldHaveAttributeOfValue("sid", "Priority", "High")
+		}, wantErr: true},
+		{name: "missing cookie", assert: func(s *APIContext) error {
+			return s.TheResponseCookieShouldHaveAttributeOfValue("token", httpctx.CookieAttributeSecure, "true")
+		}, wantErr: true},
+		{name: "expires after", assert: func(s *APIContext) error {
+			return s.TheResponseCookieShouldExpireAfter("sid", 30*time.Minute)
+		}},
+		{name: "does not expire after", assert: func(s *APIContext) error {
+			return s.TheResponseCookieShouldExpireAfter("sid", 2*time.Hour)
+		}, wantErr: true},
+		{name: "expires before", assert: func(s *APIContext) error {
+			return s.TheResponseCookieShouldExpireBefore("sid", 2*time.Hour)
+		}},
+		{name: "session cookie does not expire", assert: func(s *APIContext) error {
+			return s.TheResponseCookieShouldExpireBefore("tmp", 2*time.Hour)
+		}, wantErr: true},
+		{name: "hardened cookie", assert: func(s *APIContext) error {
+			return s.TheResponseSessionCookieShouldBeHardened("sid")
+		}},
+		{name: "not hardened cookie", assert: func(s *APIContext) error {
+			return s.TheResponseSessionCookieShouldBeHardened("theme")
+		}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewDefaultAPIContext(false, "")
+			s.Cache.Save(httpcache.LastHTTPResponseCacheKey, resp)
+
+			if err := tt.assert(s); (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestState_ResponseStatusCodeAssertions(t *testing.T) {
+	tests := []struct {
+		name    string
+		assert  func(s *APIContext) error
+		wantErr bool
+	}{
+		{name: "of class", assert: func(s *APIContext) error { return s.TheResponseStatusCodeShouldBeOfClass("4XX") }},
+		{name: "not of class", assert: func(s *APIContext) error { return s.TheResponseStatusCodeShouldBeOfClass("2xx") }, wantErr: true},
+		{name: "invalid class", assert: func(s *APIContext) error { return s.TheResponseStatusCodeShouldBeOfClass("6xx") }, wantErr: true},
+		{name: "one of", assert: func(s *APIContext) error { return s.TheResponseStatusCodeShouldBeOneOf("400, 404") }},
+		{name: "not one of", assert: func(s *APIContext) error { return s.TheResponseStatusCodeShouldBeOneOf("200,204") }, wantErr: true},
+		{name: "invalid code", assert: func(s *APIContext) error { return s.TheResponseStatusCodeShouldBeOneOf("200,abc") }, wantErr: true},
+		{name: "between", assert: func(s *APIContext) error { return s.TheResponseStatusCodeShouldBeBetween(400, 404) }},
+		{name: "not between", assert: func(s *APIContext) error { return s.TheResponseStatusCodeShouldBeBetween(200, 299) }, wantErr: true},
+		{name: "invalid range", assert: func(s *APIContext) error { return s.TheResponseStatusCodeShouldBeBetween(499, 400) }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewDefaultAPIContext(false, "")
+			s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{StatusCode: http.StatusNotFound})
+
+			if err := tt.assert(s); (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestState_TheResponseStatusCodeShouldBe_errorMessage(t *testing.T) {
+	s := NewDefaultAPIContext(false, "")
+	s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{
+		StatusCode: http.StatusUnprocessableEntity,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(`{"error": "name is required"}`)),
+	})
+
+	err := s.TheResponseStatusCodeShouldBeOfClass("2xx")
+	if err == nil || err.Error() != "expected status code of class 2xx, but got 422 Unprocessable Entity" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	s.SetStatusCodeBodyExcerptLength(10)
+	err = s.TheResponseStatusCodeShouldBe(200)
+	if err == nil || err.Error() != `expected status code 200, but got 422 Unprocessable Entity, response body: {"error": ...` {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestState_TheResponseShouldSatisfyExpression(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    bool
+	}{
+		{name: "satisfied", expression: `all(body.items, {.price > 0}) && status == 200`, wantErr: false},
+		{name: "uses cache", expression: `body.items[0].id == cache.ITEM_ID`, wantErr: false},
+		{name: "uses template", expression: `body.items[0].id == {{.ITEM_ID}}`, wantErr: false},
+		{name: "not satisfied", expression: `any(body.items, {.price > 100})`, wantErr: true},
+		{name: "not boolean", expression: `status`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewDefaultAPIContext(false, "")
+			s.Cache.Save("ITEM_ID", 1)
+			s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{"items": [{"id": 1, "price": 10}, {"id": 2, "price": 20}]}`)),
+			})
+
+			if err := s.TheResponseShouldSatisfyExpression(tt.expression); (err != nil) != tt.wantErr {
+				t.Errorf("TheResponseShouldSatisfyExpression() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestState_assertionErrorsContainDiff(t *testing.T) {
+	s := NewDefaultAPIContext(false, "")
+	s.SetDiffer(diff.New(false))
+	s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{
+		Body: ioutil.NopCloser(bytes.NewBufferString(`{"bio": "line 1\nline 2", "items": [{"id": 1}, {"id": 2}]}`)),
+	})
+
+	err := s.TheNodeShouldBeOfValue(format.JSON, "bio", "string", "line 1\nline 3")
+	if err == nil || !strings.Contains(err.Error(), "-line 3\n+line 2") {
+		t.Errorf("TheNodeShouldBeOfValue() error should contain unified diff, got: %v", err)
+	}
+
+	err = s.EveryElementOfTheNodeShouldHaveNodeOfValue(format.JSON, "$.items", "$.id", "1")
+	if err == nil || !strings.Contains(err.Error(), "[1]:\n$: expected number 1, got number 2") {
+		t.Errorf("EveryElementOfTheNodeShouldHaveNodeOfValue() error should contain diff, got: %v", err)
+	}
+}
+
+func TestState_IValidateWithXSD(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	schema := `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:shop" elementFormDefault="qualified">
+	<xs:element name="order">
+		<xs:complexType>
+			<xs:sequence><xs:element name="quantity" type="xs:positiveInteger"/></xs:sequence>
+		</xs:complexType>
+	</xs:element>
+</xs:schema>`
+	if err = ioutil.WriteFile(filepath.Join(dir, "order.xsd"), []byte(schema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	body := `<s:Envelope xmlns:s="urn:soap" xmlns="urn:shop"><s:Body><order><quantity>{{.quantity}}</quantity></order></s:Body></s:Envelope>`
+	tests := []struct {
+		name     string
+		quantity string
+		assert   func(s *APIContext) error
+		wantErr  bool
+	}{
+		{name: "body with reference", quantity: "1", assert: func(s *APIContext) error {
+			return s.IValidateLastResponseBodyWithXSDReference("order.xsd")
+		}, wantErr: true},
+		{name: "node with reference", quantity: "1", assert: func(s *APIContext) error {
+			return s.IValidateXMLNodeWithXSDReference("//s:Body/*", "{{.schema}}")
+		}},
+		{name: "invalid node with reference", quantity: "0", assert: func(s *APIContext) error {
+			return s.IValidateXMLNodeWithXSDReference("//s:Body/*", "order.xsd")
+		}, wantErr: true},
+		{name: "node with string", quantity: "2", assert: func(s *APIContext) error {
+			return s.IValidateXMLNodeWithXSDString("//s:Body/*", schema)
+		}},
+		{name: "not existing node", quantity: "2", assert: func(s *APIContext) error {
+			return s.IValidateXMLNodeWithXSDString("//s:Header", schema)
+		}, wantErr: true},
+		{name: "body with string", quantity: "2", assert: func(s *APIContext) error {
+			return s.IValidateLastResponseBodyWithXSDString(schema)
+		}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewDefaultAPIContext(false, dir)
+			s.Cache.Save("schema", "order.xsd")
+			s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{
+				Body: ioutil.NopCloser(bytes.NewBufferString(strings.Replace(body, "{{.quantity}}", tt.quantity, 1))),
+			})
+
+			if err := tt.assert(s); (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	s := NewDefaultAPIContext(false, dir)
+	s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`<order xmlns="urn:shop"><quantity>-1</quantity></order>`))})
+
+	err = s.IValidateLastResponseBodyWithXSDReference("order.xsd")
+	if err == nil || !strings.Contains(err.Error(), "/order/quantity: value '-1' is out of range of positiveInteger") {
+		t.Errorf("IValidateLastResponseBodyWithXSDReference() error = %v, should point at invalid node", err)
+	}
+}
+
+func TestState_IValidateWithSchema_convertedFormats(t *testing.T) {
+	schema := `{"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}, "age": {"type": "integer", "minimum": 0}}}`
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		assert      func(s *APIContext) error
+		wantErr     string
+	}{
+		{name: "valid YAML body", body: "name: ivo\nage: 30", assert: func(s *APIContext) error {
+			return s.IValidateLastResponseBodyWithSchemaString(schema)
+		}},
+		{name: "invalid YAML body", body: "name: ivo\nage: -1", assert: func(s *APIContext) error {
+			return s.IValidateLastResponseBodyWithSchemaString(schema)
+		}, wantErr: "YAML body does not match schema:\n#/age: must be >= 0 but found -1\n\tkeyword: #/properties/age/minimum\n\texpected: 0\n\tactual: -1"},
+		{name: "malformed JSON body", body: `{"name": "ivo",}`, assert: func(s *APIContext) error {
+			return s.IValidateLastResponseBodyWithSchemaString(schema)
+		}, wantErr: "could not convert last HTTP(s) response body to JSON, err: invalid JSON document, err: invalid character '}' looking for beginning of object key string"},
+		{name: "YAML flow mapping body", body: `{name: ivo, age: 30}`, contentType: "application/yaml", assert: func(s *APIContext) error {
+			return s.IValidateLastResponseBodyWithSchemaString(schema)
+		}},
+		{name: "invalid XML body", body: `<user><age>1</age></user>`, assert: func(s *APIContext) error {
+			return s.IValidateLastResponseBodyWithSchemaString(schema)
+		}, wantErr: "XML body does not match schema:\n#: missing properties: 'name'\n\tkeyword: #/required\n\texpected: [\"name\"]\n\tactual: object"},
+		{name: "valid XML node", body: `<users><user><name>ivo</name><age>1</age></user></users>`, assert: func(s *APIContext) error {
+			return s.IValidateNodeWithSchemaString(format.XML, "//user", schema)
+		}},
+		{name: "invalid XML node", body: `<users><user><name>ivo</name><age>1.5</age></user></users>`, assert: func(s *APIContext) error {
+			return s.IValidateNodeWithSchemaString(format.XML, "//user", schema)
+		}, wantErr: "XML node '//user' does not match schema:\n#/age: expected integer, but got number\n\tkeyword: #/properties/age/type\n\texpected: \"integer\"\n\tactual: 1.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewDefaultAPIContext(false, "")
+			s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{
+				Header: http.Header{"Content-Type": []string{tt.contentType}},
+				Body:   ioutil.NopCloser(bytes.NewBufferString(tt.body)),
+			})
+
+			err := tt.assert(s)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("error = %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// recordingDebugger is debugger, turned on, that records printed info.
+type recordingDebugger struct {
+	debugger.Debugger
+	printed []string
+}
+
+func (d *recordingDebugger) IsOn() bool {
+	return true
+}
+
+func (d *recordingDebugger) Print(info string) {
+	d.printed = append(d.printed, info)
+}
+
+func TestState_IValidateWithSchema_violations(t *testing.T) {
+	s := NewDefaultAPIContext(false, "")
+	d := &recordingDebugger{}
+	s.SetDebugger(d)
+	s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{
+		Body: ioutil.NopCloser(bytes.NewBufferString(`{"items": [{"price": 1}, {"price": -1, "name": "pen"}]}`)),
+	})
+
+	err := s.IValidateLastResponseBodyWithSchemaString(`{"properties": {"items": {"items": {"properties": {"price": {"minimum": 0}}}}}}`)
+
+	var validationErr *schema.ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Violations) != 1 {
+		t.Fatalf("error = %v, want *schema.ValidationError with 1 violation", err)
+	}
+
+	want := schema.Violation{
+		InstancePath:   "/items/1/price",
+		KeywordPath:    "/properties/items/items/properties/price/minimum",
+		SchemaLocation: "memory:///schema.json#/properties/items/items/properties/price/minimum",
+		Expected:       "0",
+		Actual:         "-1",
+		Message:        "must be >= 0 but found -1",
+		Excerpt:        "-1",
+	}
+	if validationErr.Violations[0] != want {
+		t.Errorf("violation = %+v, want %+v", validationErr.Violations[0], want)
+	}
+
+	if len(d.printed) == 0 || d.printed[len(d.printed)-1] != "node #/items/1/price violating schema:\n\n-1" {
+		t.Errorf("debugger printed %q, want excerpt of invalid node", d.printed)
+	}
+}
+
+func TestState_IGenerateJSONSchema(t *testing.T) {
+	dir := t.TempDir()
+	s := NewDefaultAPIContext(false, dir)
+	s.Cache.Save("first", &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`{"id": 1, "status": "new", "email": "ivo@example.com"}`))})
+	s.Cache.Save("second", "id: 2\nstatus: new\nnote: fragile")
+	s.Cache.Save("third", map[string]interface{}{"id": 3, "status": "new"})
+	s.Cache.Save("schemaName", "orders/order.json")
+
+	if err := s.IGenerateJSONSchemaFromSavedResponsesAndWriteItTo("first, second,third", "{{.schemaName}}"); err != nil {
+		t.Fatalf("IGenerateJSONSchemaFromSavedResponsesAndWriteItTo() error = %v", err)
+	}
+
+	inferred, err := ioutil.ReadFile(filepath.Join(dir, "orders", "order.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{`"required": [
+    "id",
+    "status"
+  ]`, `"type": "integer"`} {
+		if !strings.Contains(string(inferred), want) {
+			t.Errorf("inferred schema:\n%s\nshould contain %s", inferred, want)
+		}
+	}
+
+	s.Cache.Save(httpcache.LastHTTPResponseCacheKey, &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`{"id": "4", "status": "paid"}`))})
+	if err = s.IValidateLastResponseBodyWithSchemaReference("orders/order.json"); err == nil {
+		t.Errorf("last response body should not match inferred schema")
+	}
+
+	if err = s.IGenerateJSONSchemaFromLastResponseBodyAndWriteItTo("last.json"); err != nil {
+		t.Fatalf("IGenerateJSONSchemaFromLastResponseBodyAndWriteItTo() error = %v", err)
+	}
+
+	if err = s.IValidateLastResponseBodyWithSchemaReference("last.json"); err != nil {
+		t.Errorf("last response body should match schema inferred from it, err: %v", err)
+	}
+
+	for _, fileName := range []string{"../outside.json", filepath.Join(dir, "absolute.json"), "."} {
+		if err = s.IGenerateJSONSchemaFromLastResponseBodyAndWriteItTo(fileName); err == nil {
+			t.Errorf("IGenerateJSONSchemaFromLastResponseBodyAndWriteItTo(%s) should return error for file outside JSON schemas directory", fileName)
+		}
+	}
+
+	if err = s.IGenerateJSONSchemaFromSavedResponsesAndWriteItTo("missing", "missing.json"); err == nil {
+		t.Errorf("IGenerateJSONSchemaFromSavedResponsesAndWriteItTo() should return error for missing cache key")
+	}
+
+	s.SetJSONSchemaDir("")
+	if err = s.IGenerateJSONSchemaFromLastResponseBodyAndWriteItTo("last.json"); err == nil {
+		t.Errorf("IGenerateJSONSchemaFromLastResponseBodyAndWriteItTo() should return error without JSON schemas directory")
+	}
+}